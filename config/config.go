@@ -2,10 +2,12 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/ab22/env"
+	"github.com/ab22/stormrage/logger"
 )
 
 // Config struct that contains all of the configuration variables
@@ -17,6 +19,13 @@ type Config struct {
 	SessionCookieName string
 	SessionLifeTime   time.Duration
 
+	// AllowedOriginsList is a comma separated list of origins
+	// (scheme://host[:port]) that are allowed to send state changing
+	// requests and to open websocket connections, besides the server's own
+	// origin. It is parsed into AllowedOrigins.
+	AllowedOriginsList string `env:"ALLOWED_ORIGINS"`
+	AllowedOrigins     []string
+
 	DB struct {
 		Host     string `env:"DB_HOST" envDefault:"localhost"`
 		Port     int    `env:"DB_PORT" envDefault:"5432"`
@@ -26,11 +35,131 @@ type Config struct {
 		LogMode  bool   `env:"DB_LOG_MODE" envDefault:"False"`
 	}
 
+	Log struct {
+		Level  string `env:"LOG_LEVEL" envDefault:"info"`
+		Format string `env:"LOG_FORMAT" envDefault:"text"`
+	}
+
 	PrivateRouter struct {
 		Address  string `env:"PR_ADDR"`
 		Port     string `env:"PR_PORT" envDefault:"8728"`
 		User     string `env:"PR_USER"`
 		Password string `env:"PR_PASS"`
+
+		// SuspendedAddressList is the firewall address list that contains
+		// the IPs of suspended clients. The router's firewall must drop
+		// the traffic of the addresses in this list.
+		SuspendedAddressList string `env:"PR_SUSPENDED_LIST" envDefault:"morosos"`
+
+		// StatusCacheSeconds is the time the router's status document is
+		// cached before querying the router again.
+		StatusCacheSeconds int `env:"PR_STATUS_CACHE" envDefault:"10"`
+	}
+
+	Backup struct {
+		// IntervalHours is the time between scheduled configuration
+		// backups of the router. Scheduled backups are disabled if it's 0.
+		IntervalHours int `env:"BACKUP_INTERVAL_HOURS" envDefault:"24"`
+
+		// Retention is the number of backups kept. Older backups are
+		// deleted every time a new backup is stored.
+		Retention int `env:"BACKUP_RETENTION" envDefault:"30"`
+	}
+
+	Monitor struct {
+		// IntervalSeconds is the time between the probes of every client's
+		// address. Monitoring is disabled if it's 0.
+		IntervalSeconds int `env:"MONITOR_INTERVAL" envDefault:"300"`
+
+		// PingCount is the number of ICMP echo requests sent on each probe.
+		// A client is down if it doesn't reply to any of them.
+		PingCount int `env:"MONITOR_PING_COUNT" envDefault:"2"`
+
+		// RetentionDays is the number of days probes and outages are kept.
+		RetentionDays int `env:"MONITOR_RETENTION_DAYS" envDefault:"30"`
+	}
+
+	Alert struct {
+		// IntervalSeconds is the time between evaluations of the alert
+		// rules. Alerting is disabled if it's 0.
+		IntervalSeconds int `env:"ALERT_INTERVAL" envDefault:"60"`
+
+		// CPUThreshold is the router's CPU load percentage at which the
+		// high CPU alert fires.
+		CPUThreshold int `env:"ALERT_CPU_THRESHOLD" envDefault:"90"`
+
+		// Email notifications are sent if SMTPHost is set. EmailToList is
+		// a comma separated list of recipients, parsed into EmailTo.
+		SMTPHost     string `env:"ALERT_SMTP_HOST"`
+		SMTPPort     int    `env:"ALERT_SMTP_PORT" envDefault:"587"`
+		SMTPUser     string `env:"ALERT_SMTP_USER"`
+		SMTPPassword string `env:"ALERT_SMTP_PASS"`
+		EmailFrom    string `env:"ALERT_EMAIL_FROM"`
+		EmailToList  string `env:"ALERT_EMAIL_TO"`
+		EmailTo      []string
+
+		// WebhookURL receives every notification as a JSON POST request.
+		WebhookURL string `env:"ALERT_WEBHOOK_URL"`
+
+		// Telegram notifications are sent through the bot API if
+		// TelegramToken is set.
+		TelegramToken  string `env:"ALERT_TELEGRAM_TOKEN"`
+		TelegramChatID string `env:"ALERT_TELEGRAM_CHAT_ID"`
+		TelegramAPIURL string `env:"ALERT_TELEGRAM_API_URL" envDefault:"https://api.telegram.org"`
+	}
+
+	Traffic struct {
+		// IntervalSeconds is the time between samples of the simple queue
+		// counters. Traffic accounting is disabled if it's 0.
+		IntervalSeconds int `env:"TRAFFIC_INTERVAL" envDefault:"60"`
+
+		// Days the 5-minute, hourly and daily samples are kept.
+		FiveMinuteRetentionDays int `env:"TRAFFIC_RETENTION_5M_DAYS" envDefault:"7"`
+		HourlyRetentionDays     int `env:"TRAFFIC_RETENTION_HOURLY_DAYS" envDefault:"90"`
+		DailyRetentionDays      int `env:"TRAFFIC_RETENTION_DAILY_DAYS" envDefault:"730"`
+	}
+
+	Quota struct {
+		// IntervalSeconds is the time between checks of the clients' data
+		// caps. Clients are not throttled if it's 0.
+		IntervalSeconds int `env:"QUOTA_INTERVAL" envDefault:"300"`
+	}
+
+	Schedule struct {
+		// IntervalSeconds is the time between checks of the clients'
+		// bandwidth schedules. Schedules are not applied if it's 0.
+		IntervalSeconds int `env:"SCHEDULE_INTERVAL" envDefault:"60"`
+	}
+
+	Billing struct {
+		// IntervalHours is the time between runs of the billing job, which
+		// issues the monthly invoices and suspends the overdue accounts.
+		// The job is disabled if it's 0.
+		IntervalHours int `env:"BILLING_INTERVAL_HOURS" envDefault:"24"`
+
+		// DueDays is the number of days after an invoice is issued until
+		// it's due.
+		DueDays int `env:"BILLING_DUE_DAYS" envDefault:"10"`
+
+		// GraceDays is the number of days an invoice can be overdue before
+		// the client is suspended.
+		GraceDays int `env:"BILLING_GRACE_DAYS" envDefault:"5"`
+
+		// Currency is the ISO 4217 code of the amounts.
+		Currency string `env:"BILLING_CURRENCY" envDefault:"USD"`
+	}
+
+	Document struct {
+		// LogoPath is the PNG image printed on the header of the invoices
+		// and receipts. They are printed without a logo if it can't be
+		// read.
+		LogoPath string `env:"DOCUMENT_LOGO_PATH" envDefault:"frontend/abemar-mikrotik/app/static/images/logo_full_medium.png"`
+
+		// The company's details printed on the header.
+		CompanyName    string `env:"DOCUMENT_COMPANY_NAME" envDefault:"Abemar"`
+		CompanyAddress string `env:"DOCUMENT_COMPANY_ADDRESS"`
+		CompanyPhone   string `env:"DOCUMENT_COMPANY_PHONE"`
+		CompanyTaxID   string `env:"DOCUMENT_COMPANY_TAX_ID"`
 	}
 }
 
@@ -45,6 +174,9 @@ func New() (*Config, error) {
 		return nil, err
 	}
 
+	cfg.AllowedOrigins = parseList(cfg.AllowedOriginsList)
+	cfg.Alert.EmailTo = parseList(cfg.Alert.EmailToList)
+
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
@@ -52,6 +184,21 @@ func New() (*Config, error) {
 	return cfg, nil
 }
 
+// parseList splits a comma separated list and removes empty items.
+func parseList(list string) []string {
+	var items []string
+
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
 // Validate checks if the most important fields are set and are not empty
 // values.
 func (c *Config) Validate() error {
@@ -83,6 +230,23 @@ func (c *Config) Validate() error {
 		return fmt.Errorf(errorMsg, "DB.Name")
 	}
 
+	for _, origin := range c.AllowedOrigins {
+		u, err := url.Parse(origin)
+
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("config: allowed origin [%s] must have the form scheme://host[:port]", origin)
+		}
+	}
+
+	// Log validation.
+	if _, err := logger.ParseLevel(c.Log.Level); err != nil {
+		return fmt.Errorf("config: field [Log.Level]: %v", err)
+	}
+
+	if c.Log.Format != logger.TextFormat && c.Log.Format != logger.JSONFormat {
+		return fmt.Errorf("config: field [Log.Format] must be [%s] or [%s]", logger.TextFormat, logger.JSONFormat)
+	}
+
 	// Private Router config validation.
 	if c.PrivateRouter.Address == "" {
 		return fmt.Errorf(errorMsg, "PrivateRouter.Address")
@@ -100,20 +264,149 @@ func (c *Config) Validate() error {
 		return fmt.Errorf(errorMsg, "PrivateRouter.Password")
 	}
 
+	if c.PrivateRouter.SuspendedAddressList == "" {
+		return fmt.Errorf(errorMsg, "PrivateRouter.SuspendedAddressList")
+	}
+
+	// Backup validation.
+	if c.Backup.IntervalHours < 0 {
+		return fmt.Errorf("config: field [Backup.IntervalHours] must not be negative")
+	}
+
+	if c.Backup.Retention < 1 {
+		return fmt.Errorf("config: field [Backup.Retention] must be at least 1")
+	}
+
+	// Monitor validation.
+	if c.Monitor.IntervalSeconds < 0 {
+		return fmt.Errorf("config: field [Monitor.IntervalSeconds] must not be negative")
+	}
+
+	if c.Monitor.PingCount < 1 {
+		return fmt.Errorf("config: field [Monitor.PingCount] must be at least 1")
+	}
+
+	if c.Monitor.RetentionDays < 1 {
+		return fmt.Errorf("config: field [Monitor.RetentionDays] must be at least 1")
+	}
+
+	// Alert validation.
+	if c.Alert.IntervalSeconds < 0 {
+		return fmt.Errorf("config: field [Alert.IntervalSeconds] must not be negative")
+	}
+
+	if c.Alert.CPUThreshold < 1 || c.Alert.CPUThreshold > 100 {
+		return fmt.Errorf("config: field [Alert.CPUThreshold] must be between 1 and 100")
+	}
+
+	if c.Alert.SMTPHost != "" {
+		if c.Alert.EmailFrom == "" {
+			return fmt.Errorf(errorMsg, "Alert.EmailFrom")
+		}
+
+		if len(c.Alert.EmailTo) == 0 {
+			return fmt.Errorf(errorMsg, "Alert.EmailTo")
+		}
+	}
+
+	if c.Alert.WebhookURL != "" {
+		u, err := url.Parse(c.Alert.WebhookURL)
+
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("config: field [Alert.WebhookURL] must be an http or https URL")
+		}
+	}
+
+	if c.Alert.TelegramToken != "" && c.Alert.TelegramChatID == "" {
+		return fmt.Errorf(errorMsg, "Alert.TelegramChatID")
+	}
+
+	// Traffic validation.
+	if c.Traffic.IntervalSeconds < 0 || c.Traffic.IntervalSeconds > 300 {
+		return fmt.Errorf("config: field [Traffic.IntervalSeconds] must be between 0 and 300")
+	}
+
+	if c.Traffic.FiveMinuteRetentionDays < 1 {
+		return fmt.Errorf("config: field [Traffic.FiveMinuteRetentionDays] must be at least 1")
+	}
+
+	if c.Traffic.HourlyRetentionDays < c.Traffic.FiveMinuteRetentionDays {
+		return fmt.Errorf("config: field [Traffic.HourlyRetentionDays] must be at least [Traffic.FiveMinuteRetentionDays]")
+	}
+
+	if c.Traffic.DailyRetentionDays < c.Traffic.HourlyRetentionDays {
+		return fmt.Errorf("config: field [Traffic.DailyRetentionDays] must be at least [Traffic.HourlyRetentionDays]")
+	}
+
+	// Quota validation.
+	if c.Quota.IntervalSeconds < 0 {
+		return fmt.Errorf("config: field [Quota.IntervalSeconds] must not be negative")
+	}
+
+	// Schedule validation.
+	if c.Schedule.IntervalSeconds < 0 {
+		return fmt.Errorf("config: field [Schedule.IntervalSeconds] must not be negative")
+	}
+
+	// Billing validation.
+	if c.Billing.IntervalHours < 0 {
+		return fmt.Errorf("config: field [Billing.IntervalHours] must not be negative")
+	}
+
+	if c.Billing.DueDays < 0 {
+		return fmt.Errorf("config: field [Billing.DueDays] must not be negative")
+	}
+
+	if c.Billing.GraceDays < 0 {
+		return fmt.Errorf("config: field [Billing.GraceDays] must not be negative")
+	}
+
+	if c.Billing.Currency == "" {
+		return fmt.Errorf("config: field [Billing.Currency] is required")
+	}
+
 	return nil
 }
 
 // Print configuration values to the log. Some user and password fields
 // are omitted for security reasons.
-func (c *Config) Print() {
-	log.Println("----------------------------------")
-	log.Println("-Stormrage Project")
-	log.Println("    Application Port:", c.Port)
-	log.Println("       Database Host:", c.DB.Host)
-	log.Println("       Database Port:", c.DB.Port)
-	log.Println("       Database Name:", c.DB.Name)
-	log.Println("         Db Log mode:", c.DB.LogMode)
-	log.Println(" Private Router Addr:", c.PrivateRouter.Address)
-	log.Println(" Private Router Port:", c.PrivateRouter.Port)
-	log.Println("----------------------------------")
+func (c *Config) Print(log logger.Logger) {
+	log.Info(
+		"stormrage configuration",
+		"port", c.Port,
+		"env", c.Env,
+		"db_host", c.DB.Host,
+		"db_port", c.DB.Port,
+		"db_name", c.DB.Name,
+		"db_log_mode", c.DB.LogMode,
+		"log_level", c.Log.Level,
+		"log_format", c.Log.Format,
+		"allowed_origins", c.AllowedOrigins,
+		"private_router_addr", c.PrivateRouter.Address,
+		"private_router_port", c.PrivateRouter.Port,
+		"suspended_address_list", c.PrivateRouter.SuspendedAddressList,
+		"backup_interval_hours", c.Backup.IntervalHours,
+		"backup_retention", c.Backup.Retention,
+		"monitor_interval", c.Monitor.IntervalSeconds,
+		"monitor_ping_count", c.Monitor.PingCount,
+		"monitor_retention_days", c.Monitor.RetentionDays,
+		"alert_interval", c.Alert.IntervalSeconds,
+		"alert_cpu_threshold", c.Alert.CPUThreshold,
+		"alert_smtp_host", c.Alert.SMTPHost,
+		"alert_email_to", c.Alert.EmailTo,
+		"alert_webhook", c.Alert.WebhookURL != "",
+		"alert_telegram", c.Alert.TelegramToken != "",
+		"traffic_interval", c.Traffic.IntervalSeconds,
+		"traffic_retention_5m_days", c.Traffic.FiveMinuteRetentionDays,
+		"traffic_retention_hourly_days", c.Traffic.HourlyRetentionDays,
+		"traffic_retention_daily_days", c.Traffic.DailyRetentionDays,
+		"quota_interval", c.Quota.IntervalSeconds,
+		"schedule_interval", c.Schedule.IntervalSeconds,
+		"billing_interval_hours", c.Billing.IntervalHours,
+		"billing_due_days", c.Billing.DueDays,
+		"billing_grace_days", c.Billing.GraceDays,
+		"billing_currency", c.Billing.Currency,
+		"document_logo_path", c.Document.LogoPath,
+		"document_company_name", c.Document.CompanyName,
+	)
 }
@@ -0,0 +1,30 @@
+package alert
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/services"
+)
+
+// ListActive returns the alerts that are firing.
+func (h *handler) ListActive(w http.ResponseWriter, r *http.Request) error {
+	return httputils.WriteJSON(w, http.StatusOK, h.alertService.Active())
+}
+
+// SendTest sends a test notification through every configured notifier. If
+// any of them fails, a 502 response with the errors is written.
+func (h *handler) SendTest(w http.ResponseWriter, r *http.Request) error {
+	err := h.alertService.SendTest(r.Context())
+
+	if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	} else if err != nil {
+		httputils.WriteError(w, http.StatusBadGateway, err.Error())
+		return nil
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,24 @@
+package alert
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/alert"
+)
+
+type Handler interface {
+	ListActive(w http.ResponseWriter, r *http.Request) error
+	SendTest(w http.ResponseWriter, r *http.Request) error
+}
+
+// handler contains all handlers in charge of the alerts.
+type handler struct {
+	alertService alert.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(alertService alert.Service) Handler {
+	return &handler{
+		alertService: alertService,
+	}
+}
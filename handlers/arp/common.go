@@ -0,0 +1,57 @@
+package arp
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+)
+
+// ListEntries returns the router's ARP table.
+func (h *handler) ListEntries(w http.ResponseWriter, r *http.Request) error {
+	entries, err := h.mikrotikService.RequestARPEntries(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, entries)
+}
+
+// AddStaticEntry adds a static ARP entry that locks an IP address to a MAC
+// address.
+func (h *handler) AddStaticEntry(w http.ResponseWriter, r *http.Request) error {
+	var form models.ARPEntry
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	entry, err := h.mikrotikService.AddStaticARPEntry(r.Context(), &form)
+
+	if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, entry)
+}
+
+// Lookup returns the ARP entry, DHCP lease, client and interface of the IP
+// or MAC address sent in the 'q' query parameter.
+func (h *handler) Lookup(w http.ResponseWriter, r *http.Request) error {
+	result, err := h.mikrotikService.Lookup(r.Context(), r.URL.Query().Get("q"))
+
+	if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, result)
+}
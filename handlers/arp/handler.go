@@ -0,0 +1,26 @@
+package arp
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/mikrotik"
+)
+
+type Handler interface {
+	ListEntries(w http.ResponseWriter, r *http.Request) error
+	AddStaticEntry(w http.ResponseWriter, r *http.Request) error
+	Lookup(w http.ResponseWriter, r *http.Request) error
+}
+
+// handler contains all handlers in charge of the ARP table and of looking up
+// addresses.
+type handler struct {
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(s mikrotik.Service) Handler {
+	return &handler{
+		mikrotikService: s,
+	}
+}
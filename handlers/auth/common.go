@@ -1,14 +1,13 @@
 package auth
 
 import (
-	"fmt"
-	"log"
 	"net/http"
 	"time"
 
 	"github.com/ab22/stormrage/config"
 	"github.com/ab22/stormrage/handlers"
 	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/logger"
 	"github.com/gorilla/sessions"
 )
 
@@ -30,11 +29,7 @@ func (h *handler) Login(w http.ResponseWriter, r *http.Request) error {
 		ctx         = r.Context()
 		cookieStore = ctx.Value("cookieStore").(*sessions.CookieStore)
 		cfg         = ctx.Value("config").(*config.Config)
-
-		loginForm struct {
-			Username string
-			Password string
-		}
+		loginForm   LoginForm
 	)
 
 	if err = httputils.DecodeJSON(r.Body, &loginForm); err != nil {
@@ -47,12 +42,11 @@ func (h *handler) Login(w http.ResponseWriter, r *http.Request) error {
 	if err != nil {
 		return err
 	} else if user == nil {
-		var errorMsg = fmt.Sprintf(
-			"Failed login attempt with user [%s] from IP [%s]",
-			loginForm.Username,
-			r.RemoteAddr,
+		logger.FromContext(ctx, h.log).Warn(
+			"failed login attempt",
+			"username", loginForm.Username,
+			"remote_addr", r.RemoteAddr,
 		)
-		log.Println(errorMsg)
 
 		httputils.WriteError(w, http.StatusUnauthorized, "Usuario/Clave inválidos!")
 		return nil
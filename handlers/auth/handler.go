@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/logger"
 	"github.com/ab22/stormrage/services/auth"
 )
 
@@ -13,16 +14,24 @@ type Handler interface {
 	Logout(w http.ResponseWriter, r *http.Request) error
 }
 
+// LoginForm is the request body of the Login handler.
+type LoginForm struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
 // handler contains all handlers in charge of authentication and sessions.
 type handler struct {
 	authService auth.Service
 	cfg         *config.Config
+	log         logger.Logger
 }
 
 // NewHandler creates a new Handler.
-func NewHandler(authService auth.Service, cfg *config.Config) Handler {
+func NewHandler(authService auth.Service, cfg *config.Config, log logger.Logger) Handler {
 	return &handler{
 		authService: authService,
 		cfg:         cfg,
+		log:         log,
 	}
 }
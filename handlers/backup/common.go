@@ -0,0 +1,127 @@
+package backup
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ab22/stormrage/handlers"
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/ab22/stormrage/services/backup"
+	"github.com/gorilla/mux"
+)
+
+// List returns all stored backups without their content.
+func (h *handler) List(w http.ResponseWriter, r *http.Request) error {
+	backups, err := h.backupService.List()
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, backups)
+}
+
+// Create exports the router's configuration and stores it as a manual
+// backup.
+func (h *handler) Create(w http.ResponseWriter, r *http.Request) error {
+	var (
+		sessionData = r.Context().Value("sessionData").(*handlers.SessionData)
+		operatorID  = sessionData.UserID
+	)
+
+	b, err := h.backupService.Create(r.Context(), backup.TriggerManual, &operatorID)
+
+	if err != nil {
+		return err
+	}
+
+	b.Content = ""
+	return httputils.WriteJSON(w, http.StatusCreated, b)
+}
+
+// find searches for the backup identified by the 'id' path variable and
+// writes a 404 response if it does not exist.
+func (h *handler) find(w http.ResponseWriter, r *http.Request) (*models.RouterBackup, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err != nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil, nil
+	}
+
+	b, err := h.backupService.Find(id)
+
+	if err != nil {
+		return nil, err
+	} else if b == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil, nil
+	}
+
+	return b, nil
+}
+
+// Get returns the backup identified by the 'id' path variable, including
+// its content.
+func (h *handler) Get(w http.ResponseWriter, r *http.Request) error {
+	b, err := h.find(w, r)
+
+	if err != nil || b == nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, b)
+}
+
+// Download returns the content of the backup identified by the 'id' path
+// variable as an .rsc script that can be imported on the router.
+func (h *handler) Download(w http.ResponseWriter, r *http.Request) error {
+	b, err := h.find(w, r)
+
+	if err != nil || b == nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set(
+		"Content-Disposition",
+		fmt.Sprintf(`attachment; filename="backup-%d-%s.rsc"`, b.ID, b.CreatedAt.Format("20060102-150405")),
+	)
+
+	_, err = w.Write([]byte(b.Content))
+	return err
+}
+
+// Diff returns the unified diff between the backups identified by the
+// 'from' and 'to' query parameters as plain text.
+func (h *handler) Diff(w http.ResponseWriter, r *http.Request) error {
+	var query = r.URL.Query()
+
+	from, err := strconv.Atoi(query.Get("from"))
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "from must be a backup ID")
+		return nil
+	}
+
+	to, err := strconv.Atoi(query.Get("to"))
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "to must be a backup ID")
+		return nil
+	}
+
+	diff, err := h.backupService.Diff(from, to)
+
+	if err == services.ErrRecordNotFound {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, err = w.Write([]byte(diff))
+	return err
+}
@@ -0,0 +1,28 @@
+package backup
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/backup"
+)
+
+type Handler interface {
+	List(w http.ResponseWriter, r *http.Request) error
+	Create(w http.ResponseWriter, r *http.Request) error
+	Get(w http.ResponseWriter, r *http.Request) error
+	Download(w http.ResponseWriter, r *http.Request) error
+	Diff(w http.ResponseWriter, r *http.Request) error
+}
+
+// handler contains all handlers in charge of the router's configuration
+// backups.
+type handler struct {
+	backupService backup.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(backupService backup.Service) Handler {
+	return &handler{
+		backupService: backupService,
+	}
+}
@@ -0,0 +1,288 @@
+package billing
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ab22/stormrage/handlers"
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/gorilla/mux"
+)
+
+// writeError writes the response of the service's argument and not found
+// errors. Other errors are returned.
+func writeError(w http.ResponseWriter, err error) error {
+	if err == services.ErrRecordNotFound {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	} else if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	}
+
+	return err
+}
+
+// ListPlans returns all plans.
+func (h *handler) ListPlans(w http.ResponseWriter, r *http.Request) error {
+	plans, err := h.billingService.ListPlans()
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, plans)
+}
+
+// decodePlan reads the PlanForm of the request body. If the body is
+// invalid, a 400 response is written and nil is returned.
+func decodePlan(w http.ResponseWriter, r *http.Request) *models.Plan {
+	var form PlanForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	return &models.Plan{
+		Name:        form.Name,
+		Description: form.Description,
+		PriceCents:  form.PriceCents,
+	}
+}
+
+// CreatePlan adds a new plan.
+func (h *handler) CreatePlan(w http.ResponseWriter, r *http.Request) error {
+	form := decodePlan(w, r)
+	if form == nil {
+		return nil
+	}
+
+	plan, err := h.billingService.CreatePlan(form)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, plan)
+}
+
+// UpdatePlan replaces the plan identified by the 'id' path variable.
+func (h *handler) UpdatePlan(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	form := decodePlan(w, r)
+	if form == nil {
+		return nil
+	}
+
+	plan, err := h.billingService.UpdatePlan(id, form)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, plan)
+}
+
+// RemovePlan deletes the plan identified by the 'id' path variable. Plans
+// assigned to clients can't be removed.
+func (h *handler) RemovePlan(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err := h.billingService.RemovePlan(id); err != nil {
+		return writeError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ListAccounts returns the balance of every client with a billing account.
+// If the 'overdue' query parameter is true, only the overdue accounts are
+// returned.
+func (h *handler) ListAccounts(w http.ResponseWriter, r *http.Request) error {
+	overdueOnly, _ := strconv.ParseBool(r.URL.Query().Get("overdue"))
+
+	accounts, err := h.billingService.ListAccounts(overdueOnly)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, accounts)
+}
+
+// ClientAccount returns the balance of the client identified by the 'id'
+// path variable.
+func (h *handler) ClientAccount(w http.ResponseWriter, r *http.Request) error {
+	account, err := h.billingService.Account(mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	} else if account == nil {
+		httputils.WriteError(w, http.StatusNotFound, "client has no billing account")
+		return nil
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, account)
+}
+
+// Assign sets the plan of the client identified by the 'id' path variable.
+func (h *handler) Assign(w http.ResponseWriter, r *http.Request) error {
+	var form AccountForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	client, err := h.mikrotikService.RequestClient(r.Context(), mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	} else if client == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	}
+
+	account, err := h.billingService.Assign(r.Context(), client.ID, form.PlanID, form.BillingDay)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, account)
+}
+
+// Unassign removes the billing account of the client identified by the 'id'
+// path variable.
+func (h *handler) Unassign(w http.ResponseWriter, r *http.Request) error {
+	if err := h.billingService.Unassign(r.Context(), mux.Vars(r)["id"]); err != nil {
+		return writeError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ClientInvoices returns the invoices of the client identified by the 'id'
+// path variable.
+func (h *handler) ClientInvoices(w http.ResponseWriter, r *http.Request) error {
+	invoices, err := h.billingService.Invoices(mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, invoices)
+}
+
+// CreateInvoice adds a charge to the account of the client identified by
+// the 'id' path variable.
+func (h *handler) CreateInvoice(w http.ResponseWriter, r *http.Request) error {
+	var form InvoiceForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	invoice, err := h.billingService.CreateInvoice(mux.Vars(r)["id"], form.Description, form.AmountCents)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, invoice)
+}
+
+// FindInvoice returns the invoice identified by the 'id' path variable.
+func (h *handler) FindInvoice(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	invoice, err := h.billingService.FindInvoice(id)
+
+	if err != nil {
+		return err
+	} else if invoice == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, invoice)
+}
+
+// VoidInvoice cancels the invoice identified by the 'id' path variable.
+func (h *handler) VoidInvoice(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	invoice, err := h.billingService.VoidInvoice(r.Context(), id)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, invoice)
+}
+
+// ClientPayments returns the payments of the client identified by the 'id'
+// path variable.
+func (h *handler) ClientPayments(w http.ResponseWriter, r *http.Request) error {
+	payments, err := h.billingService.Payments(mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, payments)
+}
+
+// RegisterPayment adds a payment to the account of the client identified by
+// the 'id' path variable. The logged in user is recorded as the operator
+// who received it.
+func (h *handler) RegisterPayment(w http.ResponseWriter, r *http.Request) error {
+	var (
+		form        PaymentForm
+		sessionData = r.Context().Value("sessionData").(*handlers.SessionData)
+		operatorID  = sessionData.UserID
+	)
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	payment := &models.Payment{
+		ClientID:    mux.Vars(r)["id"],
+		AmountCents: form.AmountCents,
+		Method:      form.Method,
+		Reference:   form.Reference,
+		Notes:       form.Notes,
+		OperatorID:  &operatorID,
+	}
+
+	if form.ReceivedAt != nil {
+		payment.ReceivedAt = *form.ReceivedAt
+	}
+
+	payment, err := h.billingService.RegisterPayment(r.Context(), payment)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, payment)
+}
+
+// Run runs the billing job right away.
+func (h *handler) Run(w http.ResponseWriter, r *http.Request) error {
+	if err := h.billingService.Run(r.Context()); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,71 @@
+package billing
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ab22/stormrage/services/billing"
+	"github.com/ab22/stormrage/services/mikrotik"
+)
+
+type Handler interface {
+	ListPlans(w http.ResponseWriter, r *http.Request) error
+	CreatePlan(w http.ResponseWriter, r *http.Request) error
+	UpdatePlan(w http.ResponseWriter, r *http.Request) error
+	RemovePlan(w http.ResponseWriter, r *http.Request) error
+	ListAccounts(w http.ResponseWriter, r *http.Request) error
+	ClientAccount(w http.ResponseWriter, r *http.Request) error
+	Assign(w http.ResponseWriter, r *http.Request) error
+	Unassign(w http.ResponseWriter, r *http.Request) error
+	ClientInvoices(w http.ResponseWriter, r *http.Request) error
+	CreateInvoice(w http.ResponseWriter, r *http.Request) error
+	FindInvoice(w http.ResponseWriter, r *http.Request) error
+	VoidInvoice(w http.ResponseWriter, r *http.Request) error
+	ClientPayments(w http.ResponseWriter, r *http.Request) error
+	RegisterPayment(w http.ResponseWriter, r *http.Request) error
+	Run(w http.ResponseWriter, r *http.Request) error
+}
+
+// PlanForm is the request body of the CreatePlan and UpdatePlan handlers.
+type PlanForm struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	PriceCents  int64  `json:"priceCents" validate:"required"`
+}
+
+// AccountForm is the request body of the Assign handler. BillingDay is the
+// day of the month the invoices are issued, 1 if omitted.
+type AccountForm struct {
+	PlanID     int `json:"planId" validate:"required"`
+	BillingDay int `json:"billingDay"`
+}
+
+// InvoiceForm is the request body of the CreateInvoice handler.
+type InvoiceForm struct {
+	Description string `json:"description" validate:"required"`
+	AmountCents int64  `json:"amountCents" validate:"required"`
+}
+
+// PaymentForm is the request body of the RegisterPayment handler. Method
+// is cash or transfer, cash if omitted. ReceivedAt is now if omitted.
+type PaymentForm struct {
+	AmountCents int64      `json:"amountCents" validate:"required"`
+	Method      string     `json:"method"`
+	Reference   string     `json:"reference"`
+	Notes       string     `json:"notes"`
+	ReceivedAt  *time.Time `json:"receivedAt"`
+}
+
+// handler contains all handlers in charge of the clients' billing.
+type handler struct {
+	billingService  billing.Service
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(billingService billing.Service, mikrotikService mikrotik.Service) Handler {
+	return &handler{
+		billingService:  billingService,
+		mikrotikService: mikrotikService,
+	}
+}
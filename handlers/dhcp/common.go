@@ -0,0 +1,57 @@
+package dhcp
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/gorilla/mux"
+)
+
+// ListLeases returns all DHCP leases.
+func (h *handler) ListLeases(w http.ResponseWriter, r *http.Request) error {
+	leases, err := h.mikrotikService.RequestDHCPLeases(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, leases)
+}
+
+// writeLease writes the lease or the error returned by the service.
+func writeLease(w http.ResponseWriter, lease *models.DHCPLease, err error) error {
+	if err == services.ErrRecordNotFound {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	} else if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, lease)
+}
+
+// MakeStatic converts the dynamic lease identified by the 'id' path variable
+// into a static lease.
+func (h *handler) MakeStatic(w http.ResponseWriter, r *http.Request) error {
+	lease, err := h.mikrotikService.MakeDHCPLeaseStatic(r.Context(), mux.Vars(r)["id"])
+	return writeLease(w, lease, err)
+}
+
+// BindToClient assigns the client's target address to the lease identified
+// by the 'id' path variable.
+func (h *handler) BindToClient(w http.ResponseWriter, r *http.Request) error {
+	var form BindForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	lease, err := h.mikrotikService.BindDHCPLease(r.Context(), mux.Vars(r)["id"], form.ClientID)
+	return writeLease(w, lease, err)
+}
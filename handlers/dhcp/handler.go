@@ -0,0 +1,30 @@
+package dhcp
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/mikrotik"
+)
+
+type Handler interface {
+	ListLeases(w http.ResponseWriter, r *http.Request) error
+	MakeStatic(w http.ResponseWriter, r *http.Request) error
+	BindToClient(w http.ResponseWriter, r *http.Request) error
+}
+
+// BindForm is the request body of the BindToClient handler.
+type BindForm struct {
+	ClientID string `json:"clientId" validate:"required"`
+}
+
+// handler contains all handlers in charge of DHCP leases.
+type handler struct {
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(s mikrotik.Service) Handler {
+	return &handler{
+		mikrotikService: s,
+	}
+}
@@ -0,0 +1,12 @@
+package docs
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+)
+
+// OpenAPI serves the OpenAPI 3 document generated from the route table.
+func (h *handler) OpenAPI(w http.ResponseWriter, r *http.Request) error {
+	return httputils.WriteJSONWithETag(w, r, http.StatusOK, h.document)
+}
@@ -0,0 +1,23 @@
+package docs
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/openapi"
+)
+
+type Handler interface {
+	OpenAPI(w http.ResponseWriter, r *http.Request) error
+}
+
+// handler serves the API's documentation.
+type handler struct {
+	document *openapi.Document
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(document *openapi.Document) Handler {
+	return &handler{
+		document: document,
+	}
+}
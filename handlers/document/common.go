@@ -0,0 +1,104 @@
+package document
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ab22/stormrage/handlers"
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/gorilla/mux"
+)
+
+// operatorID returns the ID of the logged in user.
+func operatorID(r *http.Request) *int {
+	sessionData := r.Context().Value("sessionData").(*handlers.SessionData)
+	id := sessionData.UserID
+
+	return &id
+}
+
+// writePDF writes the document as a PDF attachment. Stored documents
+// include their URL in the Location header.
+func writePDF(w http.ResponseWriter, document *models.Document, err error) error {
+	if err == services.ErrRecordNotFound {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, document.FileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(document.Content)))
+
+	if document.ID != 0 {
+		w.Header().Set("Location", fmt.Sprintf("/api/v1/documents/%d", document.ID))
+	}
+
+	_, err = w.Write(document.Content)
+	return err
+}
+
+// Invoice returns the PDF of the invoice identified by the 'id' path
+// variable.
+func (h *handler) Invoice(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	document, err := h.documentService.Invoice(r.Context(), id, false, operatorID(r))
+	return writePDF(w, document, err)
+}
+
+// StoreInvoice returns the PDF of the invoice identified by the 'id' path
+// variable and stores it, so the same copy can be printed again.
+func (h *handler) StoreInvoice(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	document, err := h.documentService.Invoice(r.Context(), id, true, operatorID(r))
+	return writePDF(w, document, err)
+}
+
+// Receipt returns the PDF receipt of the payment identified by the 'id'
+// path variable.
+func (h *handler) Receipt(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	document, err := h.documentService.Receipt(r.Context(), id, false, operatorID(r))
+	return writePDF(w, document, err)
+}
+
+// StoreReceipt returns the PDF receipt of the payment identified by the
+// 'id' path variable and stores it, so the same copy can be printed again.
+func (h *handler) StoreReceipt(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	document, err := h.documentService.Receipt(r.Context(), id, true, operatorID(r))
+	return writePDF(w, document, err)
+}
+
+// ListClientDocuments returns the stored documents of the client identified
+// by the 'id' path variable, without their content.
+func (h *handler) ListClientDocuments(w http.ResponseWriter, r *http.Request) error {
+	documents, err := h.documentService.List(mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, documents)
+}
+
+// Download returns the PDF of the stored document identified by the 'id'
+// path variable.
+func (h *handler) Download(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	document, err := h.documentService.Find(id)
+	if err == nil && document == nil {
+		err = services.ErrRecordNotFound
+	}
+
+	return writePDF(w, document, err)
+}
@@ -0,0 +1,28 @@
+package document
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/document"
+)
+
+type Handler interface {
+	Invoice(w http.ResponseWriter, r *http.Request) error
+	StoreInvoice(w http.ResponseWriter, r *http.Request) error
+	Receipt(w http.ResponseWriter, r *http.Request) error
+	StoreReceipt(w http.ResponseWriter, r *http.Request) error
+	ListClientDocuments(w http.ResponseWriter, r *http.Request) error
+	Download(w http.ResponseWriter, r *http.Request) error
+}
+
+// handler contains all handlers in charge of the PDF invoices and receipts.
+type handler struct {
+	documentService document.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(documentService document.Service) Handler {
+	return &handler{
+		documentService: documentService,
+	}
+}
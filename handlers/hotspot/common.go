@@ -0,0 +1,167 @@
+package hotspot
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/gorilla/mux"
+)
+
+// ListUsers returns all hotspot users.
+func (h *handler) ListUsers(w http.ResponseWriter, r *http.Request) error {
+	users, err := h.mikrotikService.RequestHotspotUsers(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSONWithETag(w, r, http.StatusOK, users)
+}
+
+// findUser returns the hotspot user identified by the 'id' path variable.
+// If the user does not exist, a 404 response is written and nil is returned.
+func (h *handler) findUser(w http.ResponseWriter, r *http.Request) (*models.HotspotUser, error) {
+	user, err := h.mikrotikService.RequestHotspotUser(r.Context(), mux.Vars(r)["id"])
+
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+	}
+
+	return user, nil
+}
+
+// FindUser returns the hotspot user identified by the 'id' path variable.
+func (h *handler) FindUser(w http.ResponseWriter, r *http.Request) error {
+	user, err := h.findUser(w, r)
+
+	if err != nil || user == nil {
+		return err
+	}
+
+	return httputils.WriteJSONWithETag(w, r, http.StatusOK, user)
+}
+
+// CreateUser adds a new hotspot user.
+func (h *handler) CreateUser(w http.ResponseWriter, r *http.Request) error {
+	var form models.HotspotUser
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	if form.Password == "" {
+		httputils.WriteError(w, http.StatusBadRequest, "password is required")
+		return nil
+	}
+
+	user, err := h.mikrotikService.CreateHotspotUser(r.Context(), &form)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, user)
+}
+
+// UpdateUser replaces the hotspot user identified by the 'id' path variable.
+func (h *handler) UpdateUser(w http.ResponseWriter, r *http.Request) error {
+	var form models.HotspotUser
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	user, err := h.findUser(w, r)
+	if err != nil || user == nil {
+		return err
+	}
+
+	user, err = h.mikrotikService.UpdateHotspotUser(r.Context(), user.ID, &form)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, user)
+}
+
+// RemoveUser deletes the hotspot user identified by the 'id' path variable.
+func (h *handler) RemoveUser(w http.ResponseWriter, r *http.Request) error {
+	user, err := h.findUser(w, r)
+	if err != nil || user == nil {
+		return err
+	}
+
+	if err = h.mikrotikService.RemoveHotspotUser(r.Context(), user.ID); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ListSessions returns all active hotspot sessions.
+func (h *handler) ListSessions(w http.ResponseWriter, r *http.Request) error {
+	sessions, err := h.mikrotikService.RequestHotspotSessions(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, sessions)
+}
+
+// LogoutSession logs out the active session identified by the 'id' path
+// variable.
+func (h *handler) LogoutSession(w http.ResponseWriter, r *http.Request) error {
+	err := h.mikrotikService.LogoutHotspotSession(r.Context(), mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// GenerateVouchers creates a batch of hotspot users with random credentials.
+// The users are returned as JSON, or as a printable HTML page if the
+// 'format' query parameter is 'html'.
+func (h *handler) GenerateVouchers(w http.ResponseWriter, r *http.Request) error {
+	var form VoucherForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	users, err := h.mikrotikService.GenerateHotspotVouchers(r.Context(), mikrotik.VoucherBatch{
+		Count:           form.Count,
+		Prefix:          form.Prefix,
+		Profile:         form.Profile,
+		Server:          form.Server,
+		LimitUptime:     form.LimitUptime,
+		LimitBytesTotal: form.LimitBytesTotal,
+		Comment:         form.Comment,
+	})
+
+	if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if r.URL.Query().Get("format") == "html" {
+		return writeVouchers(w, http.StatusCreated, users)
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, users)
+}
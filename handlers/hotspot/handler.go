@@ -0,0 +1,41 @@
+package hotspot
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/mikrotik"
+)
+
+type Handler interface {
+	ListUsers(w http.ResponseWriter, r *http.Request) error
+	FindUser(w http.ResponseWriter, r *http.Request) error
+	CreateUser(w http.ResponseWriter, r *http.Request) error
+	UpdateUser(w http.ResponseWriter, r *http.Request) error
+	RemoveUser(w http.ResponseWriter, r *http.Request) error
+	ListSessions(w http.ResponseWriter, r *http.Request) error
+	LogoutSession(w http.ResponseWriter, r *http.Request) error
+	GenerateVouchers(w http.ResponseWriter, r *http.Request) error
+}
+
+// VoucherForm is the request body of the GenerateVouchers handler.
+type VoucherForm struct {
+	Count           int    `json:"count" validate:"required"`
+	Prefix          string `json:"prefix"`
+	Profile         string `json:"profile"`
+	Server          string `json:"server"`
+	LimitUptime     string `json:"limitUptime"`
+	LimitBytesTotal int64  `json:"limitBytesTotal"`
+	Comment         string `json:"comment"`
+}
+
+// handler contains all handlers in charge of hotspot users and sessions.
+type handler struct {
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(s mikrotik.Service) Handler {
+	return &handler{
+		mikrotikService: s,
+	}
+}
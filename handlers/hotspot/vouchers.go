@@ -0,0 +1,63 @@
+package hotspot
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/ab22/stormrage/models"
+)
+
+// voucherTemplate renders the vouchers as cards that can be printed and cut
+// out, ten per page.
+var voucherTemplate = template.Must(template.New("vouchers").Funcs(template.FuncMap{
+	"bytes": formatBytes,
+}).Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>Fichas de Internet</title>
+<style>
+	body { font-family: sans-serif; margin: 0; }
+	.voucher { display: inline-block; box-sizing: border-box; width: 50%; height: 20vh; padding: 12px; border: 1px dashed #999; page-break-inside: avoid; }
+	.voucher h2 { margin: 0 0 8px; font-size: 16px; }
+	.voucher p { margin: 2px 0; font-size: 14px; }
+	.voucher code { font-size: 18px; font-weight: bold; }
+</style>
+</head>
+<body>
+{{range .}}<div class="voucher">
+	<h2>Internet Wi-Fi</h2>
+	<p>Usuario: <code>{{.Name}}</code></p>
+	<p>Clave: <code>{{.Password}}</code></p>
+	{{if .LimitUptime}}<p>Tiempo: {{.LimitUptime}}</p>{{end}}
+	{{if .LimitBytesTotal}}<p>Datos: {{bytes .LimitBytesTotal}}</p>{{end}}
+</div>{{end}}
+</body>
+</html>
+`))
+
+// formatBytes formats a number of bytes with binary units, e.g. 1.5 GB.
+func formatBytes(n int64) string {
+	const unit = 1024
+
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// writeVouchers writes the printable list of vouchers as an HTML page.
+func writeVouchers(w http.ResponseWriter, status int, users []models.HotspotUser) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	return voucherTemplate.Execute(w, users)
+}
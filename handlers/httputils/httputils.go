@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 // A RouteType is used to differentiate requests to the API from the statics
@@ -45,3 +47,41 @@ func WriteJSON(w http.ResponseWriter, statusCode int, data interface{}) error {
 func DecodeJSON(r io.Reader, v interface{}) error {
 	return json.NewDecoder(r).Decode(v)
 }
+
+// IsAllowedOrigin checks if the request was sent from the server's own
+// origin or from one of the allowed origins. The origin is read from the
+// Origin header, falling back to the Referer header. Requests that carry
+// neither header were not sent by a browser and can't be forged by a third
+// party site, so they are allowed.
+func IsAllowedOrigin(r *http.Request, allowedOrigins []string) bool {
+	origin := r.Header.Get("Origin")
+
+	if origin == "" {
+		referer := r.Header.Get("Referer")
+
+		if referer == "" {
+			return true
+		}
+
+		origin = referer
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+
+	origin = u.Scheme + "://" + u.Host
+
+	for _, allowed := range allowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+
+	return false
+}
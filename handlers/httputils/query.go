@@ -0,0 +1,130 @@
+package httputils
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultPerPage is the page size used when the request doesn't set one.
+	DefaultPerPage = 50
+
+	// MaxPerPage is the biggest page size a request can ask for.
+	MaxPerPage = 500
+)
+
+// Pagination contains the page requested through the 'page' and 'perPage'
+// query parameters. Pages start at 1.
+type Pagination struct {
+	Page    int
+	PerPage int
+}
+
+// ParsePagination reads the 'page' and 'perPage' query parameters.
+func ParsePagination(r *http.Request) (Pagination, error) {
+	var (
+		err   error
+		query = r.URL.Query()
+		p     = Pagination{
+			Page:    1,
+			PerPage: DefaultPerPage,
+		}
+	)
+
+	if v := query.Get("page"); v != "" {
+		if p.Page, err = strconv.Atoi(v); err != nil || p.Page < 1 {
+			return p, fmt.Errorf("page must be a positive number")
+		}
+	}
+
+	if v := query.Get("perPage"); v != "" {
+		if p.PerPage, err = strconv.Atoi(v); err != nil || p.PerPage < 1 || p.PerPage > MaxPerPage {
+			return p, fmt.Errorf("perPage must be a number between 1 and %d", MaxPerPage)
+		}
+	}
+
+	return p, nil
+}
+
+// Bounds returns the slice bounds of the page for a list of total items.
+// Pages past the last one are empty.
+func (p Pagination) Bounds(total int) (int, int) {
+	// The page is compared before multiplying so that huge page numbers
+	// don't overflow.
+	if p.PerPage < 1 || p.Page-1 > total/p.PerPage {
+		return total, total
+	}
+
+	start := max((p.Page-1)*p.PerPage, 0)
+	start = min(start, total)
+
+	return start, min(start+p.PerPage, total)
+}
+
+// Page is the response of paginated list endpoints.
+type Page[T any] struct {
+	Data    []T `json:"data"`
+	Total   int `json:"total"`
+	Page    int `json:"page"`
+	PerPage int `json:"perPage"`
+}
+
+// ParseSort reads the 'sort' query parameter. A leading '-' means that the
+// field must be sorted in descending order. The field must be one of the
+// allowed fields.
+func ParseSort(r *http.Request, allowed ...string) (field string, desc bool, err error) {
+	field = r.URL.Query().Get("sort")
+
+	if field == "" {
+		return "", false, nil
+	}
+
+	if strings.HasPrefix(field, "-") {
+		field = field[1:]
+		desc = true
+	}
+
+	for _, f := range allowed {
+		if f == field {
+			return field, desc, nil
+		}
+	}
+
+	return "", false, fmt.Errorf("sort must be one of: %s", strings.Join(allowed, ", "))
+}
+
+// WriteJSONWithETag writes the data as json with an ETag header calculated
+// from the response's body. If the request's If-None-Match header matches
+// the ETag, a 304 Not Modified response with no body is written instead.
+func WriteJSONWithETag(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	sum := sha1.Sum(body)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	for _, match := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		match = strings.TrimPrefix(strings.TrimSpace(match), "W/")
+
+		if match == etag || match == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	_, err = w.Write(append(body, '\n'))
+	return err
+}
@@ -0,0 +1,43 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/gorilla/mux"
+)
+
+// ListInterfaces returns all of the router's interfaces and their counters.
+func (h *handler) ListInterfaces(w http.ResponseWriter, r *http.Request) error {
+	interfaces, err := h.mikrotikService.RequestInterfaces(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, interfaces)
+}
+
+// setDisabled enables or disables the interface identified by the 'id' path
+// variable.
+func (h *handler) setDisabled(w http.ResponseWriter, r *http.Request, disabled bool) error {
+	err := h.mikrotikService.SetInterfaceDisabled(r.Context(), mux.Vars(r)["id"], disabled)
+
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// EnableInterface enables the interface identified by the 'id' path variable.
+func (h *handler) EnableInterface(w http.ResponseWriter, r *http.Request) error {
+	return h.setDisabled(w, r, false)
+}
+
+// DisableInterface disables the interface identified by the 'id' path
+// variable.
+func (h *handler) DisableInterface(w http.ResponseWriter, r *http.Request) error {
+	return h.setDisabled(w, r, true)
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/mikrotik"
+)
+
+type Handler interface {
+	ListInterfaces(w http.ResponseWriter, r *http.Request) error
+	EnableInterface(w http.ResponseWriter, r *http.Request) error
+	DisableInterface(w http.ResponseWriter, r *http.Request) error
+}
+
+// handler contains all handlers in charge of the router's interfaces.
+type handler struct {
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(s mikrotik.Service) Handler {
+	return &handler{
+		mikrotikService: s,
+	}
+}
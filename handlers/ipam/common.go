@@ -0,0 +1,161 @@
+package ipam
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ab22/stormrage/handlers"
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/services"
+	"github.com/gorilla/mux"
+)
+
+// pathID reads the numeric 'id' path variable.
+func pathID(r *http.Request) int {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	return id
+}
+
+// writeError writes the response of the errors returned by the IPAM
+// service. Returns the error back if it's unexpected.
+func writeError(w http.ResponseWriter, err error) error {
+	if err == services.ErrRecordNotFound {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	} else if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	}
+
+	return err
+}
+
+// ListSubnets returns all subnets of the router.
+func (h *handler) ListSubnets(w http.ResponseWriter, r *http.Request) error {
+	subnets, err := h.ipamService.ListSubnets()
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, subnets)
+}
+
+// CreateSubnet adds a subnet to the router.
+func (h *handler) CreateSubnet(w http.ResponseWriter, r *http.Request) error {
+	var form SubnetForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	subnet, err := h.ipamService.CreateSubnet(form.Name, form.CIDR, form.Gateway)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, subnet)
+}
+
+// RemoveSubnet deletes the subnet identified by the 'id' path variable and
+// its reservations.
+func (h *handler) RemoveSubnet(w http.ResponseWriter, r *http.Request) error {
+	if err := h.ipamService.RemoveSubnet(pathID(r)); err != nil {
+		return writeError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Usage returns the addresses in use of the subnet identified by the 'id'
+// path variable.
+func (h *handler) Usage(w http.ResponseWriter, r *http.Request) error {
+	usage, err := h.ipamService.Usage(r.Context(), pathID(r))
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, usage)
+}
+
+// NextFree returns the next free address of the subnet identified by the
+// 'id' path variable.
+func (h *handler) NextFree(w http.ResponseWriter, r *http.Request) error {
+	address, err := h.ipamService.NextFree(r.Context(), pathID(r))
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, NextFreeResponse{Address: address})
+}
+
+// ListReservations returns the reservations of the subnet identified by the
+// 'id' path variable.
+func (h *handler) ListReservations(w http.ResponseWriter, r *http.Request) error {
+	subnet, err := h.ipamService.FindSubnet(pathID(r))
+
+	if err != nil {
+		return err
+	} else if subnet == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	}
+
+	reservations, err := h.ipamService.Reservations(subnet.ID)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, reservations)
+}
+
+// Reserve sets an address of the subnet identified by the 'id' path
+// variable aside for a client.
+func (h *handler) Reserve(w http.ResponseWriter, r *http.Request) error {
+	var (
+		form        ReserveForm
+		sessionData = r.Context().Value("sessionData").(*handlers.SessionData)
+		operatorID  = sessionData.UserID
+	)
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	reservation, err := h.ipamService.Reserve(r.Context(), pathID(r), form.Address, form.ClientID, form.Note, &operatorID)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, reservation)
+}
+
+// Release deletes the reservation identified by the 'id' path variable.
+func (h *handler) Release(w http.ResponseWriter, r *http.Request) error {
+	if err := h.ipamService.Release(pathID(r)); err != nil {
+		return writeError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Conflicts returns the addresses that are the target of more than one
+// client.
+func (h *handler) Conflicts(w http.ResponseWriter, r *http.Request) error {
+	conflicts, err := h.ipamService.Conflicts(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, conflicts)
+}
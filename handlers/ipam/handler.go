@@ -0,0 +1,51 @@
+package ipam
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/ipam"
+)
+
+type Handler interface {
+	ListSubnets(w http.ResponseWriter, r *http.Request) error
+	CreateSubnet(w http.ResponseWriter, r *http.Request) error
+	RemoveSubnet(w http.ResponseWriter, r *http.Request) error
+	Usage(w http.ResponseWriter, r *http.Request) error
+	NextFree(w http.ResponseWriter, r *http.Request) error
+	ListReservations(w http.ResponseWriter, r *http.Request) error
+	Reserve(w http.ResponseWriter, r *http.Request) error
+	Release(w http.ResponseWriter, r *http.Request) error
+	Conflicts(w http.ResponseWriter, r *http.Request) error
+}
+
+// SubnetForm is the request body of the CreateSubnet handler.
+type SubnetForm struct {
+	Name    string `json:"name" validate:"required"`
+	CIDR    string `json:"cidr" validate:"required"`
+	Gateway string `json:"gateway"`
+}
+
+// ReserveForm is the request body of the Reserve handler. If the address is
+// empty, the subnet's next free address is reserved.
+type ReserveForm struct {
+	Address  string `json:"address"`
+	ClientID string `json:"clientId"`
+	Note     string `json:"note"`
+}
+
+// NextFreeResponse is the response of the NextFree handler.
+type NextFreeResponse struct {
+	Address string `json:"address"`
+}
+
+// handler contains all handlers in charge of the IP address management.
+type handler struct {
+	ipamService ipam.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(ipamService ipam.Service) Handler {
+	return &handler{
+		ipamService: ipamService,
+	}
+}
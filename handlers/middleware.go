@@ -1,14 +1,21 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ab22/stormrage/config"
 	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/openapi"
+	"github.com/ab22/stormrage/services/token"
+	"github.com/ab22/stormrage/services/user"
 	"github.com/gorilla/sessions"
 )
 
@@ -27,57 +34,231 @@ func extendSessionLifetime(sessionData *SessionData, sessionLifeTime time.Durati
 	return sessionData.ExpiresAt.Sub(time.Now()) <= sessionLifeTime/2
 }
 
-// ValidateAuth validates that the user cookie is set up before calling the
-// handler passed as parameter.
-func ValidateAuth(h httputils.HandlerFunc) httputils.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) error {
-		var (
-			ctx     = r.Context()
-			cfg, ok = ctx.Value("config").(*config.Config)
-		)
+// ValidateAuth validates that the request is authenticated before calling
+// the handler passed as parameter. Requests are authenticated either by the
+// session cookie or by an API token sent as a bearer token in the
+// Authorization header. API tokens are accepted only if they were granted the
+// route's scope; routes without a scope require a session cookie.
+func ValidateAuth(scope string) MiddlewareFunc {
+	return func(h httputils.HandlerFunc) httputils.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if secret, ok := bearerToken(r); ok {
+				return validateToken(h, scope, secret, w, r)
+			}
 
-		if !ok {
-			httputils.WriteError(w, http.StatusInternalServerError, "")
-			return fmt.Errorf("validate auth: error casting config object")
+			return validateSession(h, w, r)
 		}
+	}
+}
 
-		cookieStore, ok := ctx.Value("cookieStore").(*sessions.CookieStore)
+// bearerToken reads the token from the 'Authorization: Bearer <token>'
+// header.
+func bearerToken(r *http.Request) (string, bool) {
+	const prefix = "bearer "
 
-		if !ok {
-			httputils.WriteError(w, http.StatusInternalServerError, "")
-			return fmt.Errorf("validate auth: could not cast value as cookie store: %s", ctx.Value("cookieStore"))
-		}
+	header := r.Header.Get("Authorization")
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
 
-		session, err := cookieStore.Get(r, cfg.SessionCookieName)
+	return strings.TrimSpace(header[len(prefix):]), true
+}
 
-		if err != nil {
-			log.Println(err)
-			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
-			return nil
-		}
+// validateSession validates that the user cookie is set up before calling
+// the handler passed as parameter.
+func validateSession(h httputils.HandlerFunc, w http.ResponseWriter, r *http.Request) error {
+	var (
+		ctx     = r.Context()
+		cfg, ok = ctx.Value("config").(*config.Config)
+	)
 
-		sessionData, ok := session.Values["data"].(*SessionData)
+	if !ok {
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return fmt.Errorf("validate auth: error casting config object")
+	}
 
-		if !ok || sessionData.IsInvalid() {
-			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
-			return nil
-		} else if time.Now().After(sessionData.ExpiresAt) {
-			session.Options.MaxAge = -1
-			session.Save(r, w)
-			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	cookieStore, ok := ctx.Value("cookieStore").(*sessions.CookieStore)
 
-			return nil
-		}
+	if !ok {
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return fmt.Errorf("validate auth: could not cast value as cookie store: %s", ctx.Value("cookieStore"))
+	}
+
+	session, err := cookieStore.Get(r, cfg.SessionCookieName)
+
+	if err != nil {
+		logger.FromContext(ctx, nil).Warn("validate auth: error reading session", "error", err)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return nil
+	}
+
+	sessionData, ok := session.Values["data"].(*SessionData)
+
+	if !ok || sessionData.IsInvalid() {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return nil
+	} else if time.Now().After(sessionData.ExpiresAt) {
+		session.Options.MaxAge = -1
+		session.Save(r, w)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+		return nil
+	}
+
+	// Save session only if the session was extended.
+	if extendSessionLifetime(sessionData, cfg.SessionLifeTime) {
+		sessionData.ExpiresAt = time.Now().Add(cfg.SessionLifeTime)
+		session.Save(r, w)
+	}
+
+	return callAuthenticated(h, sessionData, w, r)
+}
+
+// validateToken authenticates the request with an API token. The token must
+// exist, must not be expired or revoked and must have been granted the
+// route's scope.
+func validateToken(h httputils.HandlerFunc, scope, secret string, w http.ResponseWriter, r *http.Request) error {
+	var (
+		ctx              = r.Context()
+		tokenService, ok = ctx.Value("tokenService").(token.Service)
+	)
+
+	if !ok {
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return fmt.Errorf("validate auth: could not cast value as token service: %s", ctx.Value("tokenService"))
+	}
+
+	t, owner, err := tokenService.Authenticate(secret)
+
+	if err != nil {
+		httputils.WriteError(w, http.StatusInternalServerError, "")
+		return fmt.Errorf("validate auth: could not authenticate token: %v", err)
+	} else if t == nil {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return nil
+	} else if scope == "" || !t.HasScope(scope) {
+		logger.FromContext(ctx, nil).Warn("validate auth: token scope denied", "token_id", t.ID, "scope", scope)
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return nil
+	}
+
+	sessionData := &SessionData{
+		UserID:  t.UserID,
+		Email:   owner.Email,
+		TokenID: t.ID,
+		Role:    owner.Role,
+	}
+
+	if t.ExpiresAt != nil {
+		sessionData.ExpiresAt = *t.ExpiresAt
+	}
+
+	return callAuthenticated(h, sessionData, w, r)
+}
+
+// callAuthenticated stores the session data in the request's context and
+// calls the handler.
+func callAuthenticated(h httputils.HandlerFunc, sessionData *SessionData, w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+
+	if info, ok := ctx.Value("requestInfo").(*RequestInfo); ok {
+		info.UserID = sessionData.UserID
+	}
+
+	ctx = context.WithValue(ctx, "sessionData", sessionData)
+	authenticatedRequest := r.WithContext(ctx)
+	return h(w, authenticatedRequest)
+}
+
+// Authorize rejects requests of users that don't have any of the roles with
+// a 403 response. It must be applied after ValidateAuth, which sets up the
+// session data. The role of cookie sessions is read from the database on
+// every request; users that no longer exist or are not active have no role.
+func Authorize(roles []string) MiddlewareFunc {
+	return func(h httputils.HandlerFunc) httputils.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			var (
+				ctx             = r.Context()
+				sessionData, ok = ctx.Value("sessionData").(*SessionData)
+			)
+
+			if !ok {
+				httputils.WriteError(w, http.StatusInternalServerError, "")
+				return fmt.Errorf("authorize: could not cast value as session data: %s", ctx.Value("sessionData"))
+			}
+
+			if !sessionData.IsToken() {
+				userService, ok := ctx.Value("userService").(user.Service)
+
+				if !ok {
+					httputils.WriteError(w, http.StatusInternalServerError, "")
+					return fmt.Errorf("authorize: could not cast value as user service: %s", ctx.Value("userService"))
+				}
+
+				u, err := userService.FindByID(sessionData.UserID)
 
-		// Save session only if the session was extended.
-		if extendSessionLifetime(sessionData, cfg.SessionLifeTime) {
-			sessionData.ExpiresAt = time.Now().Add(cfg.SessionLifeTime)
-			session.Save(r, w)
+				if err != nil {
+					httputils.WriteError(w, http.StatusInternalServerError, "")
+					return fmt.Errorf("authorize: could not find user: %v", err)
+				}
+
+				sessionData.Role = ""
+				if u != nil && u.Status == int(user.Active) {
+					sessionData.Role = u.Role
+				}
+			}
+
+			if !sessionData.HasRole(roles...) {
+				logger.FromContext(ctx, nil).Warn(
+					"authorize: role denied",
+					"user_id", sessionData.UserID,
+					"role", sessionData.Role,
+					"required_roles", roles,
+				)
+
+				httputils.WriteError(w, http.StatusForbidden, "")
+				return nil
+			}
+
+			return h(w, r)
 		}
+	}
+}
+
+// maxBodySize limits the size of the request bodies read by ValidateBody.
+const maxBodySize = 1 << 20
 
-		ctx = context.WithValue(ctx, "sessionData", sessionData)
-		authenticatedRequest := r.WithContext(ctx)
-		return h(w, authenticatedRequest)
+// ValidateBody rejects requests whose JSON body does not match the schema
+// with a 400 response that describes the error. The body is buffered so that
+// the handler can decode it again.
+func ValidateBody(schema *openapi.Schema) MiddlewareFunc {
+	return func(h httputils.HandlerFunc) httputils.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			var (
+				value   interface{}
+				body, _ = io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+			)
+
+			if len(body) > maxBodySize {
+				httputils.WriteError(w, http.StatusRequestEntityTooLarge, "")
+				return nil
+			}
+
+			if err := json.Unmarshal(body, &value); err != nil {
+				httputils.WriteError(w, http.StatusBadRequest, "body must be valid JSON")
+				return nil
+			}
+
+			if err := schema.Validate(value); err != nil {
+				httputils.WriteError(w, http.StatusBadRequest, err.Error())
+				return nil
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			return h(w, r)
+		}
 	}
 }
 
@@ -95,3 +276,95 @@ func HandleHTTPError(h httputils.HandlerFunc) httputils.HandlerFunc {
 		return err
 	}
 }
+
+// isSafeMethod checks if the HTTP method is not supposed to change the
+// server's state.
+func isSafeMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "TRACE":
+		return true
+	}
+
+	return false
+}
+
+// CheckOrigin protects cookie authenticated endpoints against cross site
+// request forgery. State changing requests (any method besides GET, HEAD,
+// OPTIONS and TRACE) are rejected with a 403 if their Origin or Referer
+// headers point to a site that is not the server itself or one of the
+// configured allowed origins.
+func CheckOrigin(h httputils.HandlerFunc) httputils.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var (
+			ctx     = r.Context()
+			cfg, ok = ctx.Value("config").(*config.Config)
+		)
+
+		if !ok {
+			httputils.WriteError(w, http.StatusInternalServerError, "")
+			return fmt.Errorf("check origin: error casting config object")
+		}
+
+		if isSafeMethod(r.Method) || httputils.IsAllowedOrigin(r, cfg.AllowedOrigins) {
+			return h(w, r)
+		}
+
+		logger.FromContext(ctx, nil).Warn(
+			"check origin: cross origin request rejected",
+			"origin", r.Header.Get("Origin"),
+			"referer", r.Header.Get("Referer"),
+		)
+
+		httputils.WriteError(w, http.StatusForbidden, "")
+		return nil
+	}
+}
+
+// LogRequest assigns an ID to the request and logs the request's method,
+// path, status, duration and the authenticated user's ID once the handler
+// returns. If the client or a proxy already sent an X-Request-ID header,
+// that ID is reused. The request ID is written back in the response headers
+// and a logger that carries it is stored in the request's context, so every
+// log entry written while serving the request can be correlated.
+func LogRequest(h httputils.HandlerFunc) httputils.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var (
+			start    = time.Now()
+			ctx      = r.Context()
+			info     = &RequestInfo{ID: r.Header.Get("X-Request-ID")}
+			recorder = &statusRecorder{ResponseWriter: w}
+		)
+
+		if !isValidRequestID(info.ID) {
+			info.ID = newRequestID()
+		}
+
+		log := logger.FromContext(ctx, nil).With("request_id", info.ID)
+		ctx = logger.NewContext(ctx, log)
+		ctx = context.WithValue(ctx, "requestInfo", info)
+		w.Header().Set("X-Request-ID", info.ID)
+
+		err := h(recorder, r.WithContext(ctx))
+
+		fields := []interface{}{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.Status(),
+			"duration", time.Since(start),
+			"remote_addr", r.RemoteAddr,
+		}
+
+		if info.UserID != 0 {
+			fields = append(fields, "user_id", info.UserID)
+		}
+
+		if err != nil {
+			fields = append(fields, "error", err)
+			log.Error("request failed", fields...)
+		} else {
+			log.Info("request", fields...)
+		}
+
+		return err
+	}
+}
@@ -2,12 +2,17 @@ package mikrotik
 
 import (
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/gorilla/mux"
 )
 
 func (h *handler) GetClients(w http.ResponseWriter, r *http.Request) error {
-	clients, err := h.mikrotikService.RequestClients()
+	clients, err := h.mikrotikService.RequestClients(r.Context())
 
 	if err != nil {
 		return err
@@ -15,3 +20,123 @@ func (h *handler) GetClients(w http.ResponseWriter, r *http.Request) error {
 
 	return httputils.WriteJSON(w, http.StatusOK, clients)
 }
+
+// clientSortFields maps the 'sort' query parameter to a function that
+// checks if a client sorts before another. Max limits are compared as rates,
+// by their download limit first.
+var clientSortFields = map[string]func(a, b *models.Client) bool{
+	"name": func(a, b *models.Client) bool {
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	},
+	"target": func(a, b *models.Client) bool {
+		return a.Target < b.Target
+	},
+	"maxLimit": func(a, b *models.Client) bool {
+		aUp, aDown := mikrotik.ParseRateLimit(a.MaxLimit)
+		bUp, bDown := mikrotik.ParseRateLimit(b.MaxLimit)
+
+		if aDown != bDown {
+			return aDown < bDown
+		}
+
+		return aUp < bUp
+	},
+}
+
+// filterClients returns the clients that match the 'q', 'name' and 'target'
+// query parameters. All comparisons are case insensitive substring matches.
+// 'q' matches either the client's name or target.
+func filterClients(clients []models.Client, r *http.Request) []models.Client {
+	var (
+		query    = r.URL.Query()
+		q        = strings.ToLower(query.Get("q"))
+		name     = strings.ToLower(query.Get("name"))
+		target   = strings.ToLower(query.Get("target"))
+		filtered = make([]models.Client, 0, len(clients))
+	)
+
+	for _, c := range clients {
+		var (
+			clientName   = strings.ToLower(c.Name)
+			clientTarget = strings.ToLower(c.Target)
+		)
+
+		if q != "" && !strings.Contains(clientName, q) && !strings.Contains(clientTarget, q) {
+			continue
+		}
+
+		if name != "" && !strings.Contains(clientName, name) {
+			continue
+		}
+
+		if target != "" && !strings.Contains(clientTarget, target) {
+			continue
+		}
+
+		filtered = append(filtered, c)
+	}
+
+	return filtered
+}
+
+// ListClients returns a page of clients. Clients can be filtered with the
+// 'q', 'name' and 'target' query parameters, sorted with the 'sort' query
+// parameter (name, target or maxLimit, prefixed with '-' for descending
+// order) and paginated with the 'page' and 'perPage' query parameters.
+func (h *handler) ListClients(w http.ResponseWriter, r *http.Request) error {
+	pagination, err := httputils.ParsePagination(r)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	sortField, desc, err := httputils.ParseSort(r, "name", "target", "maxLimit")
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	clients, err := h.mikrotikService.RequestClients(r.Context())
+	if err != nil {
+		return err
+	}
+
+	clients = filterClients(clients, r)
+
+	if sortField != "" {
+		less := clientSortFields[sortField]
+
+		sort.SliceStable(clients, func(i, j int) bool {
+			if desc {
+				return less(&clients[j], &clients[i])
+			}
+
+			return less(&clients[i], &clients[j])
+		})
+	}
+
+	start, end := pagination.Bounds(len(clients))
+
+	return httputils.WriteJSONWithETag(w, r, http.StatusOK, httputils.Page[models.Client]{
+		Data:    clients[start:end],
+		Total:   len(clients),
+		Page:    pagination.Page,
+		PerPage: pagination.PerPage,
+	})
+}
+
+// FindClient returns the client identified by the 'id' path variable.
+func (h *handler) FindClient(w http.ResponseWriter, r *http.Request) error {
+	id := mux.Vars(r)["id"]
+
+	client, err := h.mikrotikService.RequestClient(r.Context(), id)
+
+	if err != nil {
+		return err
+	} else if client == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	}
+
+	return httputils.WriteJSONWithETag(w, r, http.StatusOK, client)
+}
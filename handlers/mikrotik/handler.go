@@ -8,6 +8,8 @@ import (
 
 type Handler interface {
 	GetClients(w http.ResponseWriter, r *http.Request) error
+	ListClients(w http.ResponseWriter, r *http.Request) error
+	FindClient(w http.ResponseWriter, r *http.Request) error
 }
 
 // handler contains the websocket upgrader to handler mikrotik's websocket client.
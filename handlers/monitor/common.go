@@ -0,0 +1,78 @@
+package monitor
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// defaultDays is the period covered when the request doesn't set the
+	// 'days' query parameter.
+	defaultDays = 7
+
+	// maxDays is the longest period a request can ask for.
+	maxDays = 365
+)
+
+// findClient checks that the client identified by the 'id' path variable
+// exists and parses the 'days' query parameter into the start of the
+// requested period. If the client does not exist or the parameter is
+// invalid, an error response is written and ok is false.
+func (h *handler) findClient(w http.ResponseWriter, r *http.Request) (clientID string, since time.Time, ok bool, err error) {
+	days := defaultDays
+
+	if v := r.URL.Query().Get("days"); v != "" {
+		if days, err = strconv.Atoi(v); err != nil || days < 1 || days > maxDays {
+			httputils.WriteError(w, http.StatusBadRequest, "days must be between 1 and 365")
+			return "", since, false, nil
+		}
+	}
+
+	client, err := h.mikrotikService.RequestClient(r.Context(), mux.Vars(r)["id"])
+
+	if err != nil {
+		return "", since, false, err
+	} else if client == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return "", since, false, nil
+	}
+
+	return client.ID, time.Now().AddDate(0, 0, -days), true, nil
+}
+
+// Availability returns the client's availability, average latency and
+// outages over the last 'days' days.
+func (h *handler) Availability(w http.ResponseWriter, r *http.Request) error {
+	clientID, since, ok, err := h.findClient(w, r)
+	if err != nil || !ok {
+		return err
+	}
+
+	availability, err := h.monitorService.Availability(clientID, since)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, availability)
+}
+
+// Probes returns the client's probes over the last 'days' days.
+func (h *handler) Probes(w http.ResponseWriter, r *http.Request) error {
+	clientID, since, ok, err := h.findClient(w, r)
+	if err != nil || !ok {
+		return err
+	}
+
+	probes, err := h.monitorService.Probes(clientID, since)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, probes)
+}
@@ -0,0 +1,28 @@
+package monitor
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/ab22/stormrage/services/monitor"
+)
+
+type Handler interface {
+	Availability(w http.ResponseWriter, r *http.Request) error
+	Probes(w http.ResponseWriter, r *http.Request) error
+}
+
+// handler contains all handlers in charge of the clients' uptime
+// monitoring.
+type handler struct {
+	monitorService  monitor.Service
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(monitorService monitor.Service, mikrotikService mikrotik.Service) Handler {
+	return &handler{
+		monitorService:  monitorService,
+		mikrotikService: mikrotikService,
+	}
+}
@@ -0,0 +1,158 @@
+package ppp
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/gorilla/mux"
+)
+
+// ListSecrets returns all PPP secrets.
+func (h *handler) ListSecrets(w http.ResponseWriter, r *http.Request) error {
+	secrets, err := h.mikrotikService.RequestPPPSecrets(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSONWithETag(w, r, http.StatusOK, secrets)
+}
+
+// findSecret returns the secret identified by the 'id' path variable. If the
+// secret does not exist, a 404 response is written and nil is returned.
+func (h *handler) findSecret(w http.ResponseWriter, r *http.Request) (*models.PPPSecret, error) {
+	secret, err := h.mikrotikService.RequestPPPSecret(r.Context(), mux.Vars(r)["id"])
+
+	if err != nil {
+		return nil, err
+	} else if secret == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+	}
+
+	return secret, nil
+}
+
+// FindSecret returns the secret identified by the 'id' path variable.
+func (h *handler) FindSecret(w http.ResponseWriter, r *http.Request) error {
+	secret, err := h.findSecret(w, r)
+
+	if err != nil || secret == nil {
+		return err
+	}
+
+	return httputils.WriteJSONWithETag(w, r, http.StatusOK, secret)
+}
+
+// CreateSecret adds a new PPP secret.
+func (h *handler) CreateSecret(w http.ResponseWriter, r *http.Request) error {
+	var form models.PPPSecret
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	if form.Password == "" {
+		httputils.WriteError(w, http.StatusBadRequest, "password is required")
+		return nil
+	}
+
+	secret, err := h.mikrotikService.CreatePPPSecret(r.Context(), &form)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, secret)
+}
+
+// UpdateSecret replaces the secret identified by the 'id' path variable.
+// Empty addresses, caller ID and comment are cleared, while an empty
+// password keeps the current one.
+func (h *handler) UpdateSecret(w http.ResponseWriter, r *http.Request) error {
+	var form models.PPPSecret
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	secret, err := h.findSecret(w, r)
+	if err != nil || secret == nil {
+		return err
+	}
+
+	secret, err = h.mikrotikService.UpdatePPPSecret(r.Context(), secret.ID, &form)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, secret)
+}
+
+// setDisabled enables or disables the secret identified by the 'id' path
+// variable.
+func (h *handler) setDisabled(w http.ResponseWriter, r *http.Request, disabled bool) error {
+	secret, err := h.findSecret(w, r)
+	if err != nil || secret == nil {
+		return err
+	}
+
+	if err = h.mikrotikService.SetPPPSecretDisabled(r.Context(), secret.ID, disabled); err != nil {
+		return err
+	}
+
+	secret.Disabled = disabled
+	return httputils.WriteJSON(w, http.StatusOK, secret)
+}
+
+// DisableSecret disables the secret identified by the 'id' path variable.
+func (h *handler) DisableSecret(w http.ResponseWriter, r *http.Request) error {
+	return h.setDisabled(w, r, true)
+}
+
+// EnableSecret enables the secret identified by the 'id' path variable.
+func (h *handler) EnableSecret(w http.ResponseWriter, r *http.Request) error {
+	return h.setDisabled(w, r, false)
+}
+
+// RemoveSecret deletes the secret identified by the 'id' path variable.
+func (h *handler) RemoveSecret(w http.ResponseWriter, r *http.Request) error {
+	secret, err := h.findSecret(w, r)
+	if err != nil || secret == nil {
+		return err
+	}
+
+	if err = h.mikrotikService.RemovePPPSecret(r.Context(), secret.ID); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ListSessions returns all active PPP sessions.
+func (h *handler) ListSessions(w http.ResponseWriter, r *http.Request) error {
+	sessions, err := h.mikrotikService.RequestPPPSessions(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, sessions)
+}
+
+// KickSession disconnects the active session identified by the 'id' path
+// variable.
+func (h *handler) KickSession(w http.ResponseWriter, r *http.Request) error {
+	err := h.mikrotikService.KickPPPSession(r.Context(), mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,31 @@
+package ppp
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/mikrotik"
+)
+
+type Handler interface {
+	ListSecrets(w http.ResponseWriter, r *http.Request) error
+	FindSecret(w http.ResponseWriter, r *http.Request) error
+	CreateSecret(w http.ResponseWriter, r *http.Request) error
+	UpdateSecret(w http.ResponseWriter, r *http.Request) error
+	DisableSecret(w http.ResponseWriter, r *http.Request) error
+	EnableSecret(w http.ResponseWriter, r *http.Request) error
+	RemoveSecret(w http.ResponseWriter, r *http.Request) error
+	ListSessions(w http.ResponseWriter, r *http.Request) error
+	KickSession(w http.ResponseWriter, r *http.Request) error
+}
+
+// handler contains all handlers in charge of PPPoE secrets and sessions.
+type handler struct {
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(s mikrotik.Service) Handler {
+	return &handler{
+		mikrotikService: s,
+	}
+}
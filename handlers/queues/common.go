@@ -0,0 +1,184 @@
+package queues
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/gorilla/mux"
+)
+
+// ListTypes returns all queue types.
+func (h *handler) ListTypes(w http.ResponseWriter, r *http.Request) error {
+	queueTypes, err := h.mikrotikService.RequestQueueTypes(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSONWithETag(w, r, http.StatusOK, queueTypes)
+}
+
+// findType returns the queue type identified by the 'id' path variable. If
+// the queue type does not exist, a 404 response is written and nil is
+// returned. Default queue types can't be modified, so a 400 response is
+// written for them as well.
+func (h *handler) findType(w http.ResponseWriter, r *http.Request) (*models.QueueType, error) {
+	queueType, err := h.mikrotikService.RequestQueueType(r.Context(), mux.Vars(r)["id"])
+
+	if err != nil {
+		return nil, err
+	} else if queueType == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil, nil
+	} else if queueType.Default {
+		httputils.WriteError(w, http.StatusBadRequest, "default queue types can't be modified")
+		return nil, nil
+	}
+
+	return queueType, nil
+}
+
+// CreateType adds a new queue type. The kind defaults to pcq.
+func (h *handler) CreateType(w http.ResponseWriter, r *http.Request) error {
+	var form models.QueueType
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	queueType, err := h.mikrotikService.CreateQueueType(r.Context(), &form)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, queueType)
+}
+
+// UpdateType replaces the queue type identified by the 'id' path variable.
+func (h *handler) UpdateType(w http.ResponseWriter, r *http.Request) error {
+	var form models.QueueType
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	queueType, err := h.findType(w, r)
+	if err != nil || queueType == nil {
+		return err
+	}
+
+	queueType, err = h.mikrotikService.UpdateQueueType(r.Context(), queueType.ID, &form)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, queueType)
+}
+
+// RemoveType deletes the queue type identified by the 'id' path variable.
+func (h *handler) RemoveType(w http.ResponseWriter, r *http.Request) error {
+	queueType, err := h.findType(w, r)
+	if err != nil || queueType == nil {
+		return err
+	}
+
+	if err = h.mikrotikService.RemoveQueueType(r.Context(), queueType.ID); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Tree returns the queue tree. Each entry contains its child entries.
+func (h *handler) Tree(w http.ResponseWriter, r *http.Request) error {
+	tree, err := h.mikrotikService.RequestQueueTree(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSONWithETag(w, r, http.StatusOK, tree)
+}
+
+// findTreeEntry returns the queue tree entry identified by the 'id' path
+// variable. If the entry does not exist, a 404 response is written and nil
+// is returned.
+func (h *handler) findTreeEntry(w http.ResponseWriter, r *http.Request) (*models.QueueTree, error) {
+	entry, err := h.mikrotikService.RequestQueueTreeEntry(r.Context(), mux.Vars(r)["id"])
+
+	if err != nil {
+		return nil, err
+	} else if entry == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+	}
+
+	return entry, nil
+}
+
+// writeTreeEntry writes the entry returned by a create or update, or a 400
+// response if the form was invalid.
+func writeTreeEntry(w http.ResponseWriter, status int, entry *models.QueueTree, err error) error {
+	if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, status, entry)
+}
+
+// CreateTreeEntry adds a new queue tree entry.
+func (h *handler) CreateTreeEntry(w http.ResponseWriter, r *http.Request) error {
+	var form TreeEntryForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	entry, err := h.mikrotikService.CreateQueueTreeEntry(r.Context(), form.entry())
+	return writeTreeEntry(w, http.StatusCreated, entry, err)
+}
+
+// UpdateTreeEntry replaces the queue tree entry identified by the 'id' path
+// variable.
+func (h *handler) UpdateTreeEntry(w http.ResponseWriter, r *http.Request) error {
+	var form TreeEntryForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	entry, err := h.findTreeEntry(w, r)
+	if err != nil || entry == nil {
+		return err
+	}
+
+	entry, err = h.mikrotikService.UpdateQueueTreeEntry(r.Context(), entry.ID, form.entry())
+	return writeTreeEntry(w, http.StatusOK, entry, err)
+}
+
+// RemoveTreeEntry deletes the queue tree entry identified by the 'id' path
+// variable.
+func (h *handler) RemoveTreeEntry(w http.ResponseWriter, r *http.Request) error {
+	entry, err := h.findTreeEntry(w, r)
+	if err != nil || entry == nil {
+		return err
+	}
+
+	if err = h.mikrotikService.RemoveQueueTreeEntry(r.Context(), entry.ID); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,62 @@
+package queues
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+)
+
+type Handler interface {
+	ListTypes(w http.ResponseWriter, r *http.Request) error
+	CreateType(w http.ResponseWriter, r *http.Request) error
+	UpdateType(w http.ResponseWriter, r *http.Request) error
+	RemoveType(w http.ResponseWriter, r *http.Request) error
+	Tree(w http.ResponseWriter, r *http.Request) error
+	CreateTreeEntry(w http.ResponseWriter, r *http.Request) error
+	UpdateTreeEntry(w http.ResponseWriter, r *http.Request) error
+	RemoveTreeEntry(w http.ResponseWriter, r *http.Request) error
+}
+
+// TreeEntryForm is the request body of the CreateTreeEntry and
+// UpdateTreeEntry handlers. Priority goes from 1 (highest) to 8 (lowest);
+// 0 keeps the router's default.
+type TreeEntryForm struct {
+	Name       string `json:"name" validate:"required"`
+	Parent     string `json:"parent" validate:"required"`
+	PacketMark string `json:"packetMark"`
+	LimitAt    string `json:"limitAt"`
+	MaxLimit   string `json:"maxLimit"`
+	Priority   int    `json:"priority"`
+	Queue      string `json:"queue"`
+	Comment    string `json:"comment"`
+	Disabled   bool   `json:"disabled"`
+}
+
+// entry converts the form into a queue tree entry.
+func (f *TreeEntryForm) entry() *models.QueueTree {
+	return &models.QueueTree{
+		Name:       f.Name,
+		Parent:     f.Parent,
+		PacketMark: f.PacketMark,
+		LimitAt:    f.LimitAt,
+		MaxLimit:   f.MaxLimit,
+		Priority:   f.Priority,
+		Queue:      f.Queue,
+		Comment:    f.Comment,
+		Disabled:   f.Disabled,
+	}
+}
+
+// handler contains all handlers in charge of queue types and the queue
+// tree.
+type handler struct {
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(s mikrotik.Service) Handler {
+	return &handler{
+		mikrotikService: s,
+	}
+}
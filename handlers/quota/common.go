@@ -0,0 +1,207 @@
+package quota
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ab22/stormrage/handlers"
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/gorilla/mux"
+)
+
+// writeError writes the response of the service's argument and not found
+// errors. Other errors are returned.
+func writeError(w http.ResponseWriter, err error) error {
+	if err == services.ErrRecordNotFound {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	} else if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	}
+
+	return err
+}
+
+// ListQuotas returns all quotas.
+func (h *handler) ListQuotas(w http.ResponseWriter, r *http.Request) error {
+	quotas, err := h.quotaService.ListQuotas()
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, quotas)
+}
+
+// decodeQuota reads the QuotaForm of the request body. If the body is
+// invalid, a 400 response is written and nil is returned.
+func decodeQuota(w http.ResponseWriter, r *http.Request) *models.Quota {
+	var form QuotaForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	return &models.Quota{
+		Name:          form.Name,
+		LimitBytes:    form.LimitBytes,
+		ThrottleLimit: form.ThrottleLimit,
+		CycleDay:      form.CycleDay,
+	}
+}
+
+// CreateQuota adds a new quota.
+func (h *handler) CreateQuota(w http.ResponseWriter, r *http.Request) error {
+	form := decodeQuota(w, r)
+	if form == nil {
+		return nil
+	}
+
+	quota, err := h.quotaService.CreateQuota(form)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, quota)
+}
+
+// UpdateQuota replaces the quota identified by the 'id' path variable.
+func (h *handler) UpdateQuota(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	form := decodeQuota(w, r)
+	if form == nil {
+		return nil
+	}
+
+	quota, err := h.quotaService.UpdateQuota(id, form)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, quota)
+}
+
+// RemoveQuota deletes the quota identified by the 'id' path variable. Quotas
+// assigned to clients can't be removed.
+func (h *handler) RemoveQuota(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err := h.quotaService.RemoveQuota(id); err != nil {
+		return writeError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ListStatuses returns the consumption of every client with a quota.
+func (h *handler) ListStatuses(w http.ResponseWriter, r *http.Request) error {
+	statuses, err := h.quotaService.ListStatuses()
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, statuses)
+}
+
+// findClientID checks that the client identified by the 'id' path variable
+// exists. If it does not, a 404 response is written and an empty ID is
+// returned.
+func (h *handler) findClientID(w http.ResponseWriter, r *http.Request) (string, error) {
+	client, err := h.mikrotikService.RequestClient(r.Context(), mux.Vars(r)["id"])
+
+	if err != nil {
+		return "", err
+	} else if client == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return "", nil
+	}
+
+	return client.ID, nil
+}
+
+// ClientStatus returns the consumption of the client identified by the 'id'
+// path variable in its current cycle.
+func (h *handler) ClientStatus(w http.ResponseWriter, r *http.Request) error {
+	status, err := h.quotaService.Status(mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	} else if status == nil {
+		httputils.WriteError(w, http.StatusNotFound, "client has no quota")
+		return nil
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, status)
+}
+
+// Assign sets the quota of the client identified by the 'id' path variable.
+func (h *handler) Assign(w http.ResponseWriter, r *http.Request) error {
+	var form AssignForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	clientID, err := h.findClientID(w, r)
+	if err != nil || clientID == "" {
+		return err
+	}
+
+	status, err := h.quotaService.Assign(r.Context(), clientID, form.QuotaID)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, status)
+}
+
+// Unassign removes the quota of the client identified by the 'id' path
+// variable and restores its limits.
+func (h *handler) Unassign(w http.ResponseWriter, r *http.Request) error {
+	if err := h.quotaService.Unassign(r.Context(), mux.Vars(r)["id"]); err != nil {
+		return writeError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Override lifts the cap of the client identified by the 'id' path variable
+// until the end of its current cycle.
+func (h *handler) Override(w http.ResponseWriter, r *http.Request) error {
+	var (
+		sessionData = r.Context().Value("sessionData").(*handlers.SessionData)
+		operatorID  = sessionData.UserID
+	)
+
+	status, err := h.quotaService.Override(r.Context(), mux.Vars(r)["id"], &operatorID)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, status)
+}
+
+// RemoveOverride removes the override of the client identified by the 'id'
+// path variable.
+func (h *handler) RemoveOverride(w http.ResponseWriter, r *http.Request) error {
+	status, err := h.quotaService.RemoveOverride(mux.Vars(r)["id"])
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,50 @@
+package quota
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/ab22/stormrage/services/quota"
+)
+
+type Handler interface {
+	ListQuotas(w http.ResponseWriter, r *http.Request) error
+	CreateQuota(w http.ResponseWriter, r *http.Request) error
+	UpdateQuota(w http.ResponseWriter, r *http.Request) error
+	RemoveQuota(w http.ResponseWriter, r *http.Request) error
+	ListStatuses(w http.ResponseWriter, r *http.Request) error
+	ClientStatus(w http.ResponseWriter, r *http.Request) error
+	Assign(w http.ResponseWriter, r *http.Request) error
+	Unassign(w http.ResponseWriter, r *http.Request) error
+	Override(w http.ResponseWriter, r *http.Request) error
+	RemoveOverride(w http.ResponseWriter, r *http.Request) error
+}
+
+// QuotaForm is the request body of the CreateQuota and UpdateQuota
+// handlers. ThrottleLimit is the max-limit set on the throttled clients'
+// queues, e.g. "512k/1M".
+type QuotaForm struct {
+	Name          string `json:"name" validate:"required"`
+	LimitBytes    int64  `json:"limitBytes" validate:"required"`
+	ThrottleLimit string `json:"throttleLimit" validate:"required"`
+	CycleDay      int    `json:"cycleDay"`
+}
+
+// AssignForm is the request body of the Assign handler.
+type AssignForm struct {
+	QuotaID int `json:"quotaId" validate:"required"`
+}
+
+// handler contains all handlers in charge of the clients' data caps.
+type handler struct {
+	quotaService    quota.Service
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(quotaService quota.Service, mikrotikService mikrotik.Service) Handler {
+	return &handler{
+		quotaService:    quotaService,
+		mikrotikService: mikrotikService,
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// RequestInfo contains the data that describes a request for logging
+// purposes. The same pointer is shared by all middlewares so that inner
+// middlewares, such as ValidateAuth, can fill in data that is logged once
+// the request finishes.
+type RequestInfo struct {
+	ID     string
+	UserID int
+}
+
+// newRequestID generates a random 16 byte hex encoded request ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// isValidRequestID checks if the request ID sent by a client or a proxy can
+// be safely reused and logged.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+
+	for _, c := range id {
+		isAlphaNum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+
+		if !isAlphaNum && c != '-' && c != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// statusRecorder wraps a http.ResponseWriter to keep track of the status code
+// written to the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	return r.ResponseWriter.Write(b)
+}
+
+// Hijack lets the websocket upgrader take over the connection.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+
+	if !ok {
+		return nil, nil, fmt.Errorf("status recorder: response writer does not implement http.Hijacker")
+	}
+
+	r.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// Status returns the status code written to the response.
+func (r *statusRecorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+
+	return r.status
+}
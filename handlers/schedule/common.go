@@ -0,0 +1,199 @@
+package schedule
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/gorilla/mux"
+)
+
+// writeError writes the response of the service's argument and not found
+// errors. Other errors are returned.
+func writeError(w http.ResponseWriter, err error) error {
+	if err == services.ErrRecordNotFound {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	} else if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	}
+
+	return err
+}
+
+// ListSchedules returns all schedules with their rules.
+func (h *handler) ListSchedules(w http.ResponseWriter, r *http.Request) error {
+	schedules, err := h.scheduleService.ListSchedules()
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, schedules)
+}
+
+// FindSchedule returns the schedule identified by the 'id' path variable.
+func (h *handler) FindSchedule(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	schedule, err := h.scheduleService.FindSchedule(id)
+
+	if err != nil {
+		return err
+	} else if schedule == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, schedule)
+}
+
+// decodeSchedule reads the ScheduleForm of the request body. If the body is
+// invalid, a 400 response is written and nil is returned.
+func decodeSchedule(w http.ResponseWriter, r *http.Request) *models.BandwidthSchedule {
+	var form ScheduleForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	schedule := &models.BandwidthSchedule{
+		Name:     form.Name,
+		TimeZone: form.TimeZone,
+		Rules:    make([]models.BandwidthRule, 0, len(form.Rules)),
+	}
+
+	for _, rule := range form.Rules {
+		schedule.Rules = append(schedule.Rules, models.BandwidthRule{
+			Days:  rule.Days,
+			Start: rule.Start,
+			End:   rule.End,
+			QueueLimits: models.QueueLimits{
+				MaxLimit:       rule.MaxLimit,
+				BurstLimit:     rule.BurstLimit,
+				BurstThreshold: rule.BurstThreshold,
+				BurstTime:      rule.BurstTime,
+			},
+		})
+	}
+
+	return schedule
+}
+
+// CreateSchedule adds a new schedule.
+func (h *handler) CreateSchedule(w http.ResponseWriter, r *http.Request) error {
+	form := decodeSchedule(w, r)
+	if form == nil {
+		return nil
+	}
+
+	schedule, err := h.scheduleService.CreateSchedule(form)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, schedule)
+}
+
+// UpdateSchedule replaces the schedule identified by the 'id' path variable
+// and its rules.
+func (h *handler) UpdateSchedule(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	form := decodeSchedule(w, r)
+	if form == nil {
+		return nil
+	}
+
+	schedule, err := h.scheduleService.UpdateSchedule(id, form)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, schedule)
+}
+
+// RemoveSchedule deletes the schedule identified by the 'id' path variable.
+// Schedules assigned to clients can't be removed.
+func (h *handler) RemoveSchedule(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err := h.scheduleService.RemoveSchedule(id); err != nil {
+		return writeError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ListStatuses returns the limits and next change of every client with a
+// schedule.
+func (h *handler) ListStatuses(w http.ResponseWriter, r *http.Request) error {
+	statuses, err := h.scheduleService.ListStatuses()
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, statuses)
+}
+
+// ClientStatus returns the limits and next change of the client identified
+// by the 'id' path variable.
+func (h *handler) ClientStatus(w http.ResponseWriter, r *http.Request) error {
+	status, err := h.scheduleService.Status(mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	} else if status == nil {
+		httputils.WriteError(w, http.StatusNotFound, "client has no schedule")
+		return nil
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, status)
+}
+
+// Assign sets the schedule of the client identified by the 'id' path
+// variable.
+func (h *handler) Assign(w http.ResponseWriter, r *http.Request) error {
+	var form AssignForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	client, err := h.mikrotikService.RequestClient(r.Context(), mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	} else if client == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	}
+
+	status, err := h.scheduleService.Assign(r.Context(), client.ID, form.ScheduleID, form.BaseLimits)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, status)
+}
+
+// Unassign removes the schedule of the client identified by the 'id' path
+// variable and restores its base limits.
+func (h *handler) Unassign(w http.ResponseWriter, r *http.Request) error {
+	if err := h.scheduleService.Unassign(r.Context(), mux.Vars(r)["id"]); err != nil {
+		return writeError(w, err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
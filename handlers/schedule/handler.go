@@ -0,0 +1,65 @@
+package schedule
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/ab22/stormrage/services/schedule"
+)
+
+type Handler interface {
+	ListSchedules(w http.ResponseWriter, r *http.Request) error
+	FindSchedule(w http.ResponseWriter, r *http.Request) error
+	CreateSchedule(w http.ResponseWriter, r *http.Request) error
+	UpdateSchedule(w http.ResponseWriter, r *http.Request) error
+	RemoveSchedule(w http.ResponseWriter, r *http.Request) error
+	ListStatuses(w http.ResponseWriter, r *http.Request) error
+	ClientStatus(w http.ResponseWriter, r *http.Request) error
+	Assign(w http.ResponseWriter, r *http.Request) error
+	Unassign(w http.ResponseWriter, r *http.Request) error
+}
+
+// RuleForm is a rule of a ScheduleForm. Start and End have the form HH:MM
+// and Days are the days of the week, e.g. ["sat", "sun"], or every day if
+// empty.
+type RuleForm struct {
+	Days           []string `json:"days"`
+	Start          string   `json:"start" validate:"required"`
+	End            string   `json:"end" validate:"required"`
+	MaxLimit       string   `json:"maxLimit" validate:"required"`
+	BurstLimit     string   `json:"burstLimit"`
+	BurstThreshold string   `json:"burstThreshold"`
+	BurstTime      string   `json:"burstTime"`
+}
+
+// ScheduleForm is the request body of the CreateSchedule and UpdateSchedule
+// handlers. TimeZone is an IANA time zone, e.g. "America/Guatemala", UTC
+// if empty. When rules overlap, the first one wins.
+type ScheduleForm struct {
+	Name     string     `json:"name" validate:"required"`
+	TimeZone string     `json:"timeZone"`
+	Rules    []RuleForm `json:"rules"`
+}
+
+// AssignForm is the request body of the Assign handler. BaseLimits are the
+// limits applied when no rule is active. If they are omitted, the queue's
+// current limits are used.
+type AssignForm struct {
+	ScheduleID int                 `json:"scheduleId" validate:"required"`
+	BaseLimits *models.QueueLimits `json:"baseLimits"`
+}
+
+// handler contains all handlers in charge of the bandwidth schedules.
+type handler struct {
+	scheduleService schedule.Service
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(scheduleService schedule.Service, mikrotikService mikrotik.Service) Handler {
+	return &handler{
+		scheduleService: scheduleService,
+		mikrotikService: mikrotikService,
+	}
+}
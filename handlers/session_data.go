@@ -2,11 +2,33 @@ package handlers
 
 import "time"
 
-// SessionData describes the session cookie for all users.
+// SessionData describes the session cookie for all users. Requests
+// authenticated with an API token get a SessionData with the token's ID,
+// which is never saved in a cookie. The user's Role is not saved in the
+// cookie either: it is read from the database by Authorize, so that role
+// changes apply to open sessions.
 type SessionData struct {
 	UserID    int
 	Email     string
 	ExpiresAt time.Time
+	TokenID   int
+	Role      string
+}
+
+// IsToken checks if the request was authenticated with an API token.
+func (s *SessionData) IsToken() bool {
+	return s.TokenID != 0
+}
+
+// HasRole checks if the user has one of the roles.
+func (s *SessionData) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if s.Role == role {
+			return true
+		}
+	}
+
+	return false
 }
 
 // IsInvalid checks wether the data is in the correct state.
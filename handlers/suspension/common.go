@@ -0,0 +1,98 @@
+package suspension
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/handlers"
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/services"
+	"github.com/ab22/stormrage/services/suspension"
+	"github.com/gorilla/mux"
+)
+
+// operatorID returns the ID of the logged in user.
+func operatorID(r *http.Request) *int {
+	sessionData := r.Context().Value("sessionData").(*handlers.SessionData)
+	id := sessionData.UserID
+
+	return &id
+}
+
+// SuspendClients suspends a list of clients. The result of each client is
+// returned in the response.
+func (h *handler) SuspendClients(w http.ResponseWriter, r *http.Request) error {
+	var form BulkForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	results := h.suspensionService.Suspend(r.Context(), form.IDs, form.Reason, operatorID(r))
+	return httputils.WriteJSON(w, http.StatusOK, results)
+}
+
+// RestoreClients restores a list of clients. The result of each client is
+// returned in the response.
+func (h *handler) RestoreClients(w http.ResponseWriter, r *http.Request) error {
+	var form BulkForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	results := h.suspensionService.Restore(r.Context(), form.IDs, form.Reason, operatorID(r))
+	return httputils.WriteJSON(w, http.StatusOK, results)
+}
+
+// writeResult writes the result of suspending or restoring a single client.
+func writeResult(w http.ResponseWriter, result suspension.Result) error {
+	if result.Err == services.ErrRecordNotFound {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	} else if result.Err != nil {
+		return result.Err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, result.Client)
+}
+
+// SuspendClient suspends the client identified by the 'id' path variable.
+func (h *handler) SuspendClient(w http.ResponseWriter, r *http.Request) error {
+	var form ReasonForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	ids := []string{mux.Vars(r)["id"]}
+	results := h.suspensionService.Suspend(r.Context(), ids, form.Reason, operatorID(r))
+
+	return writeResult(w, results[0])
+}
+
+// RestoreClient restores the client identified by the 'id' path variable.
+// The reason is read from the 'reason' query parameter.
+func (h *handler) RestoreClient(w http.ResponseWriter, r *http.Request) error {
+	var (
+		ids    = []string{mux.Vars(r)["id"]}
+		reason = r.URL.Query().Get("reason")
+	)
+
+	results := h.suspensionService.Restore(r.Context(), ids, reason, operatorID(r))
+	return writeResult(w, results[0])
+}
+
+// History returns the suspension events of the client identified by the
+// 'id' path variable.
+func (h *handler) History(w http.ResponseWriter, r *http.Request) error {
+	events, err := h.suspensionService.History(mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSONWithETag(w, r, http.StatusOK, events)
+}
@@ -0,0 +1,40 @@
+package suspension
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/suspension"
+)
+
+type Handler interface {
+	SuspendClients(w http.ResponseWriter, r *http.Request) error
+	RestoreClients(w http.ResponseWriter, r *http.Request) error
+	SuspendClient(w http.ResponseWriter, r *http.Request) error
+	RestoreClient(w http.ResponseWriter, r *http.Request) error
+	History(w http.ResponseWriter, r *http.Request) error
+}
+
+// BulkForm is the request body of the SuspendClients and RestoreClients
+// handlers.
+type BulkForm struct {
+	IDs    []string `json:"ids" validate:"required"`
+	Reason string   `json:"reason" validate:"required"`
+}
+
+// ReasonForm is the request body of the SuspendClient handler.
+type ReasonForm struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// handler contains all handlers in charge of suspending and restoring
+// clients.
+type handler struct {
+	suspensionService suspension.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(s suspension.Service) Handler {
+	return &handler{
+		suspensionService: s,
+	}
+}
@@ -0,0 +1,68 @@
+package system
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/services/mikrotik"
+)
+
+// RouterStatus returns the router's status document.
+func (h *handler) RouterStatus(w http.ResponseWriter, r *http.Request) error {
+	status, err := h.mikrotikService.RequestRouterStatus(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSONWithETag(w, r, http.StatusOK, status)
+}
+
+// parseTimeParam reads an optional RFC 3339 time from the query parameter.
+func parseTimeParam(r *http.Request, name string) (*time.Time, error) {
+	v := r.URL.Query().Get(name)
+
+	if v == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a RFC 3339 date", name)
+	}
+
+	return &t, nil
+}
+
+// Logs returns the router's log entries. Entries can be filtered with the
+// 'topic', 'text', 'from' and 'to' query parameters.
+func (h *handler) Logs(w http.ResponseWriter, r *http.Request) error {
+	var (
+		err    error
+		query  = r.URL.Query()
+		filter = mikrotik.LogFilter{
+			Topic: query.Get("topic"),
+			Text:  query.Get("text"),
+		}
+	)
+
+	if filter.From, err = parseTimeParam(r, "from"); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	if filter.To, err = parseTimeParam(r, "to"); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	entries, err := h.mikrotikService.RequestLogs(r.Context(), filter)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, entries)
+}
@@ -0,0 +1,24 @@
+package system
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/mikrotik"
+)
+
+type Handler interface {
+	RouterStatus(w http.ResponseWriter, r *http.Request) error
+	Logs(w http.ResponseWriter, r *http.Request) error
+}
+
+// handler contains all handlers in charge of the router's system status.
+type handler struct {
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(s mikrotik.Service) Handler {
+	return &handler{
+		mikrotikService: s,
+	}
+}
@@ -0,0 +1,189 @@
+package ticket
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ab22/stormrage/handlers"
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/ab22/stormrage/services/ticket"
+	"github.com/gorilla/mux"
+)
+
+// writeError writes the response of the service's argument and not found
+// errors. Other errors are returned.
+func writeError(w http.ResponseWriter, err error) error {
+	if err == services.ErrRecordNotFound {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	} else if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	}
+
+	return err
+}
+
+// userID returns the ID of the logged in user.
+func userID(r *http.Request) *int {
+	sessionData := r.Context().Value("sessionData").(*handlers.SessionData)
+	id := sessionData.UserID
+
+	return &id
+}
+
+// List returns the tickets, filtered by the 'status', 'clientId' and
+// 'assigneeId' query parameters.
+func (h *handler) List(w http.ResponseWriter, r *http.Request) error {
+	var (
+		query  = r.URL.Query()
+		filter = ticket.Filter{
+			ClientID: query.Get("clientId"),
+			Status:   query.Get("status"),
+		}
+	)
+
+	if v := query.Get("assigneeId"); v != "" {
+		assigneeID, err := strconv.Atoi(v)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "assigneeId must be a number")
+			return nil
+		}
+
+		filter.AssigneeID = &assigneeID
+	}
+
+	tickets, err := h.ticketService.List(filter)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, tickets)
+}
+
+// ClientTickets returns the tickets of the client identified by the 'id'
+// path variable, filtered by the 'status' query parameter.
+func (h *handler) ClientTickets(w http.ResponseWriter, r *http.Request) error {
+	tickets, err := h.ticketService.List(ticket.Filter{
+		ClientID: mux.Vars(r)["id"],
+		Status:   r.URL.Query().Get("status"),
+	})
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, tickets)
+}
+
+// Create opens a ticket for the client identified by the 'id' path
+// variable. The logged in user is recorded as its creator.
+func (h *handler) Create(w http.ResponseWriter, r *http.Request) error {
+	var form TicketForm
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	created, err := h.ticketService.Create(r.Context(), &models.Ticket{
+		ClientID:    mux.Vars(r)["id"],
+		Target:      form.Target,
+		Subject:     form.Subject,
+		Description: form.Description,
+		AssigneeID:  form.AssigneeID,
+		CreatedBy:   userID(r),
+	})
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, created)
+}
+
+// Find returns the ticket identified by the 'id' path variable with its
+// diagnostics and comments.
+func (h *handler) Find(w http.ResponseWriter, r *http.Request) error {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	found, err := h.ticketService.Find(id)
+
+	if err != nil {
+		return err
+	} else if found == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, found)
+}
+
+// SetStatus changes the status of the ticket identified by the 'id' path
+// variable.
+func (h *handler) SetStatus(w http.ResponseWriter, r *http.Request) error {
+	var form StatusForm
+
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	updated, err := h.ticketService.SetStatus(id, form.Status)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, updated)
+}
+
+// Assign sets the user in charge of the ticket identified by the 'id' path
+// variable.
+func (h *handler) Assign(w http.ResponseWriter, r *http.Request) error {
+	var form AssigneeForm
+
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	updated, err := h.ticketService.Assign(id, form.AssigneeID)
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, updated)
+}
+
+// AddComment adds a comment of the logged in user to the ticket identified
+// by the 'id' path variable.
+func (h *handler) AddComment(w http.ResponseWriter, r *http.Request) error {
+	var form CommentForm
+
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+
+	if err := httputils.DecodeJSON(r.Body, &form); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	comment, err := h.ticketService.AddComment(id, &models.TicketComment{
+		UserID: userID(r),
+		Body:   form.Body,
+	})
+
+	if err != nil {
+		return writeError(w, err)
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, comment)
+}
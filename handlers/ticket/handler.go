@@ -0,0 +1,55 @@
+package ticket
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/ticket"
+)
+
+type Handler interface {
+	List(w http.ResponseWriter, r *http.Request) error
+	ClientTickets(w http.ResponseWriter, r *http.Request) error
+	Create(w http.ResponseWriter, r *http.Request) error
+	Find(w http.ResponseWriter, r *http.Request) error
+	SetStatus(w http.ResponseWriter, r *http.Request) error
+	Assign(w http.ResponseWriter, r *http.Request) error
+	AddComment(w http.ResponseWriter, r *http.Request) error
+}
+
+// TicketForm is the request body of the Create handler. Target is an
+// address of the client's queue the ticket is about, if any.
+type TicketForm struct {
+	Subject     string `json:"subject" validate:"required"`
+	Description string `json:"description"`
+	Target      string `json:"target"`
+	AssigneeID  *int   `json:"assigneeId"`
+}
+
+// StatusForm is the request body of the SetStatus handler. Status is open,
+// in_progress or resolved.
+type StatusForm struct {
+	Status string `json:"status" validate:"required"`
+}
+
+// AssigneeForm is the request body of the Assign handler. A null assignee
+// unassigns the ticket.
+type AssigneeForm struct {
+	AssigneeID *int `json:"assigneeId"`
+}
+
+// CommentForm is the request body of the AddComment handler.
+type CommentForm struct {
+	Body string `json:"body" validate:"required"`
+}
+
+// handler contains all handlers in charge of the support tickets.
+type handler struct {
+	ticketService ticket.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(ticketService ticket.Service) Handler {
+	return &handler{
+		ticketService: ticketService,
+	}
+}
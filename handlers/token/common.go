@@ -0,0 +1,94 @@
+package token
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ab22/stormrage/handlers"
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/services"
+	"github.com/ab22/stormrage/services/token"
+)
+
+// Create generates a new API token for the logged in user. The token's
+// secret is included in the response and it can't be retrieved again.
+func (h *handler) Create(w http.ResponseWriter, r *http.Request) error {
+	var (
+		err         error
+		expiresAt   *time.Time
+		sessionData = r.Context().Value("sessionData").(*handlers.SessionData)
+		tokenForm   CreateForm
+	)
+
+	if err = httputils.DecodeJSON(r.Body, &tokenForm); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	if tokenForm.ExpiresInDays < 0 {
+		httputils.WriteError(w, http.StatusBadRequest, "expiresInDays must be positive")
+		return nil
+	} else if tokenForm.ExpiresInDays > 0 {
+		t := time.Now().AddDate(0, 0, tokenForm.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	t, secret, err := h.tokenService.Create(sessionData.UserID, tokenForm.Name, tokenForm.Scopes, expiresAt)
+
+	if err != nil {
+		if e, ok := err.(services.ErrInvalidArgument); ok {
+			httputils.WriteError(w, http.StatusBadRequest, e.Error())
+			return nil
+		}
+
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, CreateResponse{
+		Token:  t,
+		Secret: secret,
+	})
+}
+
+// List returns all of the logged in user's tokens.
+func (h *handler) List(w http.ResponseWriter, r *http.Request) error {
+	sessionData := r.Context().Value("sessionData").(*handlers.SessionData)
+
+	tokens, err := h.tokenService.FindByUser(sessionData.UserID)
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, tokens)
+}
+
+// Revoke deletes one of the logged in user's tokens.
+func (h *handler) Revoke(w http.ResponseWriter, r *http.Request) error {
+	var (
+		sessionData = r.Context().Value("sessionData").(*handlers.SessionData)
+		revokeForm  RevokeForm
+	)
+
+	if err := httputils.DecodeJSON(r.Body, &revokeForm); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, "")
+		return nil
+	}
+
+	err := h.tokenService.Revoke(sessionData.UserID, revokeForm.ID)
+
+	if err == services.ErrRecordNotFound {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Scopes returns all scopes that can be granted to a token.
+func (h *handler) Scopes(w http.ResponseWriter, r *http.Request) error {
+	return httputils.WriteJSON(w, http.StatusOK, token.Scopes)
+}
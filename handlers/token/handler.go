@@ -0,0 +1,46 @@
+package token
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/token"
+)
+
+type Handler interface {
+	Create(w http.ResponseWriter, r *http.Request) error
+	List(w http.ResponseWriter, r *http.Request) error
+	Revoke(w http.ResponseWriter, r *http.Request) error
+	Scopes(w http.ResponseWriter, r *http.Request) error
+}
+
+// CreateForm is the request body of the Create handler. Tokens that don't
+// set ExpiresInDays never expire.
+type CreateForm struct {
+	Name          string   `json:"name" validate:"required"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expiresInDays"`
+}
+
+// CreateResponse is the response of the Create handler.
+type CreateResponse struct {
+	Token  *models.APIToken `json:"token"`
+	Secret string           `json:"secret"`
+}
+
+// RevokeForm is the request body of the Revoke handler.
+type RevokeForm struct {
+	ID int `json:"id" validate:"required"`
+}
+
+// handler contains all handlers in charge of managing the user's API tokens.
+type handler struct {
+	tokenService token.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(s token.Service) Handler {
+	return &handler{
+		tokenService: s,
+	}
+}
@@ -0,0 +1,109 @@
+package traffic
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/services"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// defaultPeriod is the period covered when the request doesn't set the
+	// 'from' query parameter.
+	defaultPeriod = 24 * time.Hour
+
+	// defaultLimit and maxLimit bound the number of clients returned by
+	// Top.
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+// parsePeriod reads the 'from' and 'to' RFC 3339 query parameters. 'to'
+// defaults to now and 'from' to 24 hours before 'to'.
+func parsePeriod(r *http.Request) (from, to time.Time, err error) {
+	query := r.URL.Query()
+	to = time.Now()
+
+	if v := query.Get("to"); v != "" {
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			return from, to, fmt.Errorf("to must be a RFC 3339 date")
+		}
+	}
+
+	from = to.Add(-defaultPeriod)
+
+	if v := query.Get("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			return from, to, fmt.Errorf("from must be a RFC 3339 date")
+		}
+	}
+
+	return from, to, nil
+}
+
+// ClientUsage returns the traffic of the client identified by the 'id' path
+// variable between the 'from' and 'to' query parameters. The samples'
+// resolution can be set with the 'resolution' query parameter.
+func (h *handler) ClientUsage(w http.ResponseWriter, r *http.Request) error {
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	client, err := h.mikrotikService.RequestClient(r.Context(), mux.Vars(r)["id"])
+
+	if err != nil {
+		return err
+	} else if client == nil {
+		httputils.WriteError(w, http.StatusNotFound, "")
+		return nil
+	}
+
+	usage, err := h.trafficService.Usage(client.ID, from, to, r.URL.Query().Get("resolution"))
+
+	if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	usage.ClientName = client.Name
+	return httputils.WriteJSON(w, http.StatusOK, usage)
+}
+
+// Top returns the clients that used the most traffic between the 'from'
+// and 'to' query parameters. The number of clients is set with the 'limit'
+// query parameter.
+func (h *handler) Top(w http.ResponseWriter, r *http.Request) error {
+	from, to, err := parsePeriod(r)
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil
+	}
+
+	limit := defaultLimit
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxLimit {
+			httputils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+			return nil
+		}
+	}
+
+	top, err := h.trafficService.Top(r.Context(), from, to, limit)
+
+	if e, ok := err.(services.ErrInvalidArgument); ok {
+		httputils.WriteError(w, http.StatusBadRequest, e.Error())
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, top)
+}
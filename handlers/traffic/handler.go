@@ -0,0 +1,27 @@
+package traffic
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/ab22/stormrage/services/traffic"
+)
+
+type Handler interface {
+	ClientUsage(w http.ResponseWriter, r *http.Request) error
+	Top(w http.ResponseWriter, r *http.Request) error
+}
+
+// handler contains all handlers in charge of the clients' traffic history.
+type handler struct {
+	trafficService  traffic.Service
+	mikrotikService mikrotik.Service
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(trafficService traffic.Service, mikrotikService mikrotik.Service) Handler {
+	return &handler{
+		trafficService:  trafficService,
+		mikrotikService: mikrotikService,
+	}
+}
@@ -0,0 +1,19 @@
+package wireless
+
+import (
+	"net/http"
+
+	"github.com/ab22/stormrage/handlers/httputils"
+)
+
+// ListRegistrations returns the stations connected to the router's wireless
+// interfaces and the clients they belong to.
+func (h *handler) ListRegistrations(w http.ResponseWriter, r *http.Request) error {
+	registrations, err := h.mikrotikService.RequestWirelessRegistrations(r.Context())
+
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, registrations)
+}
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Logger describes a leveled and structured logger. Each logging function
+// takes a message and an optional list of alternating key/value pairs which
+// are written as fields of the log entry.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+	With(keyvals ...interface{}) Logger
+}
+
+// Supported output formats.
+const (
+	TextFormat = "text"
+	JSONFormat = "json"
+)
+
+// defaultLogger is returned by FromContext when no logger was found in the
+// context and no fallback logger was specified.
+var defaultLogger Logger = &logger{
+	l: slog.New(slog.NewTextHandler(os.Stderr, nil)),
+}
+
+// logger implements the Logger interface on top of a slog.Logger.
+type logger struct {
+	l *slog.Logger
+}
+
+// New creates a Logger that writes to w all entries with a level equal or
+// above the specified level. The format determines if entries are written as
+// key=value text or as one JSON object per line, which is the format
+// expected by most log shippers.
+func New(w io.Writer, level, format string) (Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		handler slog.Handler
+		opts    = &slog.HandlerOptions{Level: lvl}
+	)
+
+	switch format {
+	case TextFormat:
+		handler = slog.NewTextHandler(w, opts)
+	case JSONFormat:
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("logger: unknown format [%s]", format)
+	}
+
+	return &logger{
+		l: slog.New(handler),
+	}, nil
+}
+
+// ParseLevel converts the level's name into a slog.Level.
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+
+	return 0, fmt.Errorf("logger: unknown level [%s]", level)
+}
+
+func (l *logger) Debug(msg string, keyvals ...interface{}) {
+	l.l.Debug(msg, keyvals...)
+}
+
+func (l *logger) Info(msg string, keyvals ...interface{}) {
+	l.l.Info(msg, keyvals...)
+}
+
+func (l *logger) Warn(msg string, keyvals ...interface{}) {
+	l.l.Warn(msg, keyvals...)
+}
+
+func (l *logger) Error(msg string, keyvals ...interface{}) {
+	l.l.Error(msg, keyvals...)
+}
+
+// With returns a new Logger that adds the key/value pairs to every entry.
+func (l *logger) With(keyvals ...interface{}) Logger {
+	return &logger{
+		l: l.l.With(keyvals...),
+	}
+}
+
+// NewContext returns a copy of the context that carries the logger.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, "logger", l)
+}
+
+// FromContext returns the logger stored in the context. Request scoped
+// loggers carry the request ID, so services should always prefer the context
+// logger over their own. If the context has no logger, fallback is returned
+// or the default logger if fallback is nil.
+func FromContext(ctx context.Context, fallback Logger) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value("logger").(Logger); ok {
+			return l
+		}
+	}
+
+	if fallback != nil {
+		return fallback
+	}
+
+	return defaultLogger
+}
@@ -10,8 +10,6 @@ func main() {
 		log.Fatalln(err)
 	}
 
-	log.Println("Listening...")
-
 	err = s.ListenAndServe()
 	if err != nil {
 		log.Fatalln(err)
@@ -0,0 +1,31 @@
+CREATE TABLE api_tokens
+(
+	id serial NOT NULL,
+	user_id integer NOT NULL,
+	name character varying(60) NOT NULL,
+	prefix character varying(8) NOT NULL,
+	token_hash character varying(64) NOT NULL,
+	scopes character varying(255) NOT NULL DEFAULT '',
+	expires_at timestamp with time zone,
+	last_used_at timestamp with time zone,
+	created_at timestamp with time zone,
+	updated_at timestamp with time zone,
+	deleted_at timestamp with time zone,
+	CONSTRAINT api_tokens_pkey PRIMARY KEY (id),
+	CONSTRAINT api_tokens_user_id_fkey FOREIGN KEY (user_id)
+		REFERENCES users (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE CASCADE
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE UNIQUE INDEX api_tokens_token_hash_unique_idx
+	ON api_tokens
+	USING btree
+	(token_hash);
+
+CREATE INDEX api_tokens_user_id_idx
+	ON api_tokens
+	USING btree
+	(user_id);
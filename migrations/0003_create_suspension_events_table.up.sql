@@ -0,0 +1,24 @@
+CREATE TABLE suspension_events
+(
+	id serial NOT NULL,
+	client_id character varying(30) NOT NULL,
+	client_name character varying(255),
+	target character varying(255),
+	action character varying(10) NOT NULL,
+	reason character varying(255),
+	operator_id integer,
+	created_at timestamp with time zone,
+	CONSTRAINT suspension_events_pkey PRIMARY KEY (id),
+	CONSTRAINT suspension_events_operator_id_fkey FOREIGN KEY (operator_id)
+		REFERENCES users (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE SET NULL,
+	CONSTRAINT suspension_events_action_ck CHECK (action IN ('suspend', 'restore'))
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE INDEX suspension_events_client_id_idx
+	ON suspension_events
+	USING btree
+	(client_id);
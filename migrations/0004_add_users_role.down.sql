@@ -0,0 +1,3 @@
+ALTER TABLE users
+	DROP CONSTRAINT IF EXISTS users_role_ck,
+	DROP COLUMN IF EXISTS role;
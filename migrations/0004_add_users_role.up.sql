@@ -0,0 +1,5 @@
+ALTER TABLE users
+	ADD COLUMN role character varying(20) NOT NULL DEFAULT 'operator',
+	ADD CONSTRAINT users_role_ck CHECK (role IN ('admin', 'operator'));
+
+UPDATE users SET role = 'admin' WHERE username = 'admin';
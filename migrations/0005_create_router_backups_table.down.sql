@@ -0,0 +1 @@
+DROP TABLE IF EXISTS router_backups;
@@ -0,0 +1,24 @@
+CREATE TABLE router_backups
+(
+	id serial NOT NULL,
+	router character varying(255) NOT NULL,
+	content text NOT NULL,
+	size integer NOT NULL,
+	checksum character varying(64) NOT NULL,
+	trigger character varying(10) NOT NULL,
+	created_by integer,
+	created_at timestamp with time zone,
+	CONSTRAINT router_backups_pkey PRIMARY KEY (id),
+	CONSTRAINT router_backups_created_by_fkey FOREIGN KEY (created_by)
+		REFERENCES users (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE SET NULL,
+	CONSTRAINT router_backups_trigger_ck CHECK (trigger IN ('scheduled', 'manual'))
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE INDEX router_backups_created_at_idx
+	ON router_backups
+	USING btree
+	(created_at);
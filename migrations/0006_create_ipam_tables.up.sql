@@ -0,0 +1,45 @@
+CREATE TABLE subnets
+(
+	id serial NOT NULL,
+	router character varying(255) NOT NULL,
+	name character varying(60) NOT NULL,
+	cidr character varying(43) NOT NULL,
+	gateway character varying(39),
+	created_at timestamp with time zone,
+	updated_at timestamp with time zone,
+	CONSTRAINT subnets_pkey PRIMARY KEY (id)
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE UNIQUE INDEX subnets_router_cidr_unique_idx
+	ON subnets
+	USING btree
+	(router, cidr);
+
+CREATE TABLE ip_reservations
+(
+	id serial NOT NULL,
+	subnet_id integer NOT NULL,
+	address character varying(39) NOT NULL,
+	client_id character varying(30),
+	note character varying(255),
+	reserved_by integer,
+	created_at timestamp with time zone,
+	CONSTRAINT ip_reservations_pkey PRIMARY KEY (id),
+	CONSTRAINT ip_reservations_subnet_id_fkey FOREIGN KEY (subnet_id)
+		REFERENCES subnets (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE CASCADE,
+	CONSTRAINT ip_reservations_reserved_by_fkey FOREIGN KEY (reserved_by)
+		REFERENCES users (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE SET NULL
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE UNIQUE INDEX ip_reservations_address_unique_idx
+	ON ip_reservations
+	USING btree
+	(address);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS client_outages;
+DROP TABLE IF EXISTS client_probes;
@@ -0,0 +1,37 @@
+CREATE TABLE client_probes
+(
+	id serial NOT NULL,
+	client_id character varying(30) NOT NULL,
+	address character varying(39) NOT NULL,
+	up boolean NOT NULL,
+	latency_ms double precision,
+	packet_loss integer NOT NULL DEFAULT 0,
+	created_at timestamp with time zone,
+	CONSTRAINT client_probes_pkey PRIMARY KEY (id)
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE INDEX client_probes_client_id_created_at_idx
+	ON client_probes
+	USING btree
+	(client_id, created_at);
+
+CREATE TABLE client_outages
+(
+	id serial NOT NULL,
+	client_id character varying(30) NOT NULL,
+	address character varying(39) NOT NULL,
+	started_at timestamp with time zone NOT NULL,
+	ended_at timestamp with time zone,
+	CONSTRAINT client_outages_pkey PRIMARY KEY (id)
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE INDEX client_outages_client_id_started_at_idx
+	ON client_outages
+	USING btree
+	(client_id, started_at);
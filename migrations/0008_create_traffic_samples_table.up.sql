@@ -0,0 +1,19 @@
+CREATE TABLE traffic_samples
+(
+	client_id character varying(30) NOT NULL,
+	resolution character varying(2) NOT NULL,
+	bucket timestamp with time zone NOT NULL,
+	upload_bytes bigint NOT NULL DEFAULT 0,
+	download_bytes bigint NOT NULL DEFAULT 0,
+	peak_upload_rate bigint NOT NULL DEFAULT 0,
+	peak_download_rate bigint NOT NULL DEFAULT 0,
+	CONSTRAINT traffic_samples_pkey PRIMARY KEY (client_id, resolution, bucket)
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE INDEX traffic_samples_resolution_bucket_idx
+	ON traffic_samples
+	USING btree
+	(resolution, bucket);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS client_quotas;
+DROP TABLE IF EXISTS quotas;
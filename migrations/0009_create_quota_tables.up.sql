@@ -0,0 +1,44 @@
+CREATE TABLE quotas
+(
+	id serial NOT NULL,
+	name character varying(60) NOT NULL,
+	limit_bytes bigint NOT NULL,
+	throttle_limit character varying(30) NOT NULL,
+	cycle_day integer NOT NULL DEFAULT 1,
+	created_at timestamp with time zone,
+	updated_at timestamp with time zone,
+	CONSTRAINT quotas_pkey PRIMARY KEY (id)
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE UNIQUE INDEX quotas_name_unique_idx
+	ON quotas
+	USING btree
+	(name);
+
+CREATE TABLE client_quotas
+(
+	client_id character varying(30) NOT NULL,
+	quota_id integer NOT NULL,
+	cycle_start timestamp with time zone NOT NULL,
+	throttled boolean NOT NULL DEFAULT false,
+	throttled_at timestamp with time zone,
+	original_max_limit character varying(30),
+	original_burst_limit character varying(30),
+	override_until timestamp with time zone,
+	override_by integer,
+	created_at timestamp with time zone,
+	updated_at timestamp with time zone,
+	CONSTRAINT client_quotas_pkey PRIMARY KEY (client_id),
+	CONSTRAINT client_quotas_quota_id_fkey FOREIGN KEY (quota_id)
+		REFERENCES quotas (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE RESTRICT,
+	CONSTRAINT client_quotas_override_by_fkey FOREIGN KEY (override_by)
+		REFERENCES users (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE SET NULL
+)
+WITH (
+	OIDS=FALSE
+);
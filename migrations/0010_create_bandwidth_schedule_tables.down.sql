@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS client_schedules;
+DROP TABLE IF EXISTS bandwidth_rules;
+DROP TABLE IF EXISTS bandwidth_schedules;
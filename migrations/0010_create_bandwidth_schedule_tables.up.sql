@@ -0,0 +1,64 @@
+CREATE TABLE bandwidth_schedules
+(
+	id serial NOT NULL,
+	name character varying(60) NOT NULL,
+	time_zone character varying(64) NOT NULL,
+	created_at timestamp with time zone,
+	updated_at timestamp with time zone,
+	CONSTRAINT bandwidth_schedules_pkey PRIMARY KEY (id)
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE UNIQUE INDEX bandwidth_schedules_name_unique_idx
+	ON bandwidth_schedules
+	USING btree
+	(name);
+
+CREATE TABLE bandwidth_rules
+(
+	id serial NOT NULL,
+	schedule_id integer NOT NULL,
+	position integer NOT NULL,
+	days character varying(27),
+	start_time character varying(5) NOT NULL,
+	end_time character varying(5) NOT NULL,
+	max_limit character varying(30) NOT NULL,
+	burst_limit character varying(30),
+	burst_threshold character varying(30),
+	burst_time character varying(30),
+	CONSTRAINT bandwidth_rules_pkey PRIMARY KEY (id),
+	CONSTRAINT bandwidth_rules_schedule_id_fkey FOREIGN KEY (schedule_id)
+		REFERENCES bandwidth_schedules (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE CASCADE
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE INDEX bandwidth_rules_schedule_id_idx
+	ON bandwidth_rules
+	USING btree
+	(schedule_id, position);
+
+CREATE TABLE client_schedules
+(
+	client_id character varying(30) NOT NULL,
+	schedule_id integer NOT NULL,
+	base_max_limit character varying(30),
+	base_burst_limit character varying(30),
+	base_burst_threshold character varying(30),
+	base_burst_time character varying(30),
+	active_rule_id integer,
+	applied_at timestamp with time zone,
+	created_at timestamp with time zone,
+	updated_at timestamp with time zone,
+	CONSTRAINT client_schedules_pkey PRIMARY KEY (client_id),
+	CONSTRAINT client_schedules_schedule_id_fkey FOREIGN KEY (schedule_id)
+		REFERENCES bandwidth_schedules (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE RESTRICT
+)
+WITH (
+	OIDS=FALSE
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS billing_accounts;
+DROP TABLE IF EXISTS plans;
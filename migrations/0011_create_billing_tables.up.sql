@@ -0,0 +1,96 @@
+CREATE TABLE plans
+(
+	id serial NOT NULL,
+	name character varying(60) NOT NULL,
+	description character varying(255),
+	price_cents bigint NOT NULL,
+	created_at timestamp with time zone,
+	updated_at timestamp with time zone,
+	CONSTRAINT plans_pkey PRIMARY KEY (id)
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE UNIQUE INDEX plans_name_unique_idx
+	ON plans
+	USING btree
+	(name);
+
+CREATE TABLE billing_accounts
+(
+	client_id character varying(30) NOT NULL,
+	plan_id integer NOT NULL,
+	billing_day integer NOT NULL DEFAULT 1,
+	suspended boolean NOT NULL DEFAULT false,
+	suspended_at timestamp with time zone,
+	created_at timestamp with time zone,
+	updated_at timestamp with time zone,
+	CONSTRAINT billing_accounts_pkey PRIMARY KEY (client_id),
+	CONSTRAINT billing_accounts_plan_id_fkey FOREIGN KEY (plan_id)
+		REFERENCES plans (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE RESTRICT
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE TABLE invoices
+(
+	id serial NOT NULL,
+	client_id character varying(30) NOT NULL,
+	plan_id integer,
+	description character varying(255),
+	period_start timestamp with time zone,
+	period_end timestamp with time zone,
+	amount_cents bigint NOT NULL,
+	paid_cents bigint NOT NULL DEFAULT 0,
+	status character varying(10) NOT NULL,
+	issued_at timestamp with time zone NOT NULL,
+	due_at timestamp with time zone NOT NULL,
+	paid_at timestamp with time zone,
+	created_at timestamp with time zone,
+	updated_at timestamp with time zone,
+	CONSTRAINT invoices_pkey PRIMARY KEY (id),
+	CONSTRAINT invoices_plan_id_fkey FOREIGN KEY (plan_id)
+		REFERENCES plans (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE SET NULL
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE INDEX invoices_client_id_idx
+	ON invoices
+	USING btree
+	(client_id, due_at);
+
+CREATE UNIQUE INDEX invoices_client_id_period_start_unique_idx
+	ON invoices
+	USING btree
+	(client_id, period_start);
+
+CREATE TABLE payments
+(
+	id serial NOT NULL,
+	client_id character varying(30) NOT NULL,
+	amount_cents bigint NOT NULL,
+	method character varying(20) NOT NULL,
+	reference character varying(100),
+	notes character varying(255),
+	operator_id integer,
+	received_at timestamp with time zone NOT NULL,
+	created_at timestamp with time zone,
+	CONSTRAINT payments_pkey PRIMARY KEY (id),
+	CONSTRAINT payments_operator_id_fkey FOREIGN KEY (operator_id)
+		REFERENCES users (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE SET NULL
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE INDEX payments_client_id_idx
+	ON payments
+	USING btree
+	(client_id, received_at);
@@ -0,0 +1,31 @@
+CREATE TABLE documents
+(
+	id serial NOT NULL,
+	client_id character varying(30) NOT NULL,
+	kind character varying(10) NOT NULL,
+	invoice_id integer,
+	payment_id integer,
+	file_name character varying(100) NOT NULL,
+	content bytea NOT NULL,
+	size integer NOT NULL,
+	created_by integer,
+	created_at timestamp with time zone,
+	CONSTRAINT documents_pkey PRIMARY KEY (id),
+	CONSTRAINT documents_invoice_id_fkey FOREIGN KEY (invoice_id)
+		REFERENCES invoices (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE CASCADE,
+	CONSTRAINT documents_payment_id_fkey FOREIGN KEY (payment_id)
+		REFERENCES payments (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE CASCADE,
+	CONSTRAINT documents_created_by_fkey FOREIGN KEY (created_by)
+		REFERENCES users (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE SET NULL
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE INDEX documents_client_id_idx
+	ON documents
+	USING btree
+	(client_id, created_at);
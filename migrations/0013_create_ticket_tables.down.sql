@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ticket_diagnostics;
+DROP TABLE IF EXISTS ticket_comments;
+DROP TABLE IF EXISTS tickets;
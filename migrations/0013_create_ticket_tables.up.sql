@@ -0,0 +1,86 @@
+CREATE TABLE tickets
+(
+	id serial NOT NULL,
+	client_id character varying(30) NOT NULL,
+	target character varying(43),
+	subject character varying(120) NOT NULL,
+	description text,
+	status character varying(15) NOT NULL,
+	assignee_id integer,
+	created_by integer,
+	resolved_at timestamp with time zone,
+	created_at timestamp with time zone,
+	updated_at timestamp with time zone,
+	CONSTRAINT tickets_pkey PRIMARY KEY (id),
+	CONSTRAINT tickets_assignee_id_fkey FOREIGN KEY (assignee_id)
+		REFERENCES users (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE SET NULL,
+	CONSTRAINT tickets_created_by_fkey FOREIGN KEY (created_by)
+		REFERENCES users (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE SET NULL
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE INDEX tickets_client_id_idx
+	ON tickets
+	USING btree
+	(client_id, created_at);
+
+CREATE INDEX tickets_status_idx
+	ON tickets
+	USING btree
+	(status);
+
+CREATE TABLE ticket_comments
+(
+	id serial NOT NULL,
+	ticket_id integer NOT NULL,
+	user_id integer,
+	body text NOT NULL,
+	created_at timestamp with time zone,
+	CONSTRAINT ticket_comments_pkey PRIMARY KEY (id),
+	CONSTRAINT ticket_comments_ticket_id_fkey FOREIGN KEY (ticket_id)
+		REFERENCES tickets (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE CASCADE,
+	CONSTRAINT ticket_comments_user_id_fkey FOREIGN KEY (user_id)
+		REFERENCES users (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE SET NULL
+)
+WITH (
+	OIDS=FALSE
+);
+
+CREATE INDEX ticket_comments_ticket_id_idx
+	ON ticket_comments
+	USING btree
+	(ticket_id, created_at);
+
+CREATE TABLE ticket_diagnostics
+(
+	ticket_id integer NOT NULL,
+	client_name character varying(100),
+	max_limit character varying(30),
+	burst_limit character varying(30),
+	burst_threshold character varying(30),
+	burst_time character varying(30),
+	suspended boolean NOT NULL DEFAULT false,
+	ping_address character varying(39),
+	ping_sent integer NOT NULL DEFAULT 0,
+	ping_received integer NOT NULL DEFAULT 0,
+	ping_packet_loss integer,
+	ping_avg_rtt double precision,
+	reachable boolean,
+	monitor_status character varying(10),
+	last_probe_at timestamp with time zone,
+	error text,
+	created_at timestamp with time zone,
+	CONSTRAINT ticket_diagnostics_pkey PRIMARY KEY (ticket_id),
+	CONSTRAINT ticket_diagnostics_ticket_id_fkey FOREIGN KEY (ticket_id)
+		REFERENCES tickets (id) MATCH SIMPLE
+		ON UPDATE NO ACTION ON DELETE CASCADE
+)
+WITH (
+	OIDS=FALSE
+);
@@ -8,6 +8,7 @@ INSERT INTO users(
 	first_name,
 	last_name,
 	status,
+	role,
 	created_at,
 	updated_at
 ) VALUES (
@@ -17,6 +18,7 @@ INSERT INTO users(
 	'Administrador',
 	'Administrador',
 	1,
+	'admin',
 	timezone('UTC', now()),
 	timezone('UTC', now())
 )
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Alert is a condition detected by the alerting service. Key identifies the
+// condition, e.g. the client that is down, so that it's notified only once
+// while it lasts. Status is "firing" or "resolved"; ResolvedAt is nil while
+// the alert is firing.
+type Alert struct {
+	Key        string     `json:"key"`
+	Rule       string     `json:"rule"`
+	Status     string     `json:"status"`
+	Subject    string     `json:"subject"`
+	Message    string     `json:"message"`
+	StartedAt  time.Time  `json:"startedAt"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// APIToken model. Only the SHA-256 hash of the token's secret is stored.
+// Prefix contains the first characters of the secret so users can tell
+// their tokens apart.
+type APIToken struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"userId"`
+	Name       string     `json:"name" sql:"size:60; not null"`
+	Prefix     string     `json:"prefix" sql:"size:8; not null"`
+	TokenHash  string     `json:"-" sql:"size:64; unique_index; not null"`
+	Scopes     string     `json:"scopes" sql:"size:255"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	DeletedAt  *time.Time `json:"-"`
+}
+
+// TableName sets APIToken's table name to be `api_tokens`.
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
+
+// HasScope checks if the scope was granted to the token.
+func (t *APIToken) HasScope(scope string) bool {
+	for _, s := range strings.Split(t.Scopes, ",") {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsExpired checks if the token's expiration date has passed.
+func (t *APIToken) IsExpired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
@@ -0,0 +1,24 @@
+package models
+
+// ARPEntry describes a /ip/arp entry.
+type ARPEntry struct {
+	ID         string `json:"id"`
+	Address    string `json:"address" validate:"required"`
+	MACAddress string `json:"macAddress" validate:"required"`
+	Interface  string `json:"interface"`
+	Comment    string `json:"comment"`
+	Dynamic    bool   `json:"dynamic"`
+	Complete   bool   `json:"complete"`
+	Disabled   bool   `json:"disabled"`
+}
+
+// Lookup contains everything the router knows about an IP or MAC address.
+// Fields are nil if the router has no matching entry.
+type Lookup struct {
+	Address    string     `json:"address"`
+	MACAddress string     `json:"macAddress"`
+	Interface  string     `json:"interface"`
+	ARPEntry   *ARPEntry  `json:"arpEntry"`
+	DHCPLease  *DHCPLease `json:"dhcpLease"`
+	Client     *Client    `json:"client"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Plan model. A service plan with the price charged to its clients every
+// month. Amounts are in cents of the configured currency.
+type Plan struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name" sql:"size:60; unique_index; not null"`
+	Description string    `json:"description" sql:"size:255"`
+	PriceCents  int64     `json:"priceCents"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// BillingAccount model. The plan of a client and the day of the month its
+// invoices are issued. Suspended is set while the client is suspended by the
+// billing job for its overdue invoices.
+type BillingAccount struct {
+	ClientID    string     `json:"clientId" sql:"size:30; not null" gorm:"primary_key"`
+	PlanID      int        `json:"planId"`
+	BillingDay  int        `json:"billingDay"`
+	Suspended   bool       `json:"suspended"`
+	SuspendedAt *time.Time `json:"suspendedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// Invoice model. The monthly charge of a client's plan for a period, or a
+// charge added by an operator, which has no plan nor period. Payments are
+// applied to the invoices in the order they are due.
+type Invoice struct {
+	ID          int        `json:"id"`
+	ClientID    string     `json:"clientId" sql:"size:30; not null"`
+	PlanID      *int       `json:"planId"`
+	Description string     `json:"description" sql:"size:255"`
+	PeriodStart *time.Time `json:"periodStart"`
+	PeriodEnd   *time.Time `json:"periodEnd"`
+	AmountCents int64      `json:"amountCents"`
+	PaidCents   int64      `json:"paidCents"`
+	Status      string     `json:"status" sql:"size:10; not null"`
+	IssuedAt    time.Time  `json:"issuedAt"`
+	DueAt       time.Time  `json:"dueAt"`
+	PaidAt      *time.Time `json:"paidAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// Payment model. A payment of a client registered by an operator.
+type Payment struct {
+	ID          int       `json:"id"`
+	ClientID    string    `json:"clientId" sql:"size:30; not null"`
+	AmountCents int64     `json:"amountCents"`
+	Method      string    `json:"method" sql:"size:20; not null"`
+	Reference   string    `json:"reference" sql:"size:100"`
+	Notes       string    `json:"notes" sql:"size:255"`
+	OperatorID  *int      `json:"operatorId"`
+	ReceivedAt  time.Time `json:"receivedAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// AccountStatus is the balance of a client's account. BalanceCents is the
+// amount owed, negative if the client has credit. The account is overdue
+// if an invoice is unpaid after its due date.
+type AccountStatus struct {
+	BillingAccount
+	Plan         Plan       `json:"plan"`
+	Currency     string     `json:"currency"`
+	BalanceCents int64      `json:"balanceCents"`
+	OverdueCents int64      `json:"overdueCents"`
+	Overdue      bool       `json:"overdue"`
+	OverdueSince *time.Time `json:"overdueSince"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 type Client struct {
 	ID             string `json:"id"`
 	Name           string `json:"name"`
@@ -8,4 +10,33 @@ type Client struct {
 	BurstLimit     string `json:"burstLimit"`
 	BurstThreshold string `json:"burstThreshold"`
 	BurstTime      string `json:"burstTime"`
+	Suspended      bool   `json:"suspended"`
+	MACAddress     string `json:"macAddress"`
+	HostName       string `json:"hostName"`
+}
+
+// TargetAddresses returns the IP addresses and subnets of the queue's target.
+// Single host addresses are returned without the /32 suffix, the same way
+// RouterOS prints them in address lists.
+func (c *Client) TargetAddresses() []string {
+	var addresses []string
+
+	for _, target := range strings.Split(c.Target, ",") {
+		target = strings.TrimSuffix(strings.TrimSpace(target), "/32")
+
+		if target != "" {
+			addresses = append(addresses, target)
+		}
+	}
+
+	return addresses
+}
+
+// QueueLimits are the bandwidth limits of a simple queue, with the
+// upload/download format RouterOS uses, e.g. "512k/2M".
+type QueueLimits struct {
+	MaxLimit       string `json:"maxLimit"`
+	BurstLimit     string `json:"burstLimit"`
+	BurstThreshold string `json:"burstThreshold"`
+	BurstTime      string `json:"burstTime"`
 }
@@ -0,0 +1,15 @@
+package models
+
+// DHCPLease describes a /ip/dhcp-server/lease entry.
+type DHCPLease struct {
+	ID         string `json:"id"`
+	Address    string `json:"address"`
+	MACAddress string `json:"macAddress"`
+	HostName   string `json:"hostName"`
+	Server     string `json:"server"`
+	Status     string `json:"status"`
+	LastSeen   string `json:"lastSeen"`
+	Comment    string `json:"comment"`
+	Dynamic    bool   `json:"dynamic"`
+	Disabled   bool   `json:"disabled"`
+}
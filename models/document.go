@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Document model. A PDF invoice or payment receipt stored when it was
+// generated, so the same copy can be printed again. Content is omitted
+// when listing documents.
+type Document struct {
+	ID        int       `json:"id"`
+	ClientID  string    `json:"clientId" sql:"size:30; not null"`
+	Kind      string    `json:"kind" sql:"size:10; not null"`
+	InvoiceID *int      `json:"invoiceId"`
+	PaymentID *int      `json:"paymentId"`
+	FileName  string    `json:"fileName" sql:"size:100; not null"`
+	Content   []byte    `json:"-"`
+	Size      int       `json:"size"`
+	CreatedBy *int      `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
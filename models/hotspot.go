@@ -0,0 +1,34 @@
+package models
+
+// HotspotUser describes a /ip/hotspot/user entry. LimitUptime uses the
+// RouterOS time format, e.g. "1h30m". LimitBytesTotal is 0 if the user's
+// traffic is not limited.
+type HotspotUser struct {
+	ID              string `json:"id"`
+	Name            string `json:"name" validate:"required"`
+	Password        string `json:"password"`
+	Profile         string `json:"profile"`
+	Server          string `json:"server"`
+	LimitUptime     string `json:"limitUptime"`
+	LimitBytesTotal int64  `json:"limitBytesTotal"`
+	Comment         string `json:"comment"`
+	Disabled        bool   `json:"disabled"`
+	Uptime          string `json:"uptime"`
+	BytesIn         int64  `json:"bytesIn"`
+	BytesOut        int64  `json:"bytesOut"`
+}
+
+// HotspotSession describes a /ip/hotspot/active entry, a logged in hotspot
+// user.
+type HotspotSession struct {
+	ID              string `json:"id"`
+	User            string `json:"user"`
+	Server          string `json:"server"`
+	Address         string `json:"address"`
+	MACAddress      string `json:"macAddress"`
+	LoginBy         string `json:"loginBy"`
+	Uptime          string `json:"uptime"`
+	SessionTimeLeft string `json:"sessionTimeLeft"`
+	BytesIn         int64  `json:"bytesIn"`
+	BytesOut        int64  `json:"bytesOut"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// Interface describes a /interface entry and its counters.
+type Interface struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	MACAddress string `json:"macAddress"`
+	MTU        string `json:"mtu"`
+	Comment    string `json:"comment"`
+	Running    bool   `json:"running"`
+	Disabled   bool   `json:"disabled"`
+	RxBytes    int64  `json:"rxBytes"`
+	TxBytes    int64  `json:"txBytes"`
+	RxPackets  int64  `json:"rxPackets"`
+	TxPackets  int64  `json:"txPackets"`
+	RxErrors   int64  `json:"rxErrors"`
+	TxErrors   int64  `json:"txErrors"`
+	RxDrops    int64  `json:"rxDrops"`
+	TxDrops    int64  `json:"txDrops"`
+}
+
+// InterfaceTraffic contains the current traffic rates of an interface as
+// reported by /interface/monitor-traffic.
+type InterfaceTraffic struct {
+	Name               string `json:"name"`
+	RxBitsPerSecond    int64  `json:"rxBitsPerSecond"`
+	TxBitsPerSecond    int64  `json:"txBitsPerSecond"`
+	RxPacketsPerSecond int64  `json:"rxPacketsPerSecond"`
+	TxPacketsPerSecond int64  `json:"txPacketsPerSecond"`
+}
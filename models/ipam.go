@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Subnet model. A range of private addresses of a router from which client
+// addresses are allocated. The gateway is never allocated.
+type Subnet struct {
+	ID        int       `json:"id"`
+	Router    string    `json:"router" sql:"size:255; not null"`
+	Name      string    `json:"name" sql:"size:60; not null"`
+	CIDR      string    `json:"cidr" sql:"size:43; not null"`
+	Gateway   string    `json:"gateway" sql:"size:39"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// IPReservation model. An address of a subnet that is set aside for a client
+// before it's assigned on the router. ClientID is the ID of the client's
+// simple queue, if the client already exists.
+type IPReservation struct {
+	ID         int       `json:"id"`
+	SubnetID   int       `json:"subnetId"`
+	Address    string    `json:"address" sql:"size:39; unique_index; not null"`
+	ClientID   string    `json:"clientId" sql:"size:30"`
+	Note       string    `json:"note" sql:"size:255"`
+	ReservedBy *int      `json:"reservedBy"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName sets IPReservation's table name to be `ip_reservations`.
+func (IPReservation) TableName() string {
+	return "ip_reservations"
+}
+
+// UsedAddress describes an address of a subnet that is in use and where it
+// was found: "gateway", "queue", "lease", "arp" or "reservation".
+type UsedAddress struct {
+	Address    string   `json:"address"`
+	Sources    []string `json:"sources"`
+	ClientID   string   `json:"clientId,omitempty"`
+	ClientName string   `json:"clientName,omitempty"`
+	MACAddress string   `json:"macAddress,omitempty"`
+}
+
+// SubnetUsage describes which addresses of a subnet are in use. Size is the
+// number of usable host addresses. NextFree is empty if the subnet is full.
+type SubnetUsage struct {
+	Subnet    Subnet        `json:"subnet"`
+	Size      int           `json:"size"`
+	Used      int           `json:"used"`
+	Free      int           `json:"free"`
+	NextFree  string        `json:"nextFree"`
+	Addresses []UsedAddress `json:"addresses"`
+}
+
+// AddressConflict describes an address that is the target of more than one
+// client.
+type AddressConflict struct {
+	Address string   `json:"address"`
+	Clients []Client `json:"clients"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// LogEntry describes a /log entry of the router.
+type LogEntry struct {
+	ID      string `json:"id"`
+	Time    string `json:"time"`
+	Topics  string `json:"topics"`
+	Message string `json:"message"`
+}
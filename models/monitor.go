@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// PingResult contains the outcome of pinging an address from the router.
+// AvgRTT is the average round trip time in milliseconds of the replies
+// received.
+type PingResult struct {
+	Address    string  `json:"address"`
+	Sent       int     `json:"sent"`
+	Received   int     `json:"received"`
+	PacketLoss int     `json:"packetLoss"`
+	AvgRTT     float64 `json:"avgRtt"`
+}
+
+// ClientProbe model. Records the result of each time the monitoring service
+// pinged a client's address. LatencyMs is nil if the client didn't reply.
+type ClientProbe struct {
+	ID         int       `json:"id"`
+	ClientID   string    `json:"clientId" sql:"size:30; not null"`
+	Address    string    `json:"address" sql:"size:39; not null"`
+	Up         bool      `json:"up"`
+	LatencyMs  *float64  `json:"latencyMs"`
+	PacketLoss int       `json:"packetLoss"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// ClientOutage model. A period during which a client didn't reply to any
+// probe. EndedAt is nil while the outage is ongoing.
+type ClientOutage struct {
+	ID        int        `json:"id"`
+	ClientID  string     `json:"clientId" sql:"size:30; not null"`
+	Address   string     `json:"address" sql:"size:39; not null"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt"`
+}
+
+// ClientAvailability summarizes the probes of a client since a date.
+// Availability is the percentage of probes the client replied to, or nil if
+// the client wasn't probed. Status is "up", "down" or "unknown".
+type ClientAvailability struct {
+	ClientID     string         `json:"clientId"`
+	Since        time.Time      `json:"since"`
+	Status       string         `json:"status"`
+	Probes       int            `json:"probes"`
+	Availability *float64       `json:"availability"`
+	AvgLatencyMs *float64       `json:"avgLatencyMs"`
+	LastProbeAt  *time.Time     `json:"lastProbeAt"`
+	Outages      []ClientOutage `json:"outages"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// PPPSecret describes a /ppp/secret entry, the credentials used by PPPoE
+// clients to connect.
+type PPPSecret struct {
+	ID            string `json:"id"`
+	Name          string `json:"name" validate:"required"`
+	Password      string `json:"password"`
+	Service       string `json:"service"`
+	Profile       string `json:"profile"`
+	LocalAddress  string `json:"localAddress"`
+	RemoteAddress string `json:"remoteAddress"`
+	CallerID      string `json:"callerId"`
+	Comment       string `json:"comment"`
+	Disabled      bool   `json:"disabled"`
+	LastLoggedOut string `json:"lastLoggedOut"`
+}
+
+// PPPSession describes a /ppp/active entry, a connected PPP client.
+type PPPSession struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Service  string `json:"service"`
+	CallerID string `json:"callerId"`
+	Address  string `json:"address"`
+	Uptime   string `json:"uptime"`
+	Encoding string `json:"encoding"`
+}
@@ -0,0 +1,33 @@
+package models
+
+// QueueType describes a /queue/type entry. The PCQ fields are only used by
+// queue types of the pcq kind. Default types are built into RouterOS and
+// can't be modified.
+type QueueType struct {
+	ID            string `json:"id"`
+	Name          string `json:"name" validate:"required"`
+	Kind          string `json:"kind"`
+	PCQRate       string `json:"pcqRate"`
+	PCQClassifier string `json:"pcqClassifier"`
+	PCQLimit      string `json:"pcqLimit"`
+	PCQTotalLimit string `json:"pcqTotalLimit"`
+	Default       bool   `json:"default"`
+}
+
+// QueueTree describes a /queue/tree entry. Entries whose parent is another
+// entry are nested in that entry's Children, the rest are attached to an
+// interface or to global.
+type QueueTree struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Parent     string       `json:"parent"`
+	PacketMark string       `json:"packetMark"`
+	LimitAt    string       `json:"limitAt"`
+	MaxLimit   string       `json:"maxLimit"`
+	Priority   int          `json:"priority"`
+	Queue      string       `json:"queue"`
+	Comment    string       `json:"comment"`
+	Disabled   bool         `json:"disabled"`
+	Invalid    bool         `json:"invalid"`
+	Children   []*QueueTree `json:"children"`
+}
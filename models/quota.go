@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Quota model. A monthly data cap that can be assigned to one or many
+// clients. The cycle starts on CycleDay of every month; once a client's
+// traffic in the cycle reaches LimitBytes, its queue's max-limit is lowered
+// to ThrottleLimit until the next cycle.
+type Quota struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name" sql:"size:60; unique_index; not null"`
+	LimitBytes    int64     `json:"limitBytes"`
+	ThrottleLimit string    `json:"throttleLimit" sql:"size:30; not null"`
+	CycleDay      int       `json:"cycleDay"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// ClientQuota model. The quota assigned to a client and the state of its
+// current cycle. The queue's limits before throttling are kept so they can
+// be restored. While OverrideUntil is in the future, the client is not
+// throttled.
+type ClientQuota struct {
+	ClientID           string     `json:"clientId" sql:"size:30; not null" gorm:"primary_key"`
+	QuotaID            int        `json:"quotaId"`
+	CycleStart         time.Time  `json:"cycleStart"`
+	Throttled          bool       `json:"throttled"`
+	ThrottledAt        *time.Time `json:"throttledAt"`
+	OriginalMaxLimit   string     `json:"originalMaxLimit,omitempty" sql:"size:30"`
+	OriginalBurstLimit string     `json:"originalBurstLimit,omitempty" sql:"size:30"`
+	OverrideUntil      *time.Time `json:"overrideUntil"`
+	OverrideBy         *int       `json:"overrideBy"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+}
+
+// QuotaStatus is the consumption of a client in its current cycle.
+type QuotaStatus struct {
+	ClientQuota
+	Quota          Quota     `json:"quota"`
+	CycleEnd       time.Time `json:"cycleEnd"`
+	UsedBytes      int64     `json:"usedBytes"`
+	RemainingBytes int64     `json:"remainingBytes"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// RouterBackup model. Stores the script produced by running /export on a
+// router. Content is omitted when listing backups.
+type RouterBackup struct {
+	ID        int       `json:"id"`
+	Router    string    `json:"router" sql:"size:255; not null"`
+	Content   string    `json:"content,omitempty" sql:"type:text; not null"`
+	Size      int       `json:"size"`
+	Checksum  string    `json:"checksum" sql:"size:64; not null"`
+	Trigger   string    `json:"trigger" sql:"size:10; not null"`
+	CreatedBy *int      `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
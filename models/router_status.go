@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// RouterStatus is the status document of a router. It gathers the router's
+// resources, identity, health and the number of managed entries.
+type RouterStatus struct {
+	Identity     string            `json:"identity"`
+	Address      string            `json:"address"`
+	Version      string            `json:"version"`
+	BoardName    string            `json:"boardName"`
+	Architecture string            `json:"architecture"`
+	Uptime       string            `json:"uptime"`
+	CPU          string            `json:"cpu"`
+	CPUCount     int64             `json:"cpuCount"`
+	CPULoad      int64             `json:"cpuLoad"`
+	FreeMemory   int64             `json:"freeMemory"`
+	TotalMemory  int64             `json:"totalMemory"`
+	FreeHDD      int64             `json:"freeHdd"`
+	TotalHDD     int64             `json:"totalHdd"`
+	Health       map[string]string `json:"health"`
+	RouterBoard  *RouterBoard      `json:"routerBoard"`
+	QueueCount   int64             `json:"queueCount"`
+	LeaseCount   int64             `json:"leaseCount"`
+	PPPCount     int64             `json:"pppCount"`
+	CheckedAt    time.Time         `json:"checkedAt"`
+}
+
+// RouterBoard contains the hardware information of RouterBOARD devices.
+type RouterBoard struct {
+	Model           string `json:"model"`
+	SerialNumber    string `json:"serialNumber"`
+	FirmwareType    string `json:"firmwareType"`
+	CurrentFirmware string `json:"currentFirmware"`
+	UpgradeFirmware string `json:"upgradeFirmware"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// BandwidthSchedule model. A set of time-of-day rules that change the
+// limits of the clients' queues, e.g. a "night boost" between 00:00 and
+// 06:00. The rules' times are in the schedule's IANA time zone.
+type BandwidthSchedule struct {
+	ID        int             `json:"id"`
+	Name      string          `json:"name" sql:"size:60; unique_index; not null"`
+	TimeZone  string          `json:"timeZone" sql:"size:64; not null"`
+	Rules     []BandwidthRule `json:"rules" sql:"-"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// BandwidthRule model. The limits applied from Start until End, "HH:MM", on
+// the days of the week, e.g. ["mon", "tue"]. No days means every day. If
+// End is before Start, the rule lasts until End of the next day. When rules
+// overlap, the first one wins.
+type BandwidthRule struct {
+	ID         int      `json:"id"`
+	ScheduleID int      `json:"-"`
+	Position   int      `json:"-"`
+	Days       []string `json:"days" sql:"-"`
+	DayList    string   `json:"-" sql:"column:days; size:27"`
+	Start      string   `json:"start" sql:"column:start_time; size:5; not null"`
+	End        string   `json:"end" sql:"column:end_time; size:5; not null"`
+	QueueLimits
+}
+
+// ClientSchedule model. The schedule assigned to a client. The base limits
+// are applied when none of the schedule's rules is active. ActiveRuleID is
+// the rule applied to the queue, or nil if it has the base limits.
+type ClientSchedule struct {
+	ClientID           string     `json:"clientId" sql:"size:30; not null" gorm:"primary_key"`
+	ScheduleID         int        `json:"scheduleId"`
+	BaseMaxLimit       string     `json:"baseMaxLimit" sql:"size:30"`
+	BaseBurstLimit     string     `json:"baseBurstLimit" sql:"size:30"`
+	BaseBurstThreshold string     `json:"baseBurstThreshold" sql:"size:30"`
+	BaseBurstTime      string     `json:"baseBurstTime" sql:"size:30"`
+	ActiveRuleID       *int       `json:"activeRuleId"`
+	AppliedAt          *time.Time `json:"appliedAt"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+}
+
+// BaseLimits returns the limits applied when no rule is active.
+func (c *ClientSchedule) BaseLimits() QueueLimits {
+	return QueueLimits{
+		MaxLimit:       c.BaseMaxLimit,
+		BurstLimit:     c.BaseBurstLimit,
+		BurstThreshold: c.BaseBurstThreshold,
+		BurstTime:      c.BaseBurstTime,
+	}
+}
+
+// ScheduleChange is a scheduled change of a client's limits. RuleID is nil
+// when the base limits are restored.
+type ScheduleChange struct {
+	At     time.Time   `json:"at"`
+	RuleID *int        `json:"ruleId"`
+	Limits QueueLimits `json:"limits"`
+}
+
+// ClientScheduleStatus reports the limits a client has and the next time
+// they change. NextChange is nil if the schedule has no rules.
+type ClientScheduleStatus struct {
+	ClientSchedule
+	ScheduleName  string          `json:"scheduleName"`
+	CurrentLimits QueueLimits     `json:"currentLimits"`
+	NextChange    *ScheduleChange `json:"nextChange"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// SuspensionEvent model. Records every time a client was suspended or
+// restored, why and by whom. OperatorID is nil for automatic suspensions.
+type SuspensionEvent struct {
+	ID         int       `json:"id"`
+	ClientID   string    `json:"clientId" sql:"size:30; not null"`
+	ClientName string    `json:"clientName" sql:"size:255"`
+	Target     string    `json:"target" sql:"size:255"`
+	Action     string    `json:"action" sql:"size:10; not null"`
+	Reason     string    `json:"reason" sql:"size:255"`
+	OperatorID *int      `json:"operatorId"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
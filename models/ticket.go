@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Ticket model. A complaint or support request of a client. Target is the
+// queue target address the ticket is about, if any. ResolvedAt is set while
+// the ticket is resolved.
+type Ticket struct {
+	ID          int                `json:"id"`
+	ClientID    string             `json:"clientId" sql:"size:30; not null"`
+	Target      string             `json:"target" sql:"size:43"`
+	Subject     string             `json:"subject" sql:"size:120; not null"`
+	Description string             `json:"description"`
+	Status      string             `json:"status" sql:"size:15; not null"`
+	AssigneeID  *int               `json:"assigneeId"`
+	CreatedBy   *int               `json:"createdBy"`
+	ResolvedAt  *time.Time         `json:"resolvedAt"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+	Diagnostics *TicketDiagnostics `json:"diagnostics,omitempty" sql:"-"`
+	Comments    []TicketComment    `json:"comments,omitempty" sql:"-"`
+}
+
+// TicketComment model. A note added to a ticket by a user.
+type TicketComment struct {
+	ID        int       `json:"id"`
+	TicketID  int       `json:"ticketId"`
+	UserID    *int      `json:"userId"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TicketDiagnostics model. The state of the client when the ticket was
+// created: its queue limits, a ping from the router to its address and the
+// status reported by the monitoring service. Error contains the reason the
+// router could not be queried, in which case only the monitoring fields are
+// set.
+type TicketDiagnostics struct {
+	TicketID   int    `json:"ticketId" gorm:"primary_key"`
+	ClientName string `json:"clientName" sql:"size:100"`
+	QueueLimits
+	Suspended      bool       `json:"suspended"`
+	PingAddress    string     `json:"pingAddress" sql:"size:39"`
+	PingSent       int        `json:"pingSent"`
+	PingReceived   int        `json:"pingReceived"`
+	PingPacketLoss *int       `json:"pingPacketLoss"`
+	PingAvgRTT     *float64   `json:"pingAvgRtt" gorm:"column:ping_avg_rtt"`
+	Reachable      *bool      `json:"reachable"`
+	MonitorStatus  string     `json:"monitorStatus" sql:"size:10"`
+	LastProbeAt    *time.Time `json:"lastProbeAt"`
+	Error          string     `json:"error"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
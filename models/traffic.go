@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// QueueStats contains the counters of a simple queue. Upload is the traffic
+// sent by the queue's target and download the traffic it received. Rates
+// are in bits per second.
+type QueueStats struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	UploadBytes   int64  `json:"uploadBytes"`
+	DownloadBytes int64  `json:"downloadBytes"`
+	UploadRate    int64  `json:"uploadRate"`
+	DownloadRate  int64  `json:"downloadRate"`
+}
+
+// TrafficSample model. The traffic of a client during one bucket of a
+// resolution, e.g. the hour that starts at Bucket. The peak rates are the
+// highest rates seen when the queue's counters were sampled.
+type TrafficSample struct {
+	ClientID         string    `json:"-" sql:"size:30; not null"`
+	Resolution       string    `json:"-" sql:"size:2; not null"`
+	Bucket           time.Time `json:"time"`
+	UploadBytes      int64     `json:"uploadBytes"`
+	DownloadBytes    int64     `json:"downloadBytes"`
+	PeakUploadRate   int64     `json:"peakUploadRate"`
+	PeakDownloadRate int64     `json:"peakDownloadRate"`
+}
+
+// ClientUsage is the traffic of a client between two dates. Samples only
+// contains the buckets with traffic, and is omitted in rankings.
+type ClientUsage struct {
+	ClientID      string          `json:"clientId"`
+	ClientName    string          `json:"clientName,omitempty"`
+	From          time.Time       `json:"from"`
+	To            time.Time       `json:"to"`
+	Resolution    string          `json:"resolution"`
+	UploadBytes   int64           `json:"uploadBytes"`
+	DownloadBytes int64           `json:"downloadBytes"`
+	TotalBytes    int64           `json:"totalBytes"`
+	Samples       []TrafficSample `json:"samples,omitempty"`
+}
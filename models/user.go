@@ -13,6 +13,7 @@ type User struct {
 	FirstName string `sql:"size:60"`
 	LastName  string `sql:"size:60"`
 	Status    int
+	Role      string `sql:"size:20; not null"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
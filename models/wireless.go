@@ -0,0 +1,23 @@
+package models
+
+// WirelessRegistration describes a /interface/wireless/registration-table
+// entry, a station connected to one of the router's wireless interfaces.
+// Signal values are in dBm and CCQ values are percentages. ClientID and
+// ClientName identify the client whose queue targets the station's IP or
+// whose DHCP lease has the station's MAC address, if any.
+type WirelessRegistration struct {
+	ID             string `json:"id"`
+	Interface      string `json:"interface"`
+	MACAddress     string `json:"macAddress"`
+	RadioName      string `json:"radioName"`
+	LastIP         string `json:"lastIp"`
+	SignalStrength int    `json:"signalStrength"`
+	SignalToNoise  int    `json:"signalToNoise"`
+	TxCCQ          int    `json:"txCcq"`
+	RxCCQ          int    `json:"rxCcq"`
+	TxRate         string `json:"txRate"`
+	RxRate         string `json:"rxRate"`
+	Uptime         string `json:"uptime"`
+	ClientID       string `json:"clientId,omitempty"`
+	ClientName     string `json:"clientName,omitempty"`
+}
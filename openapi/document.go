@@ -0,0 +1,197 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Operation describes a single API route.
+type Operation struct {
+	Method       string
+	Path         string
+	Summary      string
+	Request      interface{}
+	Response     interface{}
+	QueryParams  []string
+	RequiresAuth bool
+	Scope        string
+}
+
+// Document is an OpenAPI 3 document.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components components                       `json:"components"`
+}
+
+// Info contains the API's metadata.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*securityScheme `json:"securitySchemes"`
+}
+
+type securityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+type operation struct {
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*parameter          `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type response struct {
+	Description string                `json:"description"`
+	Content     map[string]*mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// pathParamRegexp matches gorilla/mux path variables, which use the same
+// {name} syntax as OpenAPI path templates.
+var pathParamRegexp = regexp.MustCompile(`{([^}:]+)(:[^}]+)?}`)
+
+// NewDocument creates the OpenAPI document that describes the operations.
+// Named struct types are added to the document's components and referenced
+// from the operations.
+func NewDocument(title, version, sessionCookieName string, ops []Operation) *Document {
+	var (
+		g   = &generator{components: map[string]*Schema{}}
+		doc = &Document{
+			OpenAPI: "3.0.3",
+			Info: Info{
+				Title:   title,
+				Version: version,
+			},
+			Paths: map[string]map[string]*operation{},
+			Components: components{
+				Schemas: g.components,
+				SecuritySchemes: map[string]*securityScheme{
+					"cookieAuth": {
+						Type: "apiKey",
+						In:   "cookie",
+						Name: sessionCookieName,
+					},
+					"bearerAuth": {
+						Type:   "http",
+						Scheme: "bearer",
+					},
+				},
+			},
+		}
+	)
+
+	for _, op := range ops {
+		path := pathParamRegexp.ReplaceAllString(op.Path, "{$1}")
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*operation{}
+		}
+
+		doc.Paths[path][strings.ToLower(op.Method)] = g.operation(op)
+	}
+
+	return doc
+}
+
+func (g *generator) operation(op Operation) *operation {
+	o := &operation{
+		Summary:   op.Summary,
+		Tags:      []string{tag(op.Path)},
+		Responses: map[string]*response{},
+	}
+
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(op.Path, -1) {
+		o.Parameters = append(o.Parameters, &parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	for _, name := range op.QueryParams {
+		o.Parameters = append(o.Parameters, &parameter{
+			Name:   name,
+			In:     "query",
+			Schema: &Schema{Type: "string"},
+		})
+	}
+
+	if op.Request != nil {
+		o.RequestBody = &requestBody{
+			Required: true,
+			Content: map[string]*mediaType{
+				"application/json": {Schema: g.schema(reflect.TypeOf(op.Request))},
+			},
+		}
+	}
+
+	ok := &response{Description: http.StatusText(http.StatusOK)}
+	if op.Response != nil {
+		ok.Content = map[string]*mediaType{
+			"application/json": {Schema: g.schema(reflect.TypeOf(op.Response))},
+		}
+	}
+
+	o.Responses["200"] = ok
+
+	if op.Request != nil || len(op.QueryParams) > 0 {
+		o.Responses["400"] = &response{Description: http.StatusText(http.StatusBadRequest)}
+	}
+
+	if op.RequiresAuth {
+		o.Responses["401"] = &response{Description: http.StatusText(http.StatusUnauthorized)}
+		o.Security = []map[string][]string{
+			{"cookieAuth": {}},
+		}
+
+		if op.Scope != "" {
+			o.Security = append(o.Security, map[string][]string{
+				"bearerAuth": {op.Scope},
+			})
+		}
+	}
+
+	return o
+}
+
+// tag groups operations by the first segment of their path after the
+// optional /api/vN prefix.
+func tag(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(segments) > 2 && segments[0] == "api" {
+		segments = segments[2:]
+	}
+
+	return segments[0]
+}
@@ -0,0 +1,308 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schema describes a JSON value using the subset of the OpenAPI 3 schema
+// object needed to describe the API's request and response types.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+
+	// schemaCache keeps the inlined schemas of each type so that request
+	// bodies can be validated without generating the schema every time.
+	schemaCache = sync.Map{}
+)
+
+// SchemaFor generates the schema of v's type with all nested types inlined.
+//
+// Struct fields are named after their json tags. Fields tagged with
+// `validate:"required"` are listed as required properties.
+func SchemaFor(v interface{}) *Schema {
+	t := reflect.TypeOf(v)
+
+	if s, ok := schemaCache.Load(t); ok {
+		return s.(*Schema)
+	}
+
+	s := (&generator{}).schema(t)
+	schemaCache.Store(t, s)
+
+	return s
+}
+
+// generator creates schemas from Go types. If components is not nil, named
+// struct types are added to it and referenced with a $ref instead of being
+// inlined.
+type generator struct {
+	components map[string]*Schema
+}
+
+// schemaName returns the name used for t in the document's components.
+// Types declared outside of the models package are prefixed with their
+// package's name to avoid collisions, e.g. token.CreateForm is named
+// TokenCreateForm. The type parameters of generic types are appended to the
+// name, e.g. httputils.Page[models.Client] is named HttputilsPageClient.
+func schemaName(t reflect.Type) string {
+	var (
+		name   = t.Name()
+		pkg    = t.PkgPath()
+		prefix = ""
+	)
+
+	if pkg = pkg[strings.LastIndex(pkg, "/")+1:]; pkg != "models" && pkg != "" {
+		prefix = strings.ToUpper(pkg[:1]) + pkg[1:]
+	}
+
+	i := strings.Index(name, "[")
+	if i < 0 {
+		return prefix + name
+	}
+
+	var (
+		base   = prefix + name[:i]
+		params = strings.Split(name[i+1:len(name)-1], ",")
+	)
+
+	for _, p := range params {
+		base += p[strings.LastIndex(p, ".")+1:]
+	}
+
+	return base
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := g.schema(t.Elem())
+
+		if s.Ref != "" {
+			return s
+		}
+
+		s.Nullable = true
+		return s
+
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+
+	case reflect.String:
+		return &Schema{Type: "string"}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{
+			Type:  "array",
+			Items: g.schema(t.Elem()),
+		}
+
+	case reflect.Map:
+		return &Schema{
+			Type:                 "object",
+			AdditionalProperties: g.schema(t.Elem()),
+		}
+
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+
+		if g.components == nil || t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		name := schemaName(t)
+		if _, ok := g.components[name]; !ok {
+			// Reserve the name before generating the schema to support
+			// recursive types.
+			g.components[name] = &Schema{}
+			*g.components[name] = *g.structSchema(t)
+		}
+
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	// interface{} and any other kind accept any value.
+	return &Schema{}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		// The fields of untagged embedded structs are encoded as fields of
+		// the outer struct.
+		if field.Anonymous && name == field.Name && field.Type.Kind() == reflect.Struct {
+			embedded := g.structSchema(field.Type)
+
+			for name, property := range embedded.Properties {
+				s.Properties[name] = property
+			}
+
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+
+		s.Properties[name] = g.schema(field.Type)
+
+		if field.Tag.Get("validate") == "required" {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	sort.Strings(s.Required)
+	return s
+}
+
+// jsonName returns the name of the field as encoded by encoding/json. Returns
+// false if the field is not encoded.
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+
+	if tag == "-" {
+		return "", false
+	}
+
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name, true
+	}
+
+	return field.Name, true
+}
+
+// Validate checks that v, a value decoded by encoding/json into an
+// interface{}, matches the schema. Property names are matched exactly.
+func (s *Schema) Validate(v interface{}) error {
+	return s.validate("body", v)
+}
+
+func (s *Schema) validate(path string, v interface{}) error {
+	if v == nil {
+		if s.Type == "" || s.Nullable {
+			return nil
+		}
+
+		return fmt.Errorf("%s must not be null", path)
+	}
+
+	switch s.Type {
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", path)
+		}
+
+	case "integer":
+		n, ok := v.(float64)
+
+		if !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s must be an integer", path)
+		}
+
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s must be a number", path)
+		}
+
+	case "string":
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%s must be a string", path)
+		}
+
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, v.(string)); err != nil {
+				return fmt.Errorf("%s must be a RFC 3339 date", path)
+			}
+		}
+
+	case "array":
+		items, ok := v.([]interface{})
+
+		if !ok {
+			return fmt.Errorf("%s must be an array", path)
+		}
+
+		for i, item := range items {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+
+	case "object":
+		obj, ok := v.(map[string]interface{})
+
+		if !ok {
+			return fmt.Errorf("%s must be an object", path)
+		}
+
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s.%s is required", path, name)
+			}
+		}
+
+		for name, value := range obj {
+			property, ok := s.Properties[name]
+
+			if !ok {
+				property = s.AdditionalProperties
+			}
+
+			if property == nil {
+				continue
+			}
+
+			if err := property.validate(path+"."+name, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/openapi"
+)
+
+// newOpenAPIDocument describes the routes as an OpenAPI 3 document.
+func newOpenAPIDocument(cfg *config.Config, routes []Route) *openapi.Document {
+	ops := make([]openapi.Operation, 0, len(routes))
+
+	for _, r := range routes {
+		ops = append(ops, openapi.Operation{
+			Method:       r.Method(),
+			Path:         r.Pattern(),
+			Summary:      r.Summary(),
+			Request:      r.Request(),
+			Response:     r.Response(),
+			QueryParams:  r.QueryParams(),
+			RequiresAuth: r.RequiresAuth(),
+			Scope:        r.Scope(),
+		})
+	}
+
+	return openapi.NewDocument("Stormrage API", "1.0.0", cfg.SessionCookieName, ops)
+}
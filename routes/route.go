@@ -8,13 +8,25 @@ type Route interface {
 	Method() string
 	HandlerFunc() func(http.ResponseWriter, *http.Request) error
 	RequiresAuth() bool
+	RequiredRoles() []string
+	Scope() string
+	Summary() string
+	Request() interface{}
+	Response() interface{}
+	QueryParams() []string
 }
 
 type route struct {
-	pattern      string
-	method       string
-	handlerFunc  func(http.ResponseWriter, *http.Request) error
-	requiresAuth bool
+	pattern       string
+	method        string
+	handlerFunc   func(http.ResponseWriter, *http.Request) error
+	requiresAuth  bool
+	requiredRoles []string
+	scope         string
+	summary       string
+	request       interface{}
+	response      interface{}
+	queryParams   []string
 }
 
 func (r *route) Pattern() string {
@@ -32,3 +44,38 @@ func (r *route) HandlerFunc() func(http.ResponseWriter, *http.Request) error {
 func (r *route) RequiresAuth() bool {
 	return r.requiresAuth
 }
+
+// RequiredRoles returns the roles allowed to call the route. Routes without
+// roles can be called by any authenticated user.
+func (r *route) RequiredRoles() []string {
+	return r.requiredRoles
+}
+
+// Scope returns the scope that an API token needs to call the route. Routes
+// without a scope can only be called with a session cookie.
+func (r *route) Scope() string {
+	return r.scope
+}
+
+// Summary returns a short description of the route for the API docs.
+func (r *route) Summary() string {
+	return r.summary
+}
+
+// Request returns a value of the type of the route's JSON request body, or
+// nil if the route takes no body. Request bodies are validated against the
+// type's schema before calling the handler.
+func (r *route) Request() interface{} {
+	return r.request
+}
+
+// Response returns a value of the type of the route's JSON response, or nil
+// if the route doesn't respond with JSON.
+func (r *route) Response() interface{} {
+	return r.response
+}
+
+// QueryParams returns the names of the query parameters read by the route.
+func (r *route) QueryParams() []string {
+	return r.queryParams
+}
@@ -2,60 +2,1211 @@ package routes
 
 import (
 	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/handlers/alert"
+	"github.com/ab22/stormrage/handlers/arp"
 	"github.com/ab22/stormrage/handlers/auth"
+	"github.com/ab22/stormrage/handlers/backup"
+	"github.com/ab22/stormrage/handlers/billing"
+	"github.com/ab22/stormrage/handlers/dhcp"
+	"github.com/ab22/stormrage/handlers/docs"
+	"github.com/ab22/stormrage/handlers/document"
+	"github.com/ab22/stormrage/handlers/hotspot"
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/handlers/interfaces"
+	"github.com/ab22/stormrage/handlers/ipam"
 	"github.com/ab22/stormrage/handlers/mikrotik"
+	"github.com/ab22/stormrage/handlers/monitor"
+	"github.com/ab22/stormrage/handlers/ppp"
+	"github.com/ab22/stormrage/handlers/queues"
+	"github.com/ab22/stormrage/handlers/quota"
+	"github.com/ab22/stormrage/handlers/schedule"
+	"github.com/ab22/stormrage/handlers/suspension"
+	"github.com/ab22/stormrage/handlers/system"
+	"github.com/ab22/stormrage/handlers/ticket"
+	"github.com/ab22/stormrage/handlers/token"
+	"github.com/ab22/stormrage/handlers/traffic"
+	"github.com/ab22/stormrage/handlers/wireless"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
 	"github.com/jinzhu/gorm"
 
+	alertservices "github.com/ab22/stormrage/services/alert"
 	authservices "github.com/ab22/stormrage/services/auth"
+	backupservices "github.com/ab22/stormrage/services/backup"
+	billingservices "github.com/ab22/stormrage/services/billing"
+	documentservices "github.com/ab22/stormrage/services/document"
+	ipamservices "github.com/ab22/stormrage/services/ipam"
 	mikrotikservices "github.com/ab22/stormrage/services/mikrotik"
+	monitorservices "github.com/ab22/stormrage/services/monitor"
+	quotaservices "github.com/ab22/stormrage/services/quota"
+	scheduleservices "github.com/ab22/stormrage/services/schedule"
+	suspensionservices "github.com/ab22/stormrage/services/suspension"
+	ticketservices "github.com/ab22/stormrage/services/ticket"
+	tokenservices "github.com/ab22/stormrage/services/token"
+	trafficservices "github.com/ab22/stormrage/services/traffic"
 	userservices "github.com/ab22/stormrage/services/user"
 	"github.com/ab22/stormrage/services/ws"
 )
 
 // NewRoutes creates a new Router instance and initializes all API Routes.
-func NewRoutes(cfg *config.Config, db *gorm.DB) ([]Route, error) {
+func NewRoutes(cfg *config.Config, db *gorm.DB, log logger.Logger) ([]Route, error) {
 	var (
-		userService      = userservices.NewService(db)
-		authService      = authservices.NewService(db, userService)
-		mikrotikService  = mikrotikservices.NewService(cfg)
-		websocketService = ws.NewServer()
+		userService       = userservices.NewService(db)
+		authService       = authservices.NewService(db, userService)
+		mikrotikService   = mikrotikservices.NewService(cfg, log)
+		websocketService  = ws.NewServer(cfg, log, mikrotikService)
+		tokenService      = tokenservices.NewService(db)
+		suspensionService = suspensionservices.NewService(db, mikrotikService)
+		backupService     = backupservices.NewService(cfg, db, log, mikrotikService)
+		ipamService       = ipamservices.NewService(cfg, db, mikrotikService)
+		monitorService    = monitorservices.NewService(cfg, db, log, mikrotikService)
+		notifiers         = alertservices.NewNotifiers(cfg)
+		alertService      = alertservices.NewService(cfg, log, mikrotikService, monitorService, notifiers)
+		trafficService    = trafficservices.NewService(cfg, db, log, mikrotikService)
+		quotaService      = quotaservices.NewService(cfg, db, log, mikrotikService, trafficService, notifiers)
+		scheduleService   = scheduleservices.NewService(cfg, db, log, mikrotikService, quotaService)
+		billingService    = billingservices.NewService(cfg, db, log, mikrotikService, suspensionService)
+		documentService   = documentservices.NewService(cfg, db, log, billingService, mikrotikService, userService)
+		ticketService     = ticketservices.NewService(db, log, mikrotikService, monitorService, userService)
 
 		// staticHandler   = static.NewHandler(cfg)
-		authHandler     = auth.NewHandler(authService, cfg)
-		mikrotikHandler = mikrotik.NewHandler(mikrotikService)
+		authHandler       = auth.NewHandler(authService, cfg, log)
+		mikrotikHandler   = mikrotik.NewHandler(mikrotikService)
+		tokenHandler      = token.NewHandler(tokenService)
+		suspensionHandler = suspension.NewHandler(suspensionService)
+		pppHandler        = ppp.NewHandler(mikrotikService)
+		dhcpHandler       = dhcp.NewHandler(mikrotikService)
+		hotspotHandler    = hotspot.NewHandler(mikrotikService)
+		arpHandler        = arp.NewHandler(mikrotikService)
+		interfacesHandler = interfaces.NewHandler(mikrotikService)
+		queuesHandler     = queues.NewHandler(mikrotikService)
+		wirelessHandler   = wireless.NewHandler(mikrotikService)
+		systemHandler     = system.NewHandler(mikrotikService)
+		backupHandler     = backup.NewHandler(backupService)
+		ipamHandler       = ipam.NewHandler(ipamService)
+		monitorHandler    = monitor.NewHandler(monitorService, mikrotikService)
+		alertHandler      = alert.NewHandler(alertService)
+		trafficHandler    = traffic.NewHandler(trafficService, mikrotikService)
+		quotaHandler      = quota.NewHandler(quotaService, mikrotikService)
+		scheduleHandler   = schedule.NewHandler(scheduleService, mikrotikService)
+		billingHandler    = billing.NewHandler(billingService, mikrotikService)
+		documentHandler   = document.NewHandler(documentService)
+		ticketHandler     = ticket.NewHandler(ticketService)
 	)
 
 	// API routes
-	return []Route{
+	routes := []Route{
 		&route{
 			pattern:      "/ws/onConnect/",
 			method:       "GET",
 			handlerFunc:  websocketService.OnConnect,
 			requiresAuth: true,
+			summary:      "Upgrades the connection to a websocket",
 		},
 		&route{
 			pattern:      "/auth/checkAuthentication/",
 			method:       "POST",
 			handlerFunc:  authHandler.CheckAuth,
 			requiresAuth: true,
+			summary:      "Checks if the session is valid",
 		},
 		&route{
 			pattern:      "/auth/login/",
 			method:       "POST",
 			handlerFunc:  authHandler.Login,
 			requiresAuth: false,
+			summary:      "Logs in and sets the session cookie",
+			request:      auth.LoginForm{},
 		},
 		&route{
 			pattern:      "/auth/logout/",
 			method:       "POST",
 			handlerFunc:  authHandler.Logout,
 			requiresAuth: false,
+			summary:      "Deletes the session cookie",
 		},
 		&route{
 			pattern:      "/mikrotik/getClients/",
 			method:       "POST",
 			handlerFunc:  mikrotikHandler.GetClients,
 			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Lists all clients",
+			response:     []models.Client{},
 		},
-	}, nil
+		&route{
+			pattern:      "/mikrotik/suspendClients/",
+			method:       "POST",
+			handlerFunc:  suspensionHandler.SuspendClients,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Suspends a list of clients",
+			request:      suspension.BulkForm{},
+			response:     []suspensionservices.Result{},
+		},
+		&route{
+			pattern:      "/mikrotik/restoreClients/",
+			method:       "POST",
+			handlerFunc:  suspensionHandler.RestoreClients,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Restores a list of suspended clients",
+			request:      suspension.BulkForm{},
+			response:     []suspensionservices.Result{},
+		},
+		&route{
+			pattern:      "/tokens/create/",
+			method:       "POST",
+			handlerFunc:  tokenHandler.Create,
+			requiresAuth: true,
+			summary:      "Creates an API token",
+			request:      token.CreateForm{},
+			response:     token.CreateResponse{},
+		},
+		&route{
+			pattern:      "/tokens/list/",
+			method:       "POST",
+			handlerFunc:  tokenHandler.List,
+			requiresAuth: true,
+			summary:      "Lists the user's API tokens",
+			response:     []models.APIToken{},
+		},
+		&route{
+			pattern:      "/tokens/revoke/",
+			method:       "POST",
+			handlerFunc:  tokenHandler.Revoke,
+			requiresAuth: true,
+			summary:      "Revokes an API token",
+			request:      token.RevokeForm{},
+		},
+		&route{
+			pattern:      "/tokens/scopes/",
+			method:       "POST",
+			handlerFunc:  tokenHandler.Scopes,
+			requiresAuth: true,
+			summary:      "Lists the scopes that can be granted to API tokens",
+			response:     []string{},
+		},
+
+		// Versioned REST API routes.
+		&route{
+			pattern:      "/api/v1/clients",
+			method:       "GET",
+			handlerFunc:  mikrotikHandler.ListClients,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Lists clients",
+			response:     httputils.Page[models.Client]{},
+			queryParams:  []string{"q", "name", "target", "sort", "page", "perPage"},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}",
+			method:       "GET",
+			handlerFunc:  mikrotikHandler.FindClient,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Returns a client",
+			response:     models.Client{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/suspension",
+			method:       "PUT",
+			handlerFunc:  suspensionHandler.SuspendClient,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Suspends a client",
+			request:      suspension.ReasonForm{},
+			response:     models.Client{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/suspension",
+			method:       "DELETE",
+			handlerFunc:  suspensionHandler.RestoreClient,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Restores a suspended client",
+			response:     models.Client{},
+			queryParams:  []string{"reason"},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/suspensions",
+			method:       "GET",
+			handlerFunc:  suspensionHandler.History,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Lists the suspension history of a client",
+			response:     []models.SuspensionEvent{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/availability",
+			method:       "GET",
+			handlerFunc:  monitorHandler.Availability,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Returns the availability and outages of a client",
+			response:     models.ClientAvailability{},
+			queryParams:  []string{"days"},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/probes",
+			method:       "GET",
+			handlerFunc:  monitorHandler.Probes,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Lists the uptime probes of a client",
+			response:     []models.ClientProbe{},
+			queryParams:  []string{"days"},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/usage",
+			method:       "GET",
+			handlerFunc:  trafficHandler.ClientUsage,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Returns the traffic history of a client",
+			response:     models.ClientUsage{},
+			queryParams:  []string{"from", "to", "resolution"},
+		},
+		&route{
+			pattern:      "/api/v1/usage/top",
+			method:       "GET",
+			handlerFunc:  trafficHandler.Top,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Lists the clients that used the most traffic",
+			response:     []models.ClientUsage{},
+			queryParams:  []string{"from", "to", "limit"},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/quota",
+			method:       "GET",
+			handlerFunc:  quotaHandler.ClientStatus,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Returns the data cap consumption of a client",
+			response:     models.QuotaStatus{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/quota",
+			method:       "PUT",
+			handlerFunc:  quotaHandler.Assign,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Assigns a quota to a client",
+			request:      quota.AssignForm{},
+			response:     models.QuotaStatus{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/quota",
+			method:       "DELETE",
+			handlerFunc:  quotaHandler.Unassign,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Removes the quota of a client and restores its limits",
+		},
+		&route{
+			pattern:       "/api/v1/clients/{id}/quota/override",
+			method:        "PUT",
+			handlerFunc:   quotaHandler.Override,
+			requiresAuth:  true,
+			requiredRoles: []string{userservices.RoleAdmin},
+			scope:         tokenservices.ScopeClientsWrite,
+			summary:       "Lifts the data cap of a client until the end of its cycle",
+			response:      models.QuotaStatus{},
+		},
+		&route{
+			pattern:       "/api/v1/clients/{id}/quota/override",
+			method:        "DELETE",
+			handlerFunc:   quotaHandler.RemoveOverride,
+			requiresAuth:  true,
+			requiredRoles: []string{userservices.RoleAdmin},
+			scope:         tokenservices.ScopeClientsWrite,
+			summary:       "Removes the data cap override of a client",
+			response:      models.QuotaStatus{},
+		},
+		&route{
+			pattern:      "/api/v1/quotas",
+			method:       "GET",
+			handlerFunc:  quotaHandler.ListQuotas,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Lists the quotas",
+			response:     []models.Quota{},
+		},
+		&route{
+			pattern:      "/api/v1/quotas",
+			method:       "POST",
+			handlerFunc:  quotaHandler.CreateQuota,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Creates a quota",
+			request:      quota.QuotaForm{},
+			response:     models.Quota{},
+		},
+		&route{
+			pattern:      "/api/v1/quotas/clients",
+			method:       "GET",
+			handlerFunc:  quotaHandler.ListStatuses,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Lists the data cap consumption of every client with a quota",
+			response:     []models.QuotaStatus{},
+		},
+		&route{
+			pattern:      "/api/v1/quotas/{id:[0-9]+}",
+			method:       "PUT",
+			handlerFunc:  quotaHandler.UpdateQuota,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Updates a quota",
+			request:      quota.QuotaForm{},
+			response:     models.Quota{},
+		},
+		&route{
+			pattern:      "/api/v1/quotas/{id:[0-9]+}",
+			method:       "DELETE",
+			handlerFunc:  quotaHandler.RemoveQuota,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Removes a quota that is not assigned to any client",
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/schedule",
+			method:       "GET",
+			handlerFunc:  scheduleHandler.ClientStatus,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Returns the scheduled limits of a client and their next change",
+			response:     models.ClientScheduleStatus{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/schedule",
+			method:       "PUT",
+			handlerFunc:  scheduleHandler.Assign,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Assigns a bandwidth schedule to a client",
+			request:      schedule.AssignForm{},
+			response:     models.ClientScheduleStatus{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/schedule",
+			method:       "DELETE",
+			handlerFunc:  scheduleHandler.Unassign,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Removes the bandwidth schedule of a client and restores its base limits",
+		},
+		&route{
+			pattern:      "/api/v1/schedules",
+			method:       "GET",
+			handlerFunc:  scheduleHandler.ListSchedules,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Lists the bandwidth schedules",
+			response:     []models.BandwidthSchedule{},
+		},
+		&route{
+			pattern:      "/api/v1/schedules",
+			method:       "POST",
+			handlerFunc:  scheduleHandler.CreateSchedule,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Creates a bandwidth schedule",
+			request:      schedule.ScheduleForm{},
+			response:     models.BandwidthSchedule{},
+		},
+		&route{
+			pattern:      "/api/v1/schedules/clients",
+			method:       "GET",
+			handlerFunc:  scheduleHandler.ListStatuses,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Lists the scheduled limits and next change of every client with a schedule",
+			response:     []models.ClientScheduleStatus{},
+		},
+		&route{
+			pattern:      "/api/v1/schedules/{id:[0-9]+}",
+			method:       "GET",
+			handlerFunc:  scheduleHandler.FindSchedule,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsRead,
+			summary:      "Returns a bandwidth schedule",
+			response:     models.BandwidthSchedule{},
+		},
+		&route{
+			pattern:      "/api/v1/schedules/{id:[0-9]+}",
+			method:       "PUT",
+			handlerFunc:  scheduleHandler.UpdateSchedule,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Updates a bandwidth schedule and replaces its rules",
+			request:      schedule.ScheduleForm{},
+			response:     models.BandwidthSchedule{},
+		},
+		&route{
+			pattern:      "/api/v1/schedules/{id:[0-9]+}",
+			method:       "DELETE",
+			handlerFunc:  scheduleHandler.RemoveSchedule,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeClientsWrite,
+			summary:      "Removes a bandwidth schedule that is not assigned to any client",
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/billing",
+			method:       "GET",
+			handlerFunc:  billingHandler.ClientAccount,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingRead,
+			summary:      "Returns the billing account and balance of a client",
+			response:     models.AccountStatus{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/billing",
+			method:       "PUT",
+			handlerFunc:  billingHandler.Assign,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingWrite,
+			summary:      "Assigns a plan to a client",
+			request:      billing.AccountForm{},
+			response:     models.AccountStatus{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/billing",
+			method:       "DELETE",
+			handlerFunc:  billingHandler.Unassign,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingWrite,
+			summary:      "Removes the billing account of a client",
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/invoices",
+			method:       "GET",
+			handlerFunc:  billingHandler.ClientInvoices,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingRead,
+			summary:      "Lists the invoices of a client",
+			response:     []models.Invoice{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/invoices",
+			method:       "POST",
+			handlerFunc:  billingHandler.CreateInvoice,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingWrite,
+			summary:      "Adds a charge to the account of a client",
+			request:      billing.InvoiceForm{},
+			response:     models.Invoice{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/payments",
+			method:       "GET",
+			handlerFunc:  billingHandler.ClientPayments,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingRead,
+			summary:      "Lists the payments of a client",
+			response:     []models.Payment{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/payments",
+			method:       "POST",
+			handlerFunc:  billingHandler.RegisterPayment,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingWrite,
+			summary:      "Registers a payment of a client",
+			request:      billing.PaymentForm{},
+			response:     models.Payment{},
+		},
+		&route{
+			pattern:      "/api/v1/plans",
+			method:       "GET",
+			handlerFunc:  billingHandler.ListPlans,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingRead,
+			summary:      "Lists the plans",
+			response:     []models.Plan{},
+		},
+		&route{
+			pattern:      "/api/v1/plans",
+			method:       "POST",
+			handlerFunc:  billingHandler.CreatePlan,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingWrite,
+			summary:      "Creates a plan",
+			request:      billing.PlanForm{},
+			response:     models.Plan{},
+		},
+		&route{
+			pattern:      "/api/v1/plans/{id:[0-9]+}",
+			method:       "PUT",
+			handlerFunc:  billingHandler.UpdatePlan,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingWrite,
+			summary:      "Updates a plan",
+			request:      billing.PlanForm{},
+			response:     models.Plan{},
+		},
+		&route{
+			pattern:      "/api/v1/plans/{id:[0-9]+}",
+			method:       "DELETE",
+			handlerFunc:  billingHandler.RemovePlan,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingWrite,
+			summary:      "Removes a plan that is not assigned to any client",
+		},
+		&route{
+			pattern:      "/api/v1/billing/accounts",
+			method:       "GET",
+			handlerFunc:  billingHandler.ListAccounts,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingRead,
+			summary:      "Lists the billing accounts and their balance",
+			response:     []models.AccountStatus{},
+			queryParams:  []string{"overdue"},
+		},
+		&route{
+			pattern:       "/api/v1/billing/run",
+			method:        "POST",
+			handlerFunc:   billingHandler.Run,
+			requiresAuth:  true,
+			requiredRoles: []string{userservices.RoleAdmin},
+			scope:         tokenservices.ScopeBillingWrite,
+			summary:       "Runs the billing job right away",
+		},
+		&route{
+			pattern:      "/api/v1/invoices/{id:[0-9]+}",
+			method:       "GET",
+			handlerFunc:  billingHandler.FindInvoice,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingRead,
+			summary:      "Returns an invoice",
+			response:     models.Invoice{},
+		},
+		&route{
+			pattern:       "/api/v1/invoices/{id:[0-9]+}",
+			method:        "DELETE",
+			handlerFunc:   billingHandler.VoidInvoice,
+			requiresAuth:  true,
+			requiredRoles: []string{userservices.RoleAdmin},
+			scope:         tokenservices.ScopeBillingWrite,
+			summary:       "Voids an invoice",
+			response:      models.Invoice{},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/documents",
+			method:       "GET",
+			handlerFunc:  documentHandler.ListClientDocuments,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingRead,
+			summary:      "Lists the stored invoices and receipts of a client",
+			response:     []models.Document{},
+		},
+		&route{
+			pattern:      "/api/v1/invoices/{id:[0-9]+}/pdf",
+			method:       "GET",
+			handlerFunc:  documentHandler.Invoice,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingRead,
+			summary:      "Returns the PDF of an invoice",
+		},
+		&route{
+			pattern:      "/api/v1/invoices/{id:[0-9]+}/pdf",
+			method:       "POST",
+			handlerFunc:  documentHandler.StoreInvoice,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingWrite,
+			summary:      "Returns the PDF of an invoice and stores it",
+		},
+		&route{
+			pattern:      "/api/v1/payments/{id:[0-9]+}/receipt",
+			method:       "GET",
+			handlerFunc:  documentHandler.Receipt,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingRead,
+			summary:      "Returns the PDF receipt of a payment",
+		},
+		&route{
+			pattern:      "/api/v1/payments/{id:[0-9]+}/receipt",
+			method:       "POST",
+			handlerFunc:  documentHandler.StoreReceipt,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingWrite,
+			summary:      "Returns the PDF receipt of a payment and stores it",
+		},
+		&route{
+			pattern:      "/api/v1/documents/{id:[0-9]+}",
+			method:       "GET",
+			handlerFunc:  documentHandler.Download,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeBillingRead,
+			summary:      "Returns the PDF of a stored invoice or receipt",
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/tickets",
+			method:       "GET",
+			handlerFunc:  ticketHandler.ClientTickets,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeTicketsRead,
+			summary:      "Lists the support tickets of a client",
+			response:     []models.Ticket{},
+			queryParams:  []string{"status"},
+		},
+		&route{
+			pattern:      "/api/v1/clients/{id}/tickets",
+			method:       "POST",
+			handlerFunc:  ticketHandler.Create,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeTicketsWrite,
+			summary:      "Opens a support ticket for a client and attaches its diagnostics",
+			request:      ticket.TicketForm{},
+			response:     models.Ticket{},
+		},
+		&route{
+			pattern:      "/api/v1/tickets",
+			method:       "GET",
+			handlerFunc:  ticketHandler.List,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeTicketsRead,
+			summary:      "Lists the support tickets",
+			response:     []models.Ticket{},
+			queryParams:  []string{"status", "clientId", "assigneeId"},
+		},
+		&route{
+			pattern:      "/api/v1/tickets/{id:[0-9]+}",
+			method:       "GET",
+			handlerFunc:  ticketHandler.Find,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeTicketsRead,
+			summary:      "Returns a support ticket with its diagnostics and comments",
+			response:     models.Ticket{},
+		},
+		&route{
+			pattern:      "/api/v1/tickets/{id:[0-9]+}/status",
+			method:       "PUT",
+			handlerFunc:  ticketHandler.SetStatus,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeTicketsWrite,
+			summary:      "Changes the status of a support ticket",
+			request:      ticket.StatusForm{},
+			response:     models.Ticket{},
+		},
+		&route{
+			pattern:      "/api/v1/tickets/{id:[0-9]+}/assignee",
+			method:       "PUT",
+			handlerFunc:  ticketHandler.Assign,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeTicketsWrite,
+			summary:      "Assigns a support ticket to a user",
+			request:      ticket.AssigneeForm{},
+			response:     models.Ticket{},
+		},
+		&route{
+			pattern:      "/api/v1/tickets/{id:[0-9]+}/comments",
+			method:       "POST",
+			handlerFunc:  ticketHandler.AddComment,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeTicketsWrite,
+			summary:      "Adds a comment to a support ticket",
+			request:      ticket.CommentForm{},
+			response:     models.TicketComment{},
+		},
+		&route{
+			pattern:      "/api/v1/ppp/secrets",
+			method:       "GET",
+			handlerFunc:  pppHandler.ListSecrets,
+			requiresAuth: true,
+			scope:        tokenservices.ScopePPPRead,
+			summary:      "Lists PPP secrets",
+			response:     []models.PPPSecret{},
+		},
+		&route{
+			pattern:      "/api/v1/ppp/secrets",
+			method:       "POST",
+			handlerFunc:  pppHandler.CreateSecret,
+			requiresAuth: true,
+			scope:        tokenservices.ScopePPPWrite,
+			summary:      "Creates a PPP secret",
+			request:      models.PPPSecret{},
+			response:     models.PPPSecret{},
+		},
+		&route{
+			pattern:      "/api/v1/ppp/secrets/{id}",
+			method:       "GET",
+			handlerFunc:  pppHandler.FindSecret,
+			requiresAuth: true,
+			scope:        tokenservices.ScopePPPRead,
+			summary:      "Returns a PPP secret",
+			response:     models.PPPSecret{},
+		},
+		&route{
+			pattern:      "/api/v1/ppp/secrets/{id}",
+			method:       "PUT",
+			handlerFunc:  pppHandler.UpdateSecret,
+			requiresAuth: true,
+			scope:        tokenservices.ScopePPPWrite,
+			summary:      "Updates a PPP secret",
+			request:      models.PPPSecret{},
+			response:     models.PPPSecret{},
+		},
+		&route{
+			pattern:      "/api/v1/ppp/secrets/{id}",
+			method:       "DELETE",
+			handlerFunc:  pppHandler.RemoveSecret,
+			requiresAuth: true,
+			scope:        tokenservices.ScopePPPWrite,
+			summary:      "Removes a PPP secret",
+		},
+		&route{
+			pattern:      "/api/v1/ppp/secrets/{id}/disable",
+			method:       "POST",
+			handlerFunc:  pppHandler.DisableSecret,
+			requiresAuth: true,
+			scope:        tokenservices.ScopePPPWrite,
+			summary:      "Disables a PPP secret",
+			response:     models.PPPSecret{},
+		},
+		&route{
+			pattern:      "/api/v1/ppp/secrets/{id}/enable",
+			method:       "POST",
+			handlerFunc:  pppHandler.EnableSecret,
+			requiresAuth: true,
+			scope:        tokenservices.ScopePPPWrite,
+			summary:      "Enables a PPP secret",
+			response:     models.PPPSecret{},
+		},
+		&route{
+			pattern:      "/api/v1/ppp/active",
+			method:       "GET",
+			handlerFunc:  pppHandler.ListSessions,
+			requiresAuth: true,
+			scope:        tokenservices.ScopePPPRead,
+			summary:      "Lists active PPP sessions",
+			response:     []models.PPPSession{},
+		},
+		&route{
+			pattern:      "/api/v1/ppp/active/{id}",
+			method:       "DELETE",
+			handlerFunc:  pppHandler.KickSession,
+			requiresAuth: true,
+			scope:        tokenservices.ScopePPPWrite,
+			summary:      "Disconnects an active PPP session",
+		},
+		&route{
+			pattern:      "/api/v1/hotspot/users",
+			method:       "GET",
+			handlerFunc:  hotspotHandler.ListUsers,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeHotspotRead,
+			summary:      "Lists hotspot users",
+			response:     []models.HotspotUser{},
+		},
+		&route{
+			pattern:      "/api/v1/hotspot/users",
+			method:       "POST",
+			handlerFunc:  hotspotHandler.CreateUser,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeHotspotWrite,
+			summary:      "Creates a hotspot user",
+			request:      models.HotspotUser{},
+			response:     models.HotspotUser{},
+		},
+		&route{
+			pattern:      "/api/v1/hotspot/users/{id}",
+			method:       "GET",
+			handlerFunc:  hotspotHandler.FindUser,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeHotspotRead,
+			summary:      "Returns a hotspot user",
+			response:     models.HotspotUser{},
+		},
+		&route{
+			pattern:      "/api/v1/hotspot/users/{id}",
+			method:       "PUT",
+			handlerFunc:  hotspotHandler.UpdateUser,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeHotspotWrite,
+			summary:      "Updates a hotspot user",
+			request:      models.HotspotUser{},
+			response:     models.HotspotUser{},
+		},
+		&route{
+			pattern:      "/api/v1/hotspot/users/{id}",
+			method:       "DELETE",
+			handlerFunc:  hotspotHandler.RemoveUser,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeHotspotWrite,
+			summary:      "Removes a hotspot user",
+		},
+		&route{
+			pattern:      "/api/v1/hotspot/active",
+			method:       "GET",
+			handlerFunc:  hotspotHandler.ListSessions,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeHotspotRead,
+			summary:      "Lists active hotspot sessions",
+			response:     []models.HotspotSession{},
+		},
+		&route{
+			pattern:      "/api/v1/hotspot/active/{id}",
+			method:       "DELETE",
+			handlerFunc:  hotspotHandler.LogoutSession,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeHotspotWrite,
+			summary:      "Logs out an active hotspot session",
+		},
+		&route{
+			pattern:      "/api/v1/hotspot/vouchers",
+			method:       "POST",
+			handlerFunc:  hotspotHandler.GenerateVouchers,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeHotspotWrite,
+			summary:      "Creates a batch of hotspot users with random credentials",
+			request:      hotspot.VoucherForm{},
+			response:     []models.HotspotUser{},
+			queryParams:  []string{"format"},
+		},
+		&route{
+			pattern:      "/api/v1/dhcp/leases",
+			method:       "GET",
+			handlerFunc:  dhcpHandler.ListLeases,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Lists DHCP leases",
+			response:     []models.DHCPLease{},
+		},
+		&route{
+			pattern:      "/api/v1/dhcp/leases/{id}/make-static",
+			method:       "POST",
+			handlerFunc:  dhcpHandler.MakeStatic,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Converts a dynamic DHCP lease into a static lease",
+			response:     models.DHCPLease{},
+		},
+		&route{
+			pattern:      "/api/v1/dhcp/leases/{id}/client",
+			method:       "PUT",
+			handlerFunc:  dhcpHandler.BindToClient,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Binds a DHCP lease to a client's target address",
+			request:      dhcp.BindForm{},
+			response:     models.DHCPLease{},
+		},
+		&route{
+			pattern:      "/api/v1/arp",
+			method:       "GET",
+			handlerFunc:  arpHandler.ListEntries,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Lists the ARP table",
+			response:     []models.ARPEntry{},
+		},
+		&route{
+			pattern:      "/api/v1/arp",
+			method:       "POST",
+			handlerFunc:  arpHandler.AddStaticEntry,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Adds a static ARP entry",
+			request:      models.ARPEntry{},
+			response:     models.ARPEntry{},
+		},
+		&route{
+			pattern:      "/api/v1/lookup",
+			method:       "GET",
+			handlerFunc:  arpHandler.Lookup,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Finds the ARP entry, DHCP lease, client and interface of an IP or MAC address",
+			response:     models.Lookup{},
+			queryParams:  []string{"q"},
+		},
+		&route{
+			pattern:      "/api/v1/ipam/subnets",
+			method:       "GET",
+			handlerFunc:  ipamHandler.ListSubnets,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Lists the router's subnets",
+			response:     []models.Subnet{},
+		},
+		&route{
+			pattern:      "/api/v1/ipam/subnets",
+			method:       "POST",
+			handlerFunc:  ipamHandler.CreateSubnet,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Adds a subnet to the router",
+			request:      ipam.SubnetForm{},
+			response:     models.Subnet{},
+		},
+		&route{
+			pattern:      "/api/v1/ipam/subnets/{id:[0-9]+}",
+			method:       "DELETE",
+			handlerFunc:  ipamHandler.RemoveSubnet,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Removes a subnet and its reservations",
+		},
+		&route{
+			pattern:      "/api/v1/ipam/subnets/{id:[0-9]+}/usage",
+			method:       "GET",
+			handlerFunc:  ipamHandler.Usage,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Lists the addresses of a subnet that are in use",
+			response:     models.SubnetUsage{},
+		},
+		&route{
+			pattern:      "/api/v1/ipam/subnets/{id:[0-9]+}/next-free",
+			method:       "GET",
+			handlerFunc:  ipamHandler.NextFree,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Returns the next free address of a subnet",
+			response:     ipam.NextFreeResponse{},
+		},
+		&route{
+			pattern:      "/api/v1/ipam/subnets/{id:[0-9]+}/reservations",
+			method:       "GET",
+			handlerFunc:  ipamHandler.ListReservations,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Lists the reservations of a subnet",
+			response:     []models.IPReservation{},
+		},
+		&route{
+			pattern:      "/api/v1/ipam/subnets/{id:[0-9]+}/reservations",
+			method:       "POST",
+			handlerFunc:  ipamHandler.Reserve,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Reserves an address of a subnet, the next free one by default",
+			request:      ipam.ReserveForm{},
+			response:     models.IPReservation{},
+		},
+		&route{
+			pattern:      "/api/v1/ipam/reservations/{id:[0-9]+}",
+			method:       "DELETE",
+			handlerFunc:  ipamHandler.Release,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Releases a reserved address",
+		},
+		&route{
+			pattern:      "/api/v1/ipam/conflicts",
+			method:       "GET",
+			handlerFunc:  ipamHandler.Conflicts,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Lists the addresses targeted by more than one client",
+			response:     []models.AddressConflict{},
+		},
+		&route{
+			pattern:      "/api/v1/interfaces",
+			method:       "GET",
+			handlerFunc:  interfacesHandler.ListInterfaces,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Lists the router's interfaces",
+			response:     []models.Interface{},
+		},
+		&route{
+			pattern:      "/api/v1/interfaces/{id}/enable",
+			method:       "POST",
+			handlerFunc:  interfacesHandler.EnableInterface,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Enables an interface",
+		},
+		&route{
+			pattern:      "/api/v1/interfaces/{id}/disable",
+			method:       "POST",
+			handlerFunc:  interfacesHandler.DisableInterface,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Disables an interface",
+		},
+		&route{
+			pattern:      "/api/v1/wireless/registrations",
+			method:       "GET",
+			handlerFunc:  wirelessHandler.ListRegistrations,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Lists the connected wireless stations and their signal quality",
+			response:     []models.WirelessRegistration{},
+		},
+		&route{
+			pattern:      "/api/v1/queues/types",
+			method:       "GET",
+			handlerFunc:  queuesHandler.ListTypes,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Lists queue types",
+			response:     []models.QueueType{},
+		},
+		&route{
+			pattern:      "/api/v1/queues/types",
+			method:       "POST",
+			handlerFunc:  queuesHandler.CreateType,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Creates a queue type, PCQ by default",
+			request:      models.QueueType{},
+			response:     models.QueueType{},
+		},
+		&route{
+			pattern:      "/api/v1/queues/types/{id}",
+			method:       "PUT",
+			handlerFunc:  queuesHandler.UpdateType,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Updates a queue type",
+			request:      models.QueueType{},
+			response:     models.QueueType{},
+		},
+		&route{
+			pattern:      "/api/v1/queues/types/{id}",
+			method:       "DELETE",
+			handlerFunc:  queuesHandler.RemoveType,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Removes a queue type",
+		},
+		&route{
+			pattern:      "/api/v1/queues/tree",
+			method:       "GET",
+			handlerFunc:  queuesHandler.Tree,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Returns the queue tree with each entry's children nested",
+			response:     []*models.QueueTree{},
+		},
+		&route{
+			pattern:      "/api/v1/queues/tree",
+			method:       "POST",
+			handlerFunc:  queuesHandler.CreateTreeEntry,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Creates a queue tree entry",
+			request:      queues.TreeEntryForm{},
+			response:     models.QueueTree{},
+		},
+		&route{
+			pattern:      "/api/v1/queues/tree/{id}",
+			method:       "PUT",
+			handlerFunc:  queuesHandler.UpdateTreeEntry,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Updates a queue tree entry",
+			request:      queues.TreeEntryForm{},
+			response:     models.QueueTree{},
+		},
+		&route{
+			pattern:      "/api/v1/queues/tree/{id}",
+			method:       "DELETE",
+			handlerFunc:  queuesHandler.RemoveTreeEntry,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkWrite,
+			summary:      "Removes a queue tree entry",
+		},
+		&route{
+			pattern:      "/api/v1/router/status",
+			method:       "GET",
+			handlerFunc:  systemHandler.RouterStatus,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Returns the router's resources, identity, health and entry counts",
+			response:     models.RouterStatus{},
+		},
+		&route{
+			pattern:      "/api/v1/router/logs",
+			method:       "GET",
+			handlerFunc:  systemHandler.Logs,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Lists the router's log entries",
+			response:     []models.LogEntry{},
+			queryParams:  []string{"topic", "text", "from", "to"},
+		},
+		&route{
+			pattern:      "/api/v1/alerts",
+			method:       "GET",
+			handlerFunc:  alertHandler.ListActive,
+			requiresAuth: true,
+			scope:        tokenservices.ScopeNetworkRead,
+			summary:      "Lists the alerts that are firing",
+			response:     []models.Alert{},
+		},
+		&route{
+			pattern:       "/api/v1/alerts/test",
+			method:        "POST",
+			handlerFunc:   alertHandler.SendTest,
+			requiresAuth:  true,
+			requiredRoles: []string{userservices.RoleAdmin},
+			scope:         tokenservices.ScopeNetworkWrite,
+			summary:       "Sends a test notification through every notifier",
+		},
+		&route{
+			pattern:       "/api/v1/router/backups",
+			method:        "GET",
+			handlerFunc:   backupHandler.List,
+			requiresAuth:  true,
+			requiredRoles: []string{userservices.RoleAdmin},
+			scope:         tokenservices.ScopeBackups,
+			summary:       "Lists the router's configuration backups",
+			response:      []models.RouterBackup{},
+		},
+		&route{
+			pattern:       "/api/v1/router/backups",
+			method:        "POST",
+			handlerFunc:   backupHandler.Create,
+			requiresAuth:  true,
+			requiredRoles: []string{userservices.RoleAdmin},
+			scope:         tokenservices.ScopeBackups,
+			summary:       "Exports the router's configuration and stores a backup",
+			response:      models.RouterBackup{},
+		},
+		&route{
+			pattern:       "/api/v1/router/backups/diff",
+			method:        "GET",
+			handlerFunc:   backupHandler.Diff,
+			requiresAuth:  true,
+			requiredRoles: []string{userservices.RoleAdmin},
+			scope:         tokenservices.ScopeBackups,
+			summary:       "Returns the unified diff between two backups as plain text",
+			queryParams:   []string{"from", "to"},
+		},
+		&route{
+			pattern:       "/api/v1/router/backups/{id:[0-9]+}",
+			method:        "GET",
+			handlerFunc:   backupHandler.Get,
+			requiresAuth:  true,
+			requiredRoles: []string{userservices.RoleAdmin},
+			scope:         tokenservices.ScopeBackups,
+			summary:       "Gets a backup and its content",
+			response:      models.RouterBackup{},
+		},
+		&route{
+			pattern:       "/api/v1/router/backups/{id:[0-9]+}/download",
+			method:        "GET",
+			handlerFunc:   backupHandler.Download,
+			requiresAuth:  true,
+			requiredRoles: []string{userservices.RoleAdmin},
+			scope:         tokenservices.ScopeBackups,
+			summary:       "Downloads a backup as an .rsc script",
+		},
+	}
+
+	docsHandler := docs.NewHandler(newOpenAPIDocument(cfg, routes))
+
+	routes = append(routes, &route{
+		pattern:      "/api/v1/openapi.json",
+		method:       "GET",
+		handlerFunc:  docsHandler.OpenAPI,
+		requiresAuth: false,
+	})
+
+	return routes, nil
 }
@@ -4,13 +4,17 @@ import (
 	"context"
 	"encoding/gob"
 	"fmt"
-	"log"
 	"net/http"
+	"os"
 
 	"github.com/ab22/stormrage/config"
 	"github.com/ab22/stormrage/handlers"
 	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/openapi"
 	"github.com/ab22/stormrage/routes"
+	"github.com/ab22/stormrage/services/token"
+	"github.com/ab22/stormrage/services/user"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/sessions"
 	"github.com/jinzhu/gorm"
@@ -19,10 +23,13 @@ import (
 )
 
 type Server struct {
-	cfg         *config.Config
-	router      *mux.Router
-	cookieStore *sessions.CookieStore
-	db          *gorm.DB
+	cfg          *config.Config
+	router       *mux.Router
+	cookieStore  *sessions.CookieStore
+	db           *gorm.DB
+	log          logger.Logger
+	tokenService token.Service
+	userService  user.Service
 }
 
 func NewServer() (*Server, error) {
@@ -31,21 +38,27 @@ func NewServer() (*Server, error) {
 		server = &Server{}
 	)
 
-	log.Println("Configuring server...")
 	server.cfg, err = config.New()
 	if err != nil {
 		return nil, err
 	}
 
-	server.cfg.Print()
+	server.log, err = logger.New(os.Stderr, server.cfg.Log.Level, server.cfg.Log.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	server.cfg.Print(server.log)
 
-	log.Println("Configuring database...")
-	err = server.createDatabaseConnection()
+	server.log.Info("configuring database")
 	if err = server.createDatabaseConnection(); err != nil {
 		return nil, err
 	}
 
-	log.Println("Configuring router...")
+	server.tokenService = token.NewService(server.db)
+	server.userService = user.NewService(server.db)
+
+	server.log.Info("configuring router")
 	if err = server.configureRouter(); err != nil {
 		return nil, err
 	}
@@ -56,6 +69,8 @@ func NewServer() (*Server, error) {
 }
 
 func (s *Server) ListenAndServe() error {
+	s.log.Info("listening", "port", s.cfg.Port)
+
 	return http.ListenAndServe(
 		fmt.Sprintf(":%d", s.cfg.Port),
 		s.router,
@@ -96,7 +111,7 @@ func (s *Server) createDatabaseConnection() error {
 
 func (s *Server) configureRouter() error {
 	s.router = mux.NewRouter().StrictSlash(true)
-	r, err := routes.NewRoutes(s.cfg, s.db)
+	r, err := routes.NewRoutes(s.cfg, s.db, s.log)
 
 	if err != nil {
 		return err
@@ -119,23 +134,24 @@ func (s *Server) bindRoutes(r []routes.Route) {
 	}
 }
 
-// makeHTTPHandler creates a http.HandlerFunc from a custom http function and logs the error if
-// exists: func(http.ResponseWriter, *http.Request) error.
+// makeHTTPHandler creates a http.HandlerFunc from a custom http function:
+// func(http.ResponseWriter, *http.Request) error. Returned errors are logged
+// by the LogRequest middleware.
 func (s *Server) makeHTTPHandler(route routes.Route) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		handlerFunc := s.handleWithMiddlewares(route)
-		err := handlerFunc(w, r)
-
-		if err != nil {
-			log.Printf("Handler [%s][%s] returned error: %s", r.Method, r.URL.Path, err)
-		}
+		handlerFunc(w, r)
 	}
 }
 
 // handleWithMiddlewares applies all middlewares to the specified route. Some
 // middleware functions are applied depending on the route's properties, such
 // as ValidateAuth and Authorize middlewares. These last 2 functions require
-// that the route RequiresAuth() and that RequiredRoles() > 0.
+// that the route RequiresAuth() and that RequiredRoles() > 0. API tokens are
+// checked against the route's Scope(). Routes that declare a Request() type
+// get their body validated against the type's schema. CheckOrigin is
+// applied to all routes before authenticating the request. LogRequest is
+// always the outermost middleware so that it logs the final response status.
 func (s *Server) handleWithMiddlewares(route routes.Route) httputils.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
 		var (
@@ -145,14 +161,29 @@ func (s *Server) handleWithMiddlewares(route routes.Route) httputils.HandlerFunc
 
 		ctx = context.WithValue(ctx, "cookieStore", s.cookieStore)
 		ctx = context.WithValue(ctx, "config", s.cfg)
+		ctx = context.WithValue(ctx, "tokenService", s.tokenService)
+		ctx = context.WithValue(ctx, "userService", s.userService)
+		ctx = logger.NewContext(ctx, s.log)
 		r = r.WithContext(ctx)
 
 		handler = handlers.HandleHTTPError(handler)
 
+		if req := route.Request(); req != nil {
+			handler = handlers.ValidateBody(openapi.SchemaFor(req))(handler)
+		}
+
+		if route.RequiresAuth() && len(route.RequiredRoles()) > 0 {
+			handler = handlers.Authorize(route.RequiredRoles())(handler)
+		}
+
 		if route.RequiresAuth() {
-			handler = handlers.ValidateAuth(handler)
+			handler = handlers.ValidateAuth(route.Scope())(handler)
 		}
 
+		handler = handlers.CheckOrigin(handler)
+
+		handler = handlers.LogRequest(handler)
+
 		return handler(w, r)
 	}
 }
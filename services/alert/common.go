@@ -0,0 +1,126 @@
+package alert
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+)
+
+// run evaluates the rules every Alert.IntervalSeconds.
+func (s *service) run() {
+	interval := time.Duration(s.cfg.Alert.IntervalSeconds) * time.Second
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		s.evaluate(ctx)
+		cancel()
+	}
+}
+
+// evaluate runs every rule and compares the alerts they return with the
+// ones that are already firing. New alerts are notified as firing and the
+// alerts no longer returned are notified as resolved, so each condition is
+// notified once while it lasts. If a rule fails, its alerts are left as they
+// are.
+func (s *service) evaluate(ctx context.Context) {
+	var (
+		now           = time.Now()
+		firing        = map[string]models.Alert{}
+		failed        = map[string]bool{}
+		notifications []models.Alert
+	)
+
+	for _, r := range s.rules {
+		alerts, err := r.evaluate(ctx)
+		if err != nil {
+			s.log.Warn("alert: could not evaluate rule", "rule", r.name, "error", err)
+			failed[r.name] = true
+			continue
+		}
+
+		for _, alert := range alerts {
+			alert.Rule = r.name
+			firing[alert.Key] = alert
+		}
+	}
+
+	s.mutex.Lock()
+
+	for key, alert := range firing {
+		if _, ok := s.active[key]; ok {
+			continue
+		}
+
+		alert.Status = StatusFiring
+		if alert.StartedAt.IsZero() {
+			alert.StartedAt = now
+		}
+
+		s.active[key] = alert
+		notifications = append(notifications, alert)
+	}
+
+	for key, alert := range s.active {
+		if _, ok := firing[key]; ok || failed[alert.Rule] {
+			continue
+		}
+
+		alert.Status = StatusResolved
+		alert.ResolvedAt = &now
+
+		delete(s.active, key)
+		notifications = append(notifications, alert)
+	}
+
+	s.mutex.Unlock()
+
+	for _, alert := range notifications {
+		s.log.Warn("alert: "+alert.Status, "key", alert.Key, "subject", alert.Subject)
+		Notify(ctx, s.log, s.notifiers, alert)
+	}
+}
+
+// Active returns the alerts that are firing, oldest first.
+func (s *service) Active() []models.Alert {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	alerts := make([]models.Alert, 0, len(s.active))
+
+	for _, alert := range s.active {
+		alerts = append(alerts, alert)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].StartedAt.Equal(alerts[j].StartedAt) {
+			return alerts[i].Key < alerts[j].Key
+		}
+
+		return alerts[i].StartedAt.Before(alerts[j].StartedAt)
+	})
+
+	return alerts
+}
+
+// SendTest sends a test alert to every notifier, so that their configuration
+// can be checked. Returns the notifiers' errors.
+func (s *service) SendTest(ctx context.Context) error {
+	if len(s.notifiers) == 0 {
+		return services.ErrInvalidArgument("no notifiers are configured")
+	}
+
+	return Notify(ctx, s.log, s.notifiers, models.Alert{
+		Key:       "test",
+		Rule:      "test",
+		Status:    StatusFiring,
+		Subject:   "Test notification",
+		Message:   "This is a test of the stormrage alert notifications.",
+		StartedAt: time.Now(),
+	})
+}
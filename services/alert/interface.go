@@ -0,0 +1,72 @@
+package alert
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/ab22/stormrage/services/monitor"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	Active() []models.Alert
+	SendTest(ctx context.Context) error
+}
+
+// Alert statuses.
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Rules evaluated by the service.
+const (
+	RuleRouterUnreachable = "router_unreachable"
+	RuleCPUHigh           = "cpu_high"
+	RuleClientDown        = "client_down"
+)
+
+// service evaluates the alert rules in the background and notifies the
+// alerts that start firing and the ones that are resolved.
+type service struct {
+	cfg             *config.Config
+	log             logger.Logger
+	mikrotikService mikrotik.Service
+	monitorService  monitor.Service
+	notifiers       []Notifier
+	rules           []rule
+
+	// Guards the firing alerts, by key.
+	mutex  sync.Mutex
+	active map[string]models.Alert
+}
+
+// NewService initialization. If alerting is enabled, a goroutine that
+// evaluates the rules every Alert.IntervalSeconds is started. Every alert
+// is sent to all of the notifiers.
+func NewService(cfg *config.Config, log logger.Logger, mikrotikService mikrotik.Service, monitorService monitor.Service, notifiers []Notifier) Service {
+	s := &service{
+		cfg:             cfg,
+		log:             log,
+		mikrotikService: mikrotikService,
+		monitorService:  monitorService,
+		notifiers:       notifiers,
+		active:          map[string]models.Alert{},
+	}
+
+	s.rules = []rule{
+		{name: RuleRouterUnreachable, evaluate: s.routerUnreachable},
+		{name: RuleCPUHigh, evaluate: s.cpuHigh},
+		{name: RuleClientDown, evaluate: s.clientDown},
+	}
+
+	if cfg.Alert.IntervalSeconds > 0 {
+		go s.run()
+	}
+
+	return s
+}
@@ -0,0 +1,299 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+)
+
+// notifyTimeout is the time a notifier has to deliver an alert.
+const notifyTimeout = 15 * time.Second
+
+// Notifier delivers alerts to an external channel.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert models.Alert) error
+}
+
+// NewNotifiers creates the notifiers enabled in the configuration.
+func NewNotifiers(cfg *config.Config) []Notifier {
+	var (
+		notifiers []Notifier
+		client    = &http.Client{Timeout: notifyTimeout}
+	)
+
+	if cfg.Alert.SMTPHost != "" {
+		notifiers = append(notifiers, NewEmailNotifier(
+			cfg.Alert.SMTPHost,
+			cfg.Alert.SMTPPort,
+			cfg.Alert.SMTPUser,
+			cfg.Alert.SMTPPassword,
+			cfg.Alert.EmailFrom,
+			cfg.Alert.EmailTo,
+		))
+	}
+
+	if cfg.Alert.WebhookURL != "" {
+		notifiers = append(notifiers, NewWebhookNotifier(client, cfg.Alert.WebhookURL))
+	}
+
+	if cfg.Alert.TelegramToken != "" {
+		notifiers = append(notifiers, NewTelegramNotifier(
+			client,
+			cfg.Alert.TelegramAPIURL,
+			cfg.Alert.TelegramToken,
+			cfg.Alert.TelegramChatID,
+		))
+	}
+
+	return notifiers
+}
+
+// Notify sends the alert to every notifier. Failures are logged and
+// returned together.
+func Notify(ctx context.Context, log logger.Logger, notifiers []Notifier, alert models.Alert) error {
+	var errs []error
+
+	for _, n := range notifiers {
+		ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+		err := n.Notify(ctx, alert)
+		cancel()
+
+		if err != nil {
+			log.Error("alert: notification failed", "notifier", n.Name(), "key", alert.Key, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %v", n.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// title returns the alert's subject prefixed with its status, e.g.
+// "[FIRING] Router unreachable".
+func title(alert models.Alert) string {
+	return fmt.Sprintf("[%s] %s", strings.ToUpper(alert.Status), alert.Subject)
+}
+
+// text returns the plain text body of a notification.
+func text(alert models.Alert) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n\n%s\n\nStarted at: %s\n", title(alert), alert.Message, alert.StartedAt.Format(time.RFC1123))
+
+	if alert.ResolvedAt != nil {
+		fmt.Fprintf(&b, "Resolved at: %s\n", alert.ResolvedAt.Format(time.RFC1123))
+	}
+
+	return b.String()
+}
+
+// postJSON sends v as the JSON body of a POST request to target. Responses
+// other than 2xx are returned as errors. The URL is left out of the errors
+// because it may contain credentials.
+func postJSON(ctx context.Context, client *http.Client, target string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid url")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		if e, ok := err.(*url.Error); ok {
+			return e.Err
+		}
+
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status [%s]", res.Status)
+	}
+
+	return nil
+}
+
+// emailNotifier sends alerts by email through an SMTP server.
+type emailNotifier struct {
+	host     string
+	port     int
+	user     string
+	password string
+	from     string
+	to       []string
+}
+
+// NewEmailNotifier creates a Notifier that sends alerts from the address
+// from to the addresses to. The connection is upgraded with STARTTLS if the
+// server supports it, and authenticated if user is set.
+func NewEmailNotifier(host string, port int, user, password, from string, to []string) Notifier {
+	return &emailNotifier{
+		host:     host,
+		port:     port,
+		user:     user,
+		password: password,
+		from:     from,
+		to:       to,
+	}
+}
+
+func (n *emailNotifier) Name() string {
+	return "email"
+}
+
+// message builds the email with its headers.
+func (n *emailNotifier) message(alert models.Alert) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", title(alert)))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(text(alert), "\n", "\r\n"))
+
+	return b.Bytes()
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, alert models.Alert) error {
+	var (
+		dialer net.Dialer
+		addr   = net.JoinHostPort(n.host, strconv.Itoa(n.port))
+	)
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return err
+		}
+	}
+
+	if n.user != "" {
+		if err = c.Auth(smtp.PlainAuth("", n.user, n.password, n.host)); err != nil {
+			return err
+		}
+	}
+
+	if err = c.Mail(n.from); err != nil {
+		return err
+	}
+
+	for _, to := range n.to {
+		if err = c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(n.message(alert)); err != nil {
+		return err
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// webhookNotifier posts alerts as JSON to a URL.
+type webhookNotifier struct {
+	client *http.Client
+	url    string
+}
+
+// NewWebhookNotifier creates a Notifier that sends each models.Alert as the
+// JSON body of a POST request to url.
+func NewWebhookNotifier(client *http.Client, url string) Notifier {
+	return &webhookNotifier{
+		client: client,
+		url:    url,
+	}
+}
+
+func (n *webhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, alert models.Alert) error {
+	return postJSON(ctx, n.client, n.url, alert)
+}
+
+// telegramNotifier sends alerts as messages of a Telegram bot.
+type telegramNotifier struct {
+	client *http.Client
+	apiURL string
+	token  string
+	chatID string
+}
+
+// telegramMessage is the body of the bot API's sendMessage method.
+type telegramMessage struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+// NewTelegramNotifier creates a Notifier that sends alerts to the chat
+// through the sendMessage method of the bot API at apiURL. Any service that
+// implements the same method can be used.
+func NewTelegramNotifier(client *http.Client, apiURL, token, chatID string) Notifier {
+	return &telegramNotifier{
+		client: client,
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		token:  token,
+		chatID: chatID,
+	}
+}
+
+func (n *telegramNotifier) Name() string {
+	return "telegram"
+}
+
+func (n *telegramNotifier) Notify(ctx context.Context, alert models.Alert) error {
+	return postJSON(ctx, n.client, n.apiURL+"/bot"+n.token+"/sendMessage", telegramMessage{
+		ChatID: n.chatID,
+		Text:   text(alert),
+	})
+}
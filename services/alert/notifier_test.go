@@ -0,0 +1,328 @@
+package alert
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+)
+
+// testAlert returns the alert sent by the tests.
+func testAlert() models.Alert {
+	return models.Alert{
+		Key:       "router",
+		Rule:      "router_unreachable",
+		Status:    StatusFiring,
+		Subject:   "Router unreachable",
+		Message:   "The router did not answer.",
+		StartedAt: time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var (
+		received    models.Alert
+		contentType string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+
+		contentType = r.Header.Get("Content-Type")
+
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.Client(), server.URL+"/hooks/alerts")
+
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+
+	want := testAlert()
+	if received.Key != want.Key || received.Status != want.Status || received.Subject != want.Subject ||
+		received.Message != want.Message || !received.StartedAt.Equal(want.StartedAt) {
+		t.Errorf("received alert = %+v, want %+v", received, want)
+	}
+}
+
+func TestTelegramNotifier(t *testing.T) {
+	var (
+		path     string
+		received telegramMessage
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+
+		io.WriteString(w, `{"ok": true}`)
+	}))
+	defer server.Close()
+
+	// The trailing slash of the API URL is ignored.
+	n := NewTelegramNotifier(server.Client(), server.URL+"/", "123:secret", "-100200")
+
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if path != "/bot123:secret/sendMessage" {
+		t.Errorf("path = %q, want /bot123:secret/sendMessage", path)
+	}
+
+	if received.ChatID != "-100200" {
+		t.Errorf("chat_id = %q, want -100200", received.ChatID)
+	}
+
+	if !strings.HasPrefix(received.Text, "[FIRING] Router unreachable\n") || !strings.Contains(received.Text, "The router did not answer.") {
+		t.Errorf("text = %q", received.Text)
+	}
+}
+
+func TestHTTPNotifiersRejectNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	notifiers := []Notifier{
+		NewWebhookNotifier(server.Client(), server.URL),
+		NewTelegramNotifier(server.Client(), server.URL, "123:secret", "1"),
+	}
+
+	for _, n := range notifiers {
+		err := n.Notify(context.Background(), testAlert())
+
+		if err == nil {
+			t.Errorf("%s: Notify() error = nil, want an error", n.Name())
+			continue
+		}
+
+		if !strings.Contains(err.Error(), "401") {
+			t.Errorf("%s: error = %q, want the response status", n.Name(), err)
+		}
+
+		// The bot token is part of the URL and must not be logged.
+		if strings.Contains(err.Error(), "secret") {
+			t.Errorf("%s: error = %q contains the token", n.Name(), err)
+		}
+	}
+}
+
+func TestNotifyJoinsErrors(t *testing.T) {
+	var calls int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	log, err := logger.New(io.Discard, "error", logger.TextFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Notify(context.Background(), log, []Notifier{
+		NewWebhookNotifier(server.Client(), server.URL+"/fail"),
+		NewWebhookNotifier(server.Client(), server.URL+"/ok"),
+	}, testAlert())
+
+	if calls != 2 {
+		t.Errorf("calls = %d, want every notifier to be called", calls)
+	}
+
+	if err == nil || !strings.HasPrefix(err.Error(), "webhook: ") {
+		t.Errorf("Notify() error = %v, want the failing notifier's error", err)
+	}
+}
+
+// smtpSession is what the fake SMTP server received.
+type smtpSession struct {
+	commands []string
+	data     string
+}
+
+// fakeSMTP starts an SMTP server that accepts a single session. The reply
+// to the RCPT command can be changed to reject the recipients. The session
+// is sent to the returned channel once the client disconnects.
+func fakeSMTP(t *testing.T, extensions []string, rcptReply string) (string, int, <-chan smtpSession) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { ln.Close() })
+
+	sessions := make(chan smtpSession, 1)
+
+	go func() {
+		var session smtpSession
+
+		defer func() { sessions <- session }()
+
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var (
+			r     = bufio.NewReader(conn)
+			reply = func(lines ...string) {
+				for _, line := range lines {
+					io.WriteString(conn, line+"\r\n")
+				}
+			}
+		)
+
+		reply("220 localhost ESMTP fake")
+
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			command := strings.TrimRight(line, "\r\n")
+			session.commands = append(session.commands, command)
+
+			switch verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0]); verb {
+			case "EHLO":
+				lines := []string{"250-localhost"}
+				for _, ext := range extensions {
+					lines = append(lines, "250-"+ext)
+				}
+
+				reply(append(lines, "250 8BITMIME")...)
+			case "AUTH":
+				reply("235 2.7.0 Authentication successful")
+			case "MAIL":
+				reply("250 2.1.0 OK")
+			case "RCPT":
+				reply(rcptReply)
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					} else if line == ".\r\n" {
+						break
+					}
+
+					data.WriteString(line)
+				}
+
+				session.data = data.String()
+				reply("250 2.0.0 OK")
+			case "QUIT":
+				reply("221 2.0.0 Bye")
+				return
+			default:
+				reply("502 5.5.2 Command not recognized")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	return host, portNumber, sessions
+}
+
+func TestEmailNotifier(t *testing.T) {
+	host, port, sessions := fakeSMTP(t, []string{"AUTH PLAIN"}, "250 2.1.5 OK")
+
+	n := NewEmailNotifier(host, port, "alerts", "p4ss", "stormrage@example.com", []string{"noc@example.com", "admin@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := n.Notify(ctx, testAlert()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	session := <-sessions
+	commands := strings.Join(session.commands, "\n")
+
+	credentials := base64.StdEncoding.EncodeToString([]byte("\x00alerts\x00p4ss"))
+	for _, want := range []string{
+		"AUTH PLAIN " + credentials,
+		"MAIL FROM:<stormrage@example.com>",
+		"RCPT TO:<noc@example.com>",
+		"RCPT TO:<admin@example.com>",
+		"QUIT",
+	} {
+		if !strings.Contains(commands, want) {
+			t.Errorf("commands = %q, missing %q", commands, want)
+		}
+	}
+
+	for _, want := range []string{
+		"From: stormrage@example.com\r\n",
+		"To: noc@example.com, admin@example.com\r\n",
+		"Subject: [FIRING] Router unreachable\r\n",
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"\r\n\r\n[FIRING] Router unreachable\r\n\r\nThe router did not answer.\r\n",
+	} {
+		if !strings.Contains(session.data, want) {
+			t.Errorf("data = %q, missing %q", session.data, want)
+		}
+	}
+}
+
+func TestEmailNotifierRejectedRecipient(t *testing.T) {
+	host, port, sessions := fakeSMTP(t, nil, "550 5.1.1 No such user")
+
+	n := NewEmailNotifier(host, port, "", "", "stormrage@example.com", []string{"nobody@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := n.Notify(ctx, testAlert())
+	if err == nil || !strings.Contains(err.Error(), "No such user") {
+		t.Errorf("Notify() error = %v, want the server's rejection", err)
+	}
+
+	// Without a user the notifier must not authenticate.
+	session := <-sessions
+	for _, command := range session.commands {
+		if strings.HasPrefix(command, "AUTH") {
+			t.Errorf("unexpected %q", command)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ab22/stormrage/models"
+)
+
+// rule returns the alerts of a condition that are currently firing.
+type rule struct {
+	name     string
+	evaluate func(ctx context.Context) ([]models.Alert, error)
+}
+
+// routerUnreachable fires while the router's status can't be requested.
+func (s *service) routerUnreachable(ctx context.Context) ([]models.Alert, error) {
+	if _, err := s.mikrotikService.RequestRouterStatus(ctx); err != nil {
+		return []models.Alert{{
+			Key:     RuleRouterUnreachable,
+			Subject: "Router unreachable",
+			Message: fmt.Sprintf("The router at %s could not be queried: %v", s.cfg.PrivateRouter.Address, err),
+		}}, nil
+	}
+
+	return nil, nil
+}
+
+// cpuHigh fires while the router's CPU load is at or above the configured
+// threshold.
+func (s *service) cpuHigh(ctx context.Context) ([]models.Alert, error) {
+	status, err := s.mikrotikService.RequestRouterStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if status.CPULoad < int64(s.cfg.Alert.CPUThreshold) {
+		return nil, nil
+	}
+
+	return []models.Alert{{
+		Key:     RuleCPUHigh,
+		Subject: "Router CPU load is high",
+		Message: fmt.Sprintf("The CPU load of %s is %d%%, the threshold is %d%%.", status.Identity, status.CPULoad, s.cfg.Alert.CPUThreshold),
+	}}, nil
+}
+
+// clientDown fires for every client with an ongoing outage detected by the
+// monitoring service. The clients' names are included if the router can be
+// queried.
+func (s *service) clientDown(ctx context.Context) ([]models.Alert, error) {
+	outages, err := s.monitorService.OngoingOutages()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(outages) == 0 {
+		return nil, nil
+	}
+
+	names := map[string]string{}
+
+	if clients, err := s.mikrotikService.RequestClients(ctx); err == nil {
+		for _, client := range clients {
+			names[client.ID] = client.Name
+		}
+	}
+
+	alerts := make([]models.Alert, 0, len(outages))
+
+	for _, outage := range outages {
+		name := names[outage.ClientID]
+		if name == "" {
+			name = outage.ClientID
+		}
+
+		alerts = append(alerts, models.Alert{
+			Key:       RuleClientDown + ":" + outage.ClientID,
+			Subject:   fmt.Sprintf("Client %s is down", name),
+			Message:   fmt.Sprintf("Client %s (%s) has not replied to pings since %s.", name, outage.Address, outage.StartedAt.Format("2006-01-02 15:04:05")),
+			StartedAt: outage.StartedAt,
+		})
+	}
+
+	return alerts, nil
+}
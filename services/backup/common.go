@@ -0,0 +1,151 @@
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/jinzhu/gorm"
+)
+
+// scheduleTimeout bounds the time spent on a single scheduled backup.
+const scheduleTimeout = 2 * time.Minute
+
+// schedule creates a backup every Backup.IntervalHours. Failed backups are
+// logged and retried on the next tick.
+func (s *service) schedule() {
+	ticker := time.NewTicker(time.Duration(s.cfg.Backup.IntervalHours) * time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), scheduleTimeout)
+
+		if _, err := s.Create(ctx, TriggerScheduled, nil); err != nil {
+			s.log.Error("backup: scheduled backup failed", "error", err)
+		}
+
+		cancel()
+	}
+}
+
+// Create exports the router's configuration, stores it and deletes the
+// backups that exceed the configured retention.
+func (s *service) Create(ctx context.Context, trigger string, operatorID *int) (*models.RouterBackup, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	content, err := s.mikrotikService.ExportConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256([]byte(content))
+
+	backup := &models.RouterBackup{
+		Router:    s.cfg.PrivateRouter.Address,
+		Content:   content,
+		Size:      len(content),
+		Checksum:  hex.EncodeToString(sum[:]),
+		Trigger:   trigger,
+		CreatedBy: operatorID,
+	}
+
+	if err = s.db.Create(backup).Error; err != nil {
+		return nil, err
+	}
+
+	err = s.db.Exec(
+		"DELETE FROM router_backups WHERE id NOT IN (SELECT id FROM router_backups ORDER BY created_at DESC, id DESC LIMIT ?)",
+		s.cfg.Backup.Retention,
+	).Error
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Info(
+		"backup: router configuration stored",
+		"backup_id", backup.ID,
+		"trigger", trigger,
+		"size", backup.Size,
+	)
+
+	return backup, nil
+}
+
+// List returns all backups without their content, newest first.
+func (s *service) List() ([]models.RouterBackup, error) {
+	backups := []models.RouterBackup{}
+
+	err := s.db.
+		Select("id, router, size, checksum, trigger, created_by, created_at").
+		Order("created_at DESC, id DESC").
+		Find(&backups).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// Find searches for a backup by ID. Returns nil if it does not exist.
+func (s *service) Find(id int) (*models.RouterBackup, error) {
+	backup := &models.RouterBackup{}
+
+	err := s.db.
+		Where("id = ?", id).
+		First(backup).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return backup, nil
+}
+
+// Diff returns the unified diff between two backups. Returns
+// ErrRecordNotFound if any of the backups does not exist.
+func (s *service) Diff(fromID, toID int) (string, error) {
+	from, err := s.Find(fromID)
+	if err != nil {
+		return "", err
+	}
+
+	to, err := s.Find(toID)
+	if err != nil {
+		return "", err
+	}
+
+	if from == nil || to == nil {
+		return "", services.ErrRecordNotFound
+	}
+
+	return unifiedDiff(
+		fmt.Sprintf("backup-%d (%s)", from.ID, from.CreatedAt.Format(time.RFC3339)),
+		fmt.Sprintf("backup-%d (%s)", to.ID, to.CreatedAt.Format(time.RFC3339)),
+		splitLines(from.Content),
+		splitLines(to.Content),
+	), nil
+}
+
+// splitLines splits the script into lines. RouterOS uses CRLF line endings
+// in exported files.
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
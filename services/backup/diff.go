@@ -0,0 +1,275 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// edit is a single line of the edit script that turns a into b. Op is ' '
+// for unchanged lines, '-' for deleted lines and '+' for inserted lines.
+type edit struct {
+	op   byte
+	line string
+}
+
+// diffLines computes the shortest edit script between a and b with the
+// linear space variant of Myers' algorithm, so that diffing big exports
+// only needs memory proportional to their length. Within each change, the
+// deleted lines are placed before the inserted ones.
+func diffLines(a, b []string) []edit {
+	size := 2*((len(a)+len(b)+1)/2) + 3
+
+	d := &differ{
+		a:  a,
+		b:  b,
+		vf: make([]int, size),
+		vb: make([]int, size),
+	}
+
+	d.compare(0, len(a), 0, len(b))
+
+	// Sort each run of changes so that deletions come first.
+	for start := 0; start < len(d.edits); start++ {
+		if d.edits[start].op == ' ' {
+			continue
+		}
+
+		end := start
+		for end < len(d.edits) && d.edits[end].op != ' ' {
+			end++
+		}
+
+		sort.SliceStable(d.edits[start:end], func(i, j int) bool {
+			return d.edits[start+i].op == '-' && d.edits[start+j].op == '+'
+		})
+
+		start = end
+	}
+
+	return d.edits
+}
+
+// differ contains the state of diffLines. vf and vb are the furthest
+// reaching paths of the forward and reverse searches on each diagonal,
+// reused by every call to middleSnake.
+type differ struct {
+	a, b   []string
+	vf, vb []int
+	edits  []edit
+}
+
+// compare appends the edit script that turns a[aLo:aHi] into b[bLo:bHi].
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.edits = append(d.edits, edit{' ', d.a[aLo]})
+		aLo++
+		bLo++
+	}
+
+	suffix := 0
+	for aLo < aHi && bLo < bHi && d.a[aHi-1] == d.b[bHi-1] {
+		aHi--
+		bHi--
+		suffix++
+	}
+
+	switch {
+	case aLo == aHi:
+		for _, line := range d.b[bLo:bHi] {
+			d.edits = append(d.edits, edit{'+', line})
+		}
+	case bLo == bHi:
+		for _, line := range d.a[aLo:aHi] {
+			d.edits = append(d.edits, edit{'-', line})
+		}
+	default:
+		// Both ranges are non empty and differ at both ends, so there are
+		// at least 2 edits and both halves are smaller than the range.
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+
+		d.compare(aLo, x, bLo, y)
+
+		for ; x < u; x, y = x+1, y+1 {
+			d.edits = append(d.edits, edit{' ', d.a[x]})
+		}
+
+		d.compare(u, aHi, v, bHi)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.edits = append(d.edits, edit{' ', d.a[aHi+i]})
+	}
+}
+
+// middleSnake searches for the shortest edit script of a[aLo:aHi] and
+// b[bLo:bHi] from both ends at once, and returns the diagonal run of equal
+// lines where both searches meet, from x, y to u, v.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	var (
+		n, m   = aHi - aLo, bHi - bLo
+		delta  = n - m
+		odd    = delta%2 != 0
+		limit  = (n + m + 1) / 2
+		offset = limit + 1
+		vf, vb = d.vf, d.vb
+	)
+
+	vf[offset+1] = 0
+	vb[offset+1] = 0
+
+	for step := 0; step <= limit; step++ {
+		// Forward search, in coordinates from the start of the ranges.
+		for k := -step; k <= step; k += 2 {
+			var fx int
+
+			if k == -step || (k != step && vf[offset+k-1] < vf[offset+k+1]) {
+				fx = vf[offset+k+1]
+			} else {
+				fx = vf[offset+k-1] + 1
+			}
+
+			fy := fx - k
+			sx, sy := fx, fy
+
+			for fx < n && fy < m && d.a[aLo+fx] == d.b[bLo+fy] {
+				fx++
+				fy++
+			}
+
+			vf[offset+k] = fx
+
+			// The reverse search's diagonal of the same point.
+			if r := delta - k; odd && r >= -(step-1) && r <= step-1 && fx+vb[offset+r] >= n {
+				return aLo + sx, bLo + sy, aLo + fx, bLo + fy
+			}
+		}
+
+		// Reverse search, in coordinates from the end of the ranges.
+		for k := -step; k <= step; k += 2 {
+			var rx int
+
+			if k == -step || (k != step && vb[offset+k-1] < vb[offset+k+1]) {
+				rx = vb[offset+k+1]
+			} else {
+				rx = vb[offset+k-1] + 1
+			}
+
+			ry := rx - k
+			sx, sy := rx, ry
+
+			for rx < n && ry < m && d.a[aHi-1-rx] == d.b[bHi-1-ry] {
+				rx++
+				ry++
+			}
+
+			vb[offset+k] = rx
+
+			// The forward search's diagonal of the same point.
+			if f := delta - k; !odd && f >= -step && f <= step && rx+vf[offset+f] >= n {
+				return aHi - rx, bHi - ry, aHi - sx, bHi - sy
+			}
+		}
+	}
+
+	// Unreachable: the searches meet before step exceeds the limit.
+	return aLo, bLo, aHi, bHi
+}
+
+// unifiedDiff formats the differences between a and b in the unified diff
+// format. Returns an empty string if both are equal.
+func unifiedDiff(fromName, toName string, a, b []string) string {
+	var (
+		edits = diffLines(a, b)
+		out   strings.Builder
+	)
+
+	for start := 0; start < len(edits); {
+		// Find the next change.
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+
+		if start == len(edits) {
+			break
+		}
+
+		// Extend the hunk until there are more than 2*diffContext
+		// unchanged lines in a row or the script ends.
+		end, unchanged := start, 0
+		for i := start; i < len(edits) && unchanged <= 2*diffContext; i++ {
+			if edits[i].op == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+				end = i + 1
+			}
+		}
+
+		hunkStart := start - diffContext
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+
+		hunkEnd := end + diffContext
+		if hunkEnd > len(edits) {
+			hunkEnd = len(edits)
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+
+		writeHunk(&out, edits, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+
+	return out.String()
+}
+
+// writeHunk writes edits[start:end] with its @@ header. Line numbers are
+// computed from the edits that precede the hunk.
+func writeHunk(out *strings.Builder, edits []edit, start, end int) {
+	var aLine, bLine, aCount, bCount int
+
+	for _, e := range edits[:start] {
+		if e.op != '+' {
+			aLine++
+		}
+
+		if e.op != '-' {
+			bLine++
+		}
+	}
+
+	for _, e := range edits[start:end] {
+		if e.op != '+' {
+			aCount++
+		}
+
+		if e.op != '-' {
+			bCount++
+		}
+	}
+
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(aLine, aCount), hunkRange(bLine, bCount))
+
+	for _, e := range edits[start:end] {
+		out.WriteByte(e.op)
+		out.WriteString(e.line)
+		out.WriteByte('\n')
+	}
+}
+
+// hunkRange formats the line range of a hunk. Lines are numbered from 1 and
+// empty ranges point to the line before the hunk.
+func hunkRange(line, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", line)
+	}
+
+	return fmt.Sprintf("%d,%d", line+1, count)
+}
@@ -0,0 +1,56 @@
+package backup
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/jinzhu/gorm"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	Create(ctx context.Context, trigger string, operatorID *int) (*models.RouterBackup, error)
+	List() ([]models.RouterBackup, error)
+	Find(id int) (*models.RouterBackup, error)
+	Diff(fromID, toID int) (string, error)
+}
+
+// Triggers recorded in the backups.
+const (
+	TriggerScheduled = "scheduled"
+	TriggerManual    = "manual"
+)
+
+// service exports the router's configuration and keeps the latest backups
+// in the database.
+type service struct {
+	cfg             *config.Config
+	db              *gorm.DB
+	log             logger.Logger
+	mikrotikService mikrotik.Service
+
+	// Only one export can run at a time, because every export writes the
+	// same file on the router.
+	mutex sync.Mutex
+}
+
+// NewService initialization. If scheduled backups are enabled, a goroutine
+// that creates a backup every Backup.IntervalHours is started.
+func NewService(cfg *config.Config, db *gorm.DB, log logger.Logger, mikrotikService mikrotik.Service) Service {
+	s := &service{
+		cfg:             cfg,
+		db:              db,
+		log:             log,
+		mikrotikService: mikrotikService,
+	}
+
+	if cfg.Backup.IntervalHours > 0 {
+		go s.schedule()
+	}
+
+	return s
+}
@@ -0,0 +1,242 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/jinzhu/gorm"
+)
+
+// findAccount searches for the billing account of a client, or returns nil.
+func (s *service) findAccount(clientID string) (*models.BillingAccount, error) {
+	account := &models.BillingAccount{}
+
+	err := s.db.
+		Where("client_id = ?", clientID).
+		First(account).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return account, nil
+}
+
+// status returns the balance of the account at now.
+func (s *service) status(account *models.BillingAccount, plan models.Plan, now time.Time) (*models.AccountStatus, error) {
+	var (
+		invoiced     int64
+		overdue      int64
+		overdueSince *time.Time
+		paid         int64
+	)
+
+	err := s.db.Raw(`
+		SELECT
+			COALESCE(SUM(amount_cents), 0),
+			COALESCE(SUM(CASE WHEN due_at < ? THEN amount_cents - paid_cents ELSE 0 END), 0),
+			MIN(CASE WHEN due_at < ? AND paid_cents < amount_cents THEN due_at END)
+		FROM invoices
+		WHERE client_id = ? AND status <> ?`,
+		now, now, account.ClientID, InvoiceVoid,
+	).Row().Scan(&invoiced, &overdue, &overdueSince)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.
+		Model(&models.Payment{}).
+		Where("client_id = ?", account.ClientID).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Row().
+		Scan(&paid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AccountStatus{
+		BillingAccount: *account,
+		Plan:           plan,
+		Currency:       s.cfg.Billing.Currency,
+		BalanceCents:   invoiced - paid,
+		OverdueCents:   overdue,
+		Overdue:        overdue > 0,
+		OverdueSince:   overdueSince,
+	}, nil
+}
+
+// plansByID returns all plans by ID.
+func (s *service) plansByID() (map[int]models.Plan, error) {
+	plans, err := s.ListPlans()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]models.Plan, len(plans))
+	for _, plan := range plans {
+		byID[plan.ID] = plan
+	}
+
+	return byID, nil
+}
+
+// ListAccounts returns the balance of every client with a billing account,
+// or only of the overdue ones.
+func (s *service) ListAccounts(overdueOnly bool) ([]models.AccountStatus, error) {
+	var (
+		now      = time.Now()
+		accounts = []models.BillingAccount{}
+	)
+
+	plans, err := s.plansByID()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.
+		Order("client_id").
+		Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]models.AccountStatus, 0, len(accounts))
+
+	for i := range accounts {
+		status, err := s.status(&accounts[i], plans[accounts[i].PlanID], now)
+		if err != nil {
+			return nil, err
+		}
+
+		if overdueOnly && !status.Overdue {
+			continue
+		}
+
+		statuses = append(statuses, *status)
+	}
+
+	return statuses, nil
+}
+
+// Account returns the balance of the client's account, or nil if the
+// client has no billing account.
+func (s *service) Account(clientID string) (*models.AccountStatus, error) {
+	account, err := s.findAccount(clientID)
+	if err != nil || account == nil {
+		return nil, err
+	}
+
+	plan, err := s.FindPlan(account.PlanID)
+	if err != nil {
+		return nil, err
+	} else if plan == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	return s.status(account, *plan, time.Now())
+}
+
+// Assign sets the client's plan and the day of the month its invoices are
+// issued. A billing day of 0 is set to 1. New accounts are invoiced the
+// current period right away.
+func (s *service) Assign(ctx context.Context, clientID string, planID, billingDay int) (*models.AccountStatus, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	if billingDay == 0 {
+		billingDay = 1
+	}
+
+	if billingDay < 1 || billingDay > maxBillingDay {
+		return nil, services.ErrInvalidArgument(fmt.Sprintf("billingDay must be between 1 and %d", maxBillingDay))
+	}
+
+	plan, err := s.FindPlan(planID)
+	if err != nil {
+		return nil, err
+	} else if plan == nil {
+		return nil, services.ErrInvalidArgument("plan does not exist")
+	}
+
+	account, err := s.findAccount(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account == nil {
+		account = &models.BillingAccount{
+			ClientID:   clientID,
+			PlanID:     plan.ID,
+			BillingDay: billingDay,
+		}
+
+		if err = s.db.Create(account).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	account.PlanID = plan.ID
+	account.BillingDay = billingDay
+
+	if err = s.db.Save(account).Error; err != nil {
+		return nil, err
+	}
+
+	if err = s.issue(account, plan, now); err != nil {
+		return nil, err
+	}
+
+	if err = s.allocate(clientID, now); err != nil {
+		return nil, err
+	}
+
+	return s.status(account, *plan, now)
+}
+
+// Unassign removes the client's billing account, so it's no longer
+// invoiced, and reinstates the client if it's still suspended by the billing
+// job. Its invoices and payments are kept. Returns ErrRecordNotFound if the
+// client has no billing account.
+func (s *service) Unassign(ctx context.Context, clientID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	account, err := s.findAccount(clientID)
+	if err != nil {
+		return err
+	} else if account == nil {
+		return services.ErrRecordNotFound
+	}
+
+	client, err := s.mikrotikService.RequestClient(ctx, clientID)
+	if err != nil {
+		return err
+	}
+
+	if client != nil && client.Suspended {
+		event, err := s.billingSuspension(clientID)
+		if err != nil {
+			return err
+		}
+
+		if event != nil {
+			results := s.suspensionService.Restore(ctx, []string{clientID}, "billing account removed", nil)
+
+			if err = results[0].Err; err != nil && err != services.ErrRecordNotFound {
+				return err
+			}
+		}
+	}
+
+	return s.db.
+		Where("client_id = ?", clientID).
+		Delete(&models.BillingAccount{}).Error
+}
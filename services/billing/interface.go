@@ -0,0 +1,90 @@
+package billing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/ab22/stormrage/services/suspension"
+	"github.com/jinzhu/gorm"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	ListPlans() ([]models.Plan, error)
+	FindPlan(id int) (*models.Plan, error)
+	CreatePlan(plan *models.Plan) (*models.Plan, error)
+	UpdatePlan(id int, plan *models.Plan) (*models.Plan, error)
+	RemovePlan(id int) error
+
+	ListAccounts(overdueOnly bool) ([]models.AccountStatus, error)
+	Account(clientID string) (*models.AccountStatus, error)
+	Assign(ctx context.Context, clientID string, planID, billingDay int) (*models.AccountStatus, error)
+	Unassign(ctx context.Context, clientID string) error
+
+	Invoices(clientID string) ([]models.Invoice, error)
+	FindInvoice(id int) (*models.Invoice, error)
+	CreateInvoice(clientID, description string, amountCents int64) (*models.Invoice, error)
+	VoidInvoice(ctx context.Context, id int) (*models.Invoice, error)
+
+	Payments(clientID string) ([]models.Payment, error)
+	FindPayment(id int) (*models.Payment, error)
+	RegisterPayment(ctx context.Context, payment *models.Payment) (*models.Payment, error)
+
+	Run(ctx context.Context) error
+}
+
+// Statuses of an invoice.
+const (
+	InvoicePending = "pending"
+	InvoicePaid    = "paid"
+	InvoiceVoid    = "void"
+)
+
+// Methods of a payment.
+const (
+	MethodCash     = "cash"
+	MethodTransfer = "transfer"
+)
+
+// jobTimeout is the time limit of a run of the billing job.
+const jobTimeout = 10 * time.Minute
+
+// suspendReason is the reason of the suspensions made by the billing job.
+// Only those suspensions are reinstated automatically.
+const suspendReason = "overdue invoices"
+
+// service issues the clients' invoices, registers their payments and
+// suspends the accounts with overdue invoices.
+type service struct {
+	cfg               *config.Config
+	db                *gorm.DB
+	log               logger.Logger
+	mikrotikService   mikrotik.Service
+	suspensionService suspension.Service
+
+	// Serializes the changes of the accounts' invoices and suspension.
+	mutex sync.Mutex
+}
+
+// NewService initialization. If enabled, the billing job is run when it
+// starts and every Billing.IntervalHours.
+func NewService(cfg *config.Config, db *gorm.DB, log logger.Logger, mikrotikService mikrotik.Service, suspensionService suspension.Service) Service {
+	s := &service{
+		cfg:               cfg,
+		db:                db,
+		log:               log,
+		mikrotikService:   mikrotikService,
+		suspensionService: suspensionService,
+	}
+
+	if cfg.Billing.IntervalHours > 0 {
+		go s.run()
+	}
+
+	return s
+}
@@ -0,0 +1,270 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/jinzhu/gorm"
+)
+
+// maxBillingDay is the last day invoices can be issued on, so that every
+// month has it.
+const maxBillingDay = 28
+
+// periodStart returns the start of the billing period that contains t.
+// Periods start at midnight of the billing day.
+func periodStart(t time.Time, day int) time.Time {
+	y, m, d := t.Date()
+
+	if d < day {
+		m--
+	}
+
+	return time.Date(y, m, day, 0, 0, 0, 0, t.Location())
+}
+
+// nextPeriod returns the start of the first period of the account that has
+// not been invoiced. Periods are not invoiced twice if the billing day
+// changes. The invoices of a previous account of the client are kept when
+// it's unassigned, so the periods before the account was created are never
+// invoiced.
+func (s *service) nextPeriod(account *models.BillingAccount) (time.Time, error) {
+	var (
+		last  = &models.Invoice{}
+		floor = periodStart(account.CreatedAt.Local(), account.BillingDay)
+	)
+
+	err := s.db.
+		Where("client_id = ? AND period_start IS NOT NULL", account.ClientID).
+		Order("period_start DESC").
+		First(last).Error
+	if err == gorm.ErrRecordNotFound {
+		return floor, nil
+	} else if err != nil {
+		return time.Time{}, err
+	}
+
+	end := last.PeriodEnd.Local()
+	start := periodStart(end, account.BillingDay)
+
+	if start.Before(end) {
+		start = start.AddDate(0, 1, 0)
+	}
+
+	if start.Before(floor) {
+		start = floor
+	}
+
+	return start, nil
+}
+
+// issue creates the invoices of the account's periods that started until
+// now, so the periods missed while the job didn't run are also invoiced.
+// The first invoice is issued when the account is created.
+func (s *service) issue(account *models.BillingAccount, plan *models.Plan, now time.Time) error {
+	start, err := s.nextPeriod(account)
+	if err != nil {
+		return err
+	}
+
+	for ; !start.After(now); start = start.AddDate(0, 1, 0) {
+		var (
+			from     = start
+			to       = start.AddDate(0, 1, 0)
+			issuedAt = start
+			planID   = plan.ID
+		)
+
+		if issuedAt.Before(account.CreatedAt) {
+			issuedAt = account.CreatedAt
+		}
+
+		invoice := &models.Invoice{
+			ClientID:    account.ClientID,
+			PlanID:      &planID,
+			Description: fmt.Sprintf("%s %s", plan.Name, from.Format("01/2006")),
+			PeriodStart: &from,
+			PeriodEnd:   &to,
+			AmountCents: plan.PriceCents,
+			Status:      InvoicePending,
+			IssuedAt:    issuedAt,
+			DueAt:       issuedAt.AddDate(0, 0, s.cfg.Billing.DueDays),
+		}
+
+		if err = s.db.Create(invoice).Error; err != nil {
+			return err
+		}
+
+		s.log.Info("billing: invoice issued", "client_id", account.ClientID, "invoice_id", invoice.ID, "amount_cents", invoice.AmountCents)
+	}
+
+	return nil
+}
+
+// allocate applies the client's payments to its invoices in the order they
+// are due, and updates the invoices whose paid amount changed.
+func (s *service) allocate(clientID string, now time.Time) error {
+	var (
+		invoices = []models.Invoice{}
+		paid     int64
+	)
+
+	err := s.db.
+		Model(&models.Payment{}).
+		Where("client_id = ?", clientID).
+		Select("COALESCE(SUM(amount_cents), 0)").
+		Row().
+		Scan(&paid)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.
+		Where("client_id = ? AND status <> ?", clientID, InvoiceVoid).
+		Order("due_at, id").
+		Find(&invoices).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range invoices {
+		invoice := &invoices[i]
+		applied := min(paid, invoice.AmountCents)
+		paid -= applied
+
+		if applied == invoice.PaidCents {
+			continue
+		}
+
+		invoice.PaidCents = applied
+		invoice.Status = InvoicePending
+		invoice.PaidAt = nil
+
+		if applied == invoice.AmountCents {
+			invoice.Status = InvoicePaid
+			invoice.PaidAt = &now
+		}
+
+		if err = s.db.Save(invoice).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Invoices returns the invoices of a client, newest first.
+func (s *service) Invoices(clientID string) ([]models.Invoice, error) {
+	invoices := []models.Invoice{}
+
+	err := s.db.
+		Where("client_id = ?", clientID).
+		Order("issued_at DESC, id DESC").
+		Find(&invoices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// FindInvoice searches for an invoice by ID.
+// Returns *models.Invoice instance if it finds it, or nil otherwise.
+func (s *service) FindInvoice(id int) (*models.Invoice, error) {
+	invoice := &models.Invoice{}
+
+	err := s.db.
+		Where("id = ?", id).
+		First(invoice).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return invoice, nil
+}
+
+// CreateInvoice adds a charge to the client's account, e.g. an installation
+// fee. It's due after Billing.DueDays like the monthly invoices.
+func (s *service) CreateInvoice(clientID, description string, amountCents int64) (*models.Invoice, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return nil, services.ErrInvalidArgument("description is required")
+	}
+
+	if amountCents <= 0 {
+		return nil, services.ErrInvalidArgument("amountCents must be positive")
+	}
+
+	account, err := s.findAccount(clientID)
+	if err != nil {
+		return nil, err
+	} else if account == nil {
+		return nil, services.ErrInvalidArgument("client has no billing account")
+	}
+
+	invoice := &models.Invoice{
+		ClientID:    clientID,
+		Description: description,
+		AmountCents: amountCents,
+		Status:      InvoicePending,
+		IssuedAt:    now,
+		DueAt:       now.AddDate(0, 0, s.cfg.Billing.DueDays),
+	}
+
+	if err = s.db.Create(invoice).Error; err != nil {
+		return nil, err
+	}
+
+	// Credit from previous payments is applied to the new invoice.
+	if err = s.allocate(clientID, now); err != nil {
+		return nil, err
+	}
+
+	return s.FindInvoice(invoice.ID)
+}
+
+// VoidInvoice cancels an invoice. The payments applied to it are applied to
+// the client's other invoices, and the client is reinstated if it's no
+// longer overdue. Returns ErrRecordNotFound if the invoice does not exist.
+func (s *service) VoidInvoice(ctx context.Context, id int) (*models.Invoice, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	invoice, err := s.FindInvoice(id)
+	if err != nil {
+		return nil, err
+	} else if invoice == nil {
+		return nil, services.ErrRecordNotFound
+	} else if invoice.Status == InvoiceVoid {
+		return invoice, nil
+	}
+
+	invoice.Status = InvoiceVoid
+	invoice.PaidCents = 0
+	invoice.PaidAt = nil
+
+	if err = s.db.Save(invoice).Error; err != nil {
+		return nil, err
+	}
+
+	if err = s.settle(ctx, invoice.ClientID, now); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
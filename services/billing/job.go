@@ -0,0 +1,196 @@
+package billing
+
+import (
+	"context"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/ab22/stormrage/services/suspension"
+)
+
+// run runs the billing job when it starts and every Billing.IntervalHours,
+// so it runs even if the server restarts more often than the interval.
+func (s *service) run() {
+	ticker := time.NewTicker(time.Duration(s.cfg.Billing.IntervalHours) * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), jobTimeout)
+
+		if err := s.Run(ctx); err != nil {
+			s.log.Error("billing: job failed", "error", err)
+		}
+
+		cancel()
+		<-ticker.C
+	}
+}
+
+// Run issues the invoices of the periods that started, applies the
+// payments and suspends or reinstates every account according to its
+// overdue invoices. A failure with one account doesn't stop the others. If
+// the router's clients can't be requested, the invoices and payments are
+// still processed but no client is suspended or reinstated.
+func (s *service) Run(ctx context.Context) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var (
+		now      = time.Now()
+		accounts = []models.BillingAccount{}
+	)
+
+	plans, err := s.plansByID()
+	if err != nil {
+		return err
+	}
+
+	if err = s.db.Find(&accounts).Error; err != nil {
+		return err
+	}
+
+	clients := map[string]*models.Client{}
+
+	if list, err := s.mikrotikService.RequestClients(ctx); err != nil {
+		s.log.Error("billing: could not request clients", "error", err)
+	} else {
+		for i := range list {
+			clients[list[i].ID] = &list[i]
+		}
+	}
+
+	for i := range accounts {
+		account := &accounts[i]
+
+		plan, ok := plans[account.PlanID]
+		if !ok {
+			continue
+		}
+
+		if err = s.issue(account, &plan, now); err != nil {
+			s.log.Error("billing: could not issue invoices", "client_id", account.ClientID, "error", err)
+			continue
+		}
+
+		if err = s.allocate(account.ClientID, now); err != nil {
+			s.log.Error("billing: could not apply payments", "client_id", account.ClientID, "error", err)
+			continue
+		}
+
+		client, ok := clients[account.ClientID]
+		if !ok {
+			continue
+		}
+
+		if err = s.enforce(ctx, account, plan, client, now); err != nil {
+			s.log.Error("billing: could not update client", "client_id", account.ClientID, "error", err)
+		}
+	}
+
+	s.log.Info("billing: job finished", "accounts", len(accounts))
+	return nil
+}
+
+// settle applies the client's payments to its invoices and suspends or
+// reinstates it. Clients without a billing account or that no longer exist
+// on the router are left as they are.
+func (s *service) settle(ctx context.Context, clientID string, now time.Time) error {
+	if err := s.allocate(clientID, now); err != nil {
+		return err
+	}
+
+	account, err := s.findAccount(clientID)
+	if err != nil || account == nil {
+		return err
+	}
+
+	plan, err := s.FindPlan(account.PlanID)
+	if err != nil || plan == nil {
+		return err
+	}
+
+	client, err := s.mikrotikService.RequestClient(ctx, clientID)
+	if err != nil || client == nil {
+		return err
+	}
+
+	return s.enforce(ctx, account, *plan, client, now)
+}
+
+// billingSuspension returns the event of the client's current suspension if
+// the billing job suspended it, or nil otherwise. Suspensions made or
+// restored later by operators belong to them.
+func (s *service) billingSuspension(clientID string) (*models.SuspensionEvent, error) {
+	events, err := s.suspensionService.History(clientID)
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+
+	last := events[0]
+	if last.Action != suspension.ActionSuspend || last.Reason != suspendReason || last.OperatorID != nil {
+		return nil, nil
+	}
+
+	return &last, nil
+}
+
+// enforce suspends the client if an invoice is overdue for more than
+// Billing.GraceDays and it's not suspended on the router, and reinstates it
+// once that's no longer the case if its suspension was made by the billing
+// job. The account's Suspended flag follows the client's state.
+func (s *service) enforce(ctx context.Context, account *models.BillingAccount, plan models.Plan, client *models.Client, now time.Time) error {
+	status, err := s.status(account, plan, now)
+	if err != nil {
+		return err
+	}
+
+	graceEnded := status.OverdueSince != nil &&
+		now.After(status.OverdueSince.AddDate(0, 0, s.cfg.Billing.GraceDays))
+
+	var event *models.SuspensionEvent
+
+	if client.Suspended {
+		if event, err = s.billingSuspension(account.ClientID); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case graceEnded && !client.Suspended:
+		results := s.suspensionService.Suspend(ctx, []string{account.ClientID}, suspendReason, nil)
+		if err = results[0].Err; err != nil {
+			return err
+		}
+
+		account.Suspended = true
+		account.SuspendedAt = &now
+
+		s.log.Warn("billing: client suspended", "client_id", account.ClientID, "overdue_cents", status.OverdueCents)
+
+	case !graceEnded && event != nil:
+		results := s.suspensionService.Restore(ctx, []string{account.ClientID}, "invoices paid", nil)
+		if err = results[0].Err; err != nil && err != services.ErrRecordNotFound {
+			return err
+		}
+
+		account.Suspended = false
+		account.SuspendedAt = nil
+
+		s.log.Info("billing: client reinstated", "client_id", account.ClientID)
+
+	case account.Suspended != (event != nil):
+		// An operator suspended or restored the client since the last run.
+		account.Suspended = event != nil
+		account.SuspendedAt = nil
+
+		if event != nil {
+			account.SuspendedAt = &event.CreatedAt
+		}
+
+	default:
+		return nil
+	}
+
+	return s.db.Save(account).Error
+}
@@ -0,0 +1,115 @@
+package billing
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/jinzhu/gorm"
+)
+
+// validPayment checks the payment's fields. An empty method is set to cash
+// and an empty date to now.
+func validPayment(payment *models.Payment, now time.Time) error {
+	if payment.AmountCents <= 0 {
+		return services.ErrInvalidArgument("amountCents must be positive")
+	}
+
+	payment.Method = strings.ToLower(strings.TrimSpace(payment.Method))
+
+	switch payment.Method {
+	case "":
+		payment.Method = MethodCash
+	case MethodCash, MethodTransfer:
+	default:
+		return services.ErrInvalidArgument("method must be cash or transfer")
+	}
+
+	if payment.ReceivedAt.IsZero() {
+		payment.ReceivedAt = now
+	} else if payment.ReceivedAt.After(now) {
+		return services.ErrInvalidArgument("receivedAt can't be in the future")
+	}
+
+	return nil
+}
+
+// Payments returns the payments of a client, newest first.
+func (s *service) Payments(clientID string) ([]models.Payment, error) {
+	payments := []models.Payment{}
+
+	err := s.db.
+		Where("client_id = ?", clientID).
+		Order("received_at DESC, id DESC").
+		Find(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// FindPayment searches for a payment by ID.
+// Returns *models.Payment instance if it finds it, or nil otherwise.
+func (s *service) FindPayment(id int) (*models.Payment, error) {
+	payment := &models.Payment{}
+
+	err := s.db.
+		Where("id = ?", id).
+		First(payment).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return payment, nil
+}
+
+// RegisterPayment adds a payment to the client's account and applies it to
+// its invoices. If the client was suspended for its overdue invoices and
+// they are now paid, it's reinstated right away.
+func (s *service) RegisterPayment(ctx context.Context, payment *models.Payment) (*models.Payment, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	if err := validPayment(payment, now); err != nil {
+		return nil, err
+	}
+
+	account, err := s.findAccount(payment.ClientID)
+	if err != nil {
+		return nil, err
+	} else if account == nil {
+		return nil, services.ErrInvalidArgument("client has no billing account")
+	}
+
+	created := &models.Payment{
+		ClientID:    payment.ClientID,
+		AmountCents: payment.AmountCents,
+		Method:      payment.Method,
+		Reference:   strings.TrimSpace(payment.Reference),
+		Notes:       strings.TrimSpace(payment.Notes),
+		OperatorID:  payment.OperatorID,
+		ReceivedAt:  payment.ReceivedAt,
+	}
+
+	if err = s.db.Create(created).Error; err != nil {
+		return nil, err
+	}
+
+	s.log.Info("billing: payment registered", "client_id", created.ClientID, "payment_id", created.ID, "amount_cents", created.AmountCents, "operator_id", created.OperatorID)
+
+	if err = s.settle(ctx, created.ClientID, now); err != nil {
+		// The payment was already registered, so the error is only logged.
+		s.log.Error("billing: could not settle account", "client_id", created.ClientID, "error", err)
+	}
+
+	return created, nil
+}
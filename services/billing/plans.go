@@ -0,0 +1,154 @@
+package billing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/jinzhu/gorm"
+)
+
+// validPlan checks the plan's fields.
+func validPlan(plan *models.Plan) error {
+	plan.Name = strings.TrimSpace(plan.Name)
+	if plan.Name == "" {
+		return services.ErrInvalidArgument("plan name is required")
+	}
+
+	if plan.PriceCents <= 0 {
+		return services.ErrInvalidArgument("priceCents must be positive")
+	}
+
+	return nil
+}
+
+// ListPlans returns all plans.
+func (s *service) ListPlans() ([]models.Plan, error) {
+	plans := []models.Plan{}
+
+	err := s.db.
+		Order("name").
+		Find(&plans).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+// FindPlan searches for a plan by ID.
+// Returns *models.Plan instance if it finds it, or nil otherwise.
+func (s *service) FindPlan(id int) (*models.Plan, error) {
+	plan := &models.Plan{}
+
+	err := s.db.
+		Where("id = ?", id).
+		First(plan).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return plan, nil
+}
+
+// findPlanByName searches for a plan by name, or returns nil.
+func (s *service) findPlanByName(name string) (*models.Plan, error) {
+	plan := &models.Plan{}
+
+	err := s.db.
+		Where("name = ?", name).
+		First(plan).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return plan, nil
+}
+
+// CreatePlan adds a new plan. Plan names must be unique.
+func (s *service) CreatePlan(plan *models.Plan) (*models.Plan, error) {
+	if err := validPlan(plan); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findPlanByName(plan.Name)
+	if err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, services.ErrInvalidArgument("plan name is already in use")
+	}
+
+	created := &models.Plan{
+		Name:        plan.Name,
+		Description: plan.Description,
+		PriceCents:  plan.PriceCents,
+	}
+
+	if err = s.db.Create(created).Error; err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// UpdatePlan replaces the fields of a plan. A new price is charged from the
+// next invoices on. Returns ErrRecordNotFound if the plan does not exist.
+func (s *service) UpdatePlan(id int, plan *models.Plan) (*models.Plan, error) {
+	if err := validPlan(plan); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findPlanByName(plan.Name)
+	if err != nil {
+		return nil, err
+	} else if existing != nil && existing.ID != id {
+		return nil, services.ErrInvalidArgument("plan name is already in use")
+	}
+
+	updated, err := s.FindPlan(id)
+	if err != nil {
+		return nil, err
+	} else if updated == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	updated.Name = plan.Name
+	updated.Description = plan.Description
+	updated.PriceCents = plan.PriceCents
+
+	if err = s.db.Save(updated).Error; err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// RemovePlan deletes a plan that is not assigned to any client.
+func (s *service) RemovePlan(id int) error {
+	var assigned int
+
+	err := s.db.
+		Model(&models.BillingAccount{}).
+		Where("plan_id = ?", id).
+		Count(&assigned).Error
+	if err != nil {
+		return err
+	}
+
+	if assigned > 0 {
+		return services.ErrInvalidArgument(fmt.Sprintf("plan is assigned to %d clients", assigned))
+	}
+
+	return s.db.
+		Where("id = ?", id).
+		Delete(&models.Plan{}).Error
+}
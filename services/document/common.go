@@ -0,0 +1,157 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/jinzhu/gorm"
+)
+
+// client returns the client's details from the router. If the router can't
+// be reached, the document is printed with the client's ID only.
+func (s *service) client(ctx context.Context, id string) client {
+	c := client{ID: id}
+
+	found, err := s.mikrotikService.RequestClient(ctx, id)
+	if err != nil {
+		s.log.Warn("document: could not request client", "client_id", id, "error", err)
+		return c
+	} else if found != nil {
+		c.Name = found.Name
+		c.Target = strings.Join(found.TargetAddresses(), ", ")
+	}
+
+	return c
+}
+
+// operator returns the name of the user who received a payment.
+func (s *service) operator(id *int) (string, error) {
+	if id == nil {
+		return "", nil
+	}
+
+	u, err := s.userService.FindByID(*id)
+	if err != nil || u == nil {
+		return "", err
+	}
+
+	if name := strings.TrimSpace(u.FirstName + " " + u.LastName); name != "" {
+		return name, nil
+	}
+
+	return u.Username, nil
+}
+
+// save stores the document if requested.
+func (s *service) save(document *models.Document, store bool) (*models.Document, error) {
+	document.Size = len(document.Content)
+
+	if !store {
+		return document, nil
+	}
+
+	if err := s.db.Create(document).Error; err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// Invoice renders an invoice to PDF and stores it if requested. Returns
+// ErrRecordNotFound if the invoice does not exist.
+func (s *service) Invoice(ctx context.Context, id int, store bool, operatorID *int) (*models.Document, error) {
+	var plan *models.Plan
+
+	invoice, err := s.billingService.FindInvoice(id)
+	if err != nil {
+		return nil, err
+	} else if invoice == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	if invoice.PlanID != nil {
+		if plan, err = s.billingService.FindPlan(*invoice.PlanID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.save(&models.Document{
+		ClientID:  invoice.ClientID,
+		Kind:      KindInvoice,
+		InvoiceID: &invoice.ID,
+		FileName:  fmt.Sprintf("factura-%06d.pdf", invoice.ID),
+		Content:   s.renderInvoice(invoice, plan, s.client(ctx, invoice.ClientID), time.Now()),
+		CreatedBy: operatorID,
+	}, store)
+}
+
+// Receipt renders the receipt of a payment to PDF and stores it if
+// requested. Returns ErrRecordNotFound if the payment does not exist.
+func (s *service) Receipt(ctx context.Context, id int, store bool, operatorID *int) (*models.Document, error) {
+	payment, err := s.billingService.FindPayment(id)
+	if err != nil {
+		return nil, err
+	} else if payment == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	account, err := s.billingService.Account(payment.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	operator, err := s.operator(payment.OperatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.save(&models.Document{
+		ClientID:  payment.ClientID,
+		Kind:      KindReceipt,
+		PaymentID: &payment.ID,
+		FileName:  fmt.Sprintf("recibo-%06d.pdf", payment.ID),
+		Content:   s.renderReceipt(payment, account, operator, s.client(ctx, payment.ClientID), time.Now()),
+		CreatedBy: operatorID,
+	}, store)
+}
+
+// List returns the stored documents of a client without their content,
+// newest first.
+func (s *service) List(clientID string) ([]models.Document, error) {
+	documents := []models.Document{}
+
+	err := s.db.
+		Select("id, client_id, kind, invoice_id, payment_id, file_name, size, created_by, created_at").
+		Where("client_id = ?", clientID).
+		Order("created_at DESC").
+		Find(&documents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return documents, nil
+}
+
+// Find searches for a stored document by ID.
+// Returns *models.Document instance with its content if it finds it, or
+// nil otherwise.
+func (s *service) Find(id int) (*models.Document, error) {
+	document := &models.Document{}
+
+	err := s.db.
+		Where("id = ?", id).
+		First(document).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return document, nil
+}
@@ -0,0 +1,70 @@
+package document
+
+import (
+	"context"
+	"image/png"
+	"os"
+
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/billing"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/ab22/stormrage/services/user"
+	"github.com/jinzhu/gorm"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	Invoice(ctx context.Context, id int, store bool, operatorID *int) (*models.Document, error)
+	Receipt(ctx context.Context, id int, store bool, operatorID *int) (*models.Document, error)
+	List(clientID string) ([]models.Document, error)
+	Find(id int) (*models.Document, error)
+}
+
+// Kinds of documents.
+const (
+	KindInvoice = "invoice"
+	KindReceipt = "receipt"
+)
+
+// service renders the clients' invoices and payment receipts to PDF.
+type service struct {
+	cfg             *config.Config
+	db              *gorm.DB
+	log             logger.Logger
+	billingService  billing.Service
+	mikrotikService mikrotik.Service
+	userService     user.Service
+
+	// logo is printed on the header. It's nil if it could not be read.
+	logo *pdfImage
+}
+
+// NewService initialization. The logo is read from Document.LogoPath.
+func NewService(cfg *config.Config, db *gorm.DB, log logger.Logger, billingService billing.Service, mikrotikService mikrotik.Service, userService user.Service) Service {
+	s := &service{
+		cfg:             cfg,
+		db:              db,
+		log:             log,
+		billingService:  billingService,
+		mikrotikService: mikrotikService,
+		userService:     userService,
+	}
+
+	if f, err := os.Open(cfg.Document.LogoPath); err != nil {
+		log.Warn("document: could not open logo, documents are printed without it", "path", cfg.Document.LogoPath, "error", err)
+	} else {
+		img, err := png.Decode(f)
+		f.Close()
+
+		if err != nil {
+			log.Warn("document: could not decode logo, documents are printed without it", "path", cfg.Document.LogoPath, "error", err)
+		} else {
+			logo := newImage(img)
+			s.logo = &logo
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,269 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+)
+
+// Size of an A4 page in points.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+// Fonts of a page. Both are standard fonts every PDF reader has, so they
+// are not embedded.
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+// helveticaWidths are the widths of the printable ASCII characters in
+// Helvetica, in thousandths of the font size. The digits, punctuation and
+// space have the same width in Helvetica-Bold.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // ' ' to '/'
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // '0' to '?'
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // '@' to 'O'
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // 'P' to '_'
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // '`' to 'o'
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // 'p' to '~'
+}
+
+// winAnsi contains the characters of the Windows-1252 encoding that are
+// not in the same position as in Unicode.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// encodeText converts s to the WinAnsi encoding of the fonts. Characters
+// that are not in it are replaced by '?'.
+func encodeText(s string) []byte {
+	encoded := make([]byte, 0, len(s))
+
+	for _, r := range s {
+		switch {
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			encoded = append(encoded, byte(r))
+		case winAnsi[r] != 0:
+			encoded = append(encoded, winAnsi[r])
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+
+	return encoded
+}
+
+// textWidth returns the width of s in points. The width of the characters
+// outside of ASCII is estimated.
+func textWidth(s string, size float64) float64 {
+	var width int
+
+	for _, c := range encodeText(s) {
+		if c >= 0x20 && c < 0x7f {
+			width += helveticaWidths[c-0x20]
+		} else {
+			width += 556
+		}
+	}
+
+	return float64(width) * size / 1000
+}
+
+// pdfString returns s as a PDF literal string.
+func pdfString(s string) string {
+	var b strings.Builder
+
+	b.WriteByte('(')
+
+	for _, c := range encodeText(s) {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c >= 0x80:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	b.WriteByte(')')
+	return b.String()
+}
+
+// number formats a coordinate or size for a content stream.
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// pdfImage is an image drawn on a page, with its RGB samples and alpha
+// channel compressed. Alpha is nil if the image is opaque.
+type pdfImage struct {
+	width  int
+	height int
+	rgb    []byte
+	alpha  []byte
+}
+
+// compress returns data compressed with zlib, the FlateDecode filter.
+func compress(data []byte) []byte {
+	var b bytes.Buffer
+
+	w := zlib.NewWriter(&b)
+	w.Write(data)
+	w.Close()
+
+	return b.Bytes()
+}
+
+// newImage converts img to the samples of a PDF image.
+func newImage(img image.Image) pdfImage {
+	var (
+		bounds = img.Bounds()
+		rgb    = make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+		alpha  = make([]byte, 0, bounds.Dx()*bounds.Dy())
+		opaque = true
+	)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+
+			// The colors are premultiplied by the alpha.
+			if a > 0 && a < 0xffff {
+				r, g, b = r*0xffff/a, g*0xffff/a, b*0xffff/a
+			}
+
+			rgb = append(rgb, byte(r>>8), byte(g>>8), byte(b>>8))
+			alpha = append(alpha, byte(a>>8))
+
+			if a != 0xffff {
+				opaque = false
+			}
+		}
+	}
+
+	converted := pdfImage{
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+		rgb:    compress(rgb),
+	}
+
+	if !opaque {
+		converted.alpha = compress(alpha)
+	}
+
+	return converted
+}
+
+// page is a single A4 page. Coordinates are in points from the top left
+// corner of the page.
+type page struct {
+	content bytes.Buffer
+	images  []pdfImage
+}
+
+// text writes s with its baseline starting at x, y.
+func (p *page) text(x, y float64, font string, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td %s Tj ET\n",
+		font, number(size), number(x), number(pageHeight-y), pdfString(s))
+}
+
+// textRight writes s with its baseline ending at x, y.
+func (p *page) textRight(x, y float64, font string, size float64, s string) {
+	p.text(x-textWidth(s, size), y, font, size, s)
+}
+
+// line draws a line from x1, y1 to x2, y2.
+func (p *page) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n",
+		number(width), number(x1), number(pageHeight-y1), number(x2), number(pageHeight-y2))
+}
+
+// rect fills a rectangle whose top left corner is x, y with a gray level,
+// from 0 (black) to 1 (white).
+func (p *page) rect(x, y, width, height, gray float64) {
+	fmt.Fprintf(&p.content, "q %s g %s %s %s %s re f Q\n",
+		number(gray), number(x), number(pageHeight-y-height), number(width), number(height))
+}
+
+// image draws img in the rectangle whose top left corner is x, y.
+func (p *page) image(img pdfImage, x, y, width, height float64) {
+	p.images = append(p.images, img)
+
+	fmt.Fprintf(&p.content, "q %s 0 0 %s %s %s cm /Im%d Do Q\n",
+		number(width), number(height), number(x), number(pageHeight-y-height), len(p.images))
+}
+
+// bytes returns the PDF document with the page.
+func (p *page) bytes(title string) []byte {
+	var (
+		b       bytes.Buffer
+		objects []string
+		xobject strings.Builder
+	)
+
+	add := func(object string) int {
+		objects = append(objects, object)
+		return len(objects)
+	}
+
+	stream := func(dict string, data []byte) string {
+		return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+	}
+
+	// The catalog, pages and page are the first objects, so their numbers
+	// are known before the others are added.
+	add("<< /Type /Catalog /Pages 2 0 R >>")
+	add("<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	add("")
+
+	regular := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	bold := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, img := range p.images {
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode", img.width, img.height)
+
+		if img.alpha != nil {
+			mask := add(stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode", img.width, img.height), img.alpha))
+			dict += fmt.Sprintf(" /SMask %d 0 R", mask)
+		}
+
+		fmt.Fprintf(&xobject, " /Im%d %d 0 R", i+1, add(stream(dict, img.rgb)))
+	}
+
+	content := add(stream("/Filter /FlateDecode", compress(p.content.Bytes())))
+	info := add(fmt.Sprintf("<< /Title %s /Producer (stormrage) >>", pdfString(title)))
+
+	objects[2] = fmt.Sprintf(
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /%s %d 0 R /%s %d 0 R >> /XObject <<%s >> >> /Contents %d 0 R >>",
+		number(pageWidth), number(pageHeight), fontRegular, regular, fontBold, bold, xobject.String(), content,
+	)
+
+	offsets := make([]int, len(objects))
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	for i, object := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, info, xref)
+
+	return b.Bytes()
+}
@@ -0,0 +1,256 @@
+package document
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/billing"
+)
+
+// Layout of the pages, in points.
+const (
+	marginLeft  = 50
+	marginRight = pageWidth - 50
+	dateFormat  = "02/01/2006"
+)
+
+// invoiceStatuses are the labels of the invoice statuses.
+var invoiceStatuses = map[string]string{
+	billing.InvoicePending: "Pendiente",
+	billing.InvoicePaid:    "Pagada",
+	billing.InvoiceVoid:    "Anulada",
+}
+
+// paymentMethods are the labels of the payment methods.
+var paymentMethods = map[string]string{
+	billing.MethodCash:     "Efectivo",
+	billing.MethodTransfer: "Transferencia",
+}
+
+// client contains the client's details printed on the documents.
+type client struct {
+	ID     string
+	Name   string
+	Target string
+}
+
+// formatMoney formats an amount in cents, e.g. "USD 1,234.50".
+func formatMoney(cents int64, currency string) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	var (
+		whole  = strconv.FormatInt(cents/100, 10)
+		groups []string
+	)
+
+	for len(whole) > 3 {
+		groups = append([]string{whole[len(whole)-3:]}, groups...)
+		whole = whole[:len(whole)-3]
+	}
+
+	groups = append([]string{whole}, groups...)
+
+	return fmt.Sprintf("%s %s%s.%02d", currency, sign, strings.Join(groups, ","), cents%100)
+}
+
+// orDash returns v, or a dash if it's empty.
+func orDash(v string) string {
+	if v == "" {
+		return "-"
+	}
+
+	return v
+}
+
+// header prints the logo, the company's details and the title of the
+// document. Returns the y coordinate below it.
+func (s *service) header(p *page, title string, number int) float64 {
+	doc := s.cfg.Document
+
+	if s.logo != nil {
+		// The logo is scaled to a height of 40 points.
+		width := float64(s.logo.width) * 40 / float64(s.logo.height)
+		p.image(*s.logo, marginLeft, 40, min(width, 250), 40)
+	}
+
+	y := 52.0
+	p.textRight(marginRight, y, fontBold, 14, doc.CompanyName)
+
+	for _, line := range []string{
+		doc.CompanyAddress,
+		prefixed("Tel. ", doc.CompanyPhone),
+		prefixed("ID fiscal: ", doc.CompanyTaxID),
+	} {
+		if line == "" {
+			continue
+		}
+
+		y += 12
+		p.textRight(marginRight, y, fontRegular, 9, line)
+	}
+
+	p.text(marginLeft, 130, fontBold, 20, title)
+	p.textRight(marginRight, 130, fontBold, 12, fmt.Sprintf("No. %06d", number))
+	p.line(marginLeft, 142, marginRight, 142, 1)
+
+	return 165
+}
+
+// prefixed returns v with the prefix, or an empty string if v is empty.
+func prefixed(prefix, v string) string {
+	if v == "" {
+		return ""
+	}
+
+	return prefix + v
+}
+
+// field prints a label with its value below it.
+func field(p *page, x, y float64, label, value string) {
+	p.text(x, y, fontBold, 9, label)
+	p.text(x, y+14, fontRegular, 10, orDash(value))
+}
+
+// clientFields prints the client's details. Returns the y coordinate below
+// them.
+func clientFields(p *page, y float64, c client) float64 {
+	field(p, marginLeft, y, "Cliente", c.Name)
+	field(p, 250, y, "Código de cliente", c.ID)
+	field(p, 400, y, "Dirección IP", c.Target)
+
+	return y + 40
+}
+
+// footer prints the date the document was generated.
+func footer(p *page, now time.Time) {
+	p.line(marginLeft, 790, marginRight, 790, 0.5)
+	p.text(marginLeft, 803, fontRegular, 8, "Documento generado el "+now.Format("02/01/2006 15:04"))
+}
+
+// renderInvoice returns the PDF of an invoice. The plan is nil for the
+// charges added by operators.
+func (s *service) renderInvoice(invoice *models.Invoice, plan *models.Plan, c client, now time.Time) []byte {
+	var (
+		p        = &page{}
+		currency = s.cfg.Billing.Currency
+		y        = s.header(p, "FACTURA", invoice.ID)
+	)
+
+	y = clientFields(p, y, c)
+
+	period := ""
+	if invoice.PeriodStart != nil && invoice.PeriodEnd != nil {
+		period = fmt.Sprintf("%s - %s",
+			invoice.PeriodStart.Local().Format(dateFormat),
+			invoice.PeriodEnd.Local().AddDate(0, 0, -1).Format(dateFormat),
+		)
+	}
+
+	field(p, marginLeft, y, "Fecha de emisión", invoice.IssuedAt.Local().Format(dateFormat))
+	field(p, 250, y, "Fecha de vencimiento", invoice.DueAt.Local().Format(dateFormat))
+	field(p, 400, y, "Periodo", period)
+
+	// Charges.
+	y += 55
+	p.rect(marginLeft, y, marginRight-marginLeft, 20, 0.9)
+	p.text(marginLeft+5, y+14, fontBold, 10, "Descripción")
+	p.textRight(marginRight-5, y+14, fontBold, 10, "Monto")
+
+	y += 40
+	p.text(marginLeft+5, y, fontRegular, 10, invoice.Description)
+	p.textRight(marginRight-5, y, fontRegular, 10, formatMoney(invoice.AmountCents, currency))
+
+	if plan != nil && plan.Description != "" {
+		y += 13
+		p.text(marginLeft+5, y, fontRegular, 8, "Plan "+plan.Name+": "+plan.Description)
+	}
+
+	y += 12
+	p.line(marginLeft, y, marginRight, y, 0.5)
+
+	// Totals.
+	for _, total := range []struct {
+		label string
+		cents int64
+		font  string
+	}{
+		{"Total", invoice.AmountCents, fontRegular},
+		{"Pagado", invoice.PaidCents, fontRegular},
+		{"Saldo pendiente", invoice.AmountCents - invoice.PaidCents, fontBold},
+	} {
+		y += 20
+		p.text(360, y, total.font, 10, total.label)
+		p.textRight(marginRight-5, y, total.font, 10, formatMoney(total.cents, currency))
+	}
+
+	y += 30
+	p.text(marginLeft, y, fontBold, 10, "Estado: ")
+	p.text(marginLeft+textWidth("Estado: ", 10), y, fontRegular, 10, invoiceStatuses[invoice.Status])
+
+	footer(p, now)
+	return p.bytes(fmt.Sprintf("Factura %06d", invoice.ID))
+}
+
+// renderReceipt returns the PDF of a payment receipt. The account is nil if
+// the client no longer has a billing account.
+func (s *service) renderReceipt(payment *models.Payment, account *models.AccountStatus, operator string, c client, now time.Time) []byte {
+	var (
+		p        = &page{}
+		currency = s.cfg.Billing.Currency
+		y        = s.header(p, "RECIBO DE PAGO", payment.ID)
+		plan     string
+	)
+
+	y = clientFields(p, y, c)
+
+	field(p, marginLeft, y, "Fecha de pago", payment.ReceivedAt.Local().Format(dateFormat))
+	field(p, 250, y, "Método de pago", paymentMethods[payment.Method])
+	field(p, 400, y, "Referencia", payment.Reference)
+
+	if account != nil {
+		plan = account.Plan.Name
+	}
+
+	y += 40
+	field(p, marginLeft, y, "Plan", plan)
+	field(p, 250, y, "Recibido por", operator)
+
+	// Amount.
+	y += 45
+	p.rect(marginLeft, y, marginRight-marginLeft, 50, 0.93)
+	p.text(marginLeft+10, y+30, fontBold, 12, "Monto recibido")
+	p.textRight(marginRight-10, y+32, fontBold, 18, formatMoney(payment.AmountCents, currency))
+
+	y += 80
+	if account != nil {
+		label, balance := "Saldo pendiente de la cuenta", account.BalanceCents
+		if balance < 0 {
+			label, balance = "Saldo a favor", -balance
+		}
+
+		p.text(marginLeft, y, fontBold, 10, label)
+		p.textRight(marginRight-5, y, fontBold, 10, formatMoney(balance, currency))
+		y += 25
+	}
+
+	if payment.Notes != "" {
+		p.text(marginLeft, y, fontBold, 9, "Notas")
+		p.text(marginLeft, y+14, fontRegular, 10, payment.Notes)
+		y += 25
+	}
+
+	// Signature.
+	y += 60
+	p.line(330, y, marginRight, y, 0.5)
+	p.text(330+(marginRight-330-textWidth("Firma y sello", 9))/2, y+13, fontRegular, 9, "Firma y sello")
+
+	footer(p, now)
+	return p.bytes(fmt.Sprintf("Recibo %06d", payment.ID))
+}
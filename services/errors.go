@@ -24,3 +24,11 @@ type ErrExpiredToken struct{}
 func (e *ErrExpiredToken) Error() string {
 	return fmt.Sprintf("token expired")
 }
+
+// ErrInvalidArgument indicates that a value sent to a service was not valid.
+// The message is meant to be shown to the user.
+type ErrInvalidArgument string
+
+func (e ErrInvalidArgument) Error() string {
+	return string(e)
+}
@@ -0,0 +1,192 @@
+package ipam
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// minPrefixBits limits the size of the subnets, so that their addresses
+	// can be enumerated when computing their usage.
+	minPrefixBits = 16
+
+	// maxPrefixBits is the smallest subnet that has usable host addresses.
+	maxPrefixBits = 30
+)
+
+// parseSubnet parses an IPv4 subnet in CIDR notation. The host bits are
+// cleared, e.g. 10.0.0.1/24 is parsed as 10.0.0.0/24.
+func parseSubnet(cidr string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+
+	if err != nil || !prefix.Addr().Is4() {
+		return netip.Prefix{}, services.ErrInvalidArgument("cidr must be an IPv4 subnet, e.g. 10.0.0.0/24")
+	}
+
+	if prefix.Bits() < minPrefixBits || prefix.Bits() > maxPrefixBits {
+		return netip.Prefix{}, services.ErrInvalidArgument(
+			fmt.Sprintf("subnet prefix must be between /%d and /%d", minPrefixBits, maxPrefixBits),
+		)
+	}
+
+	return prefix.Masked(), nil
+}
+
+// broadcast returns the last address of the subnet.
+func broadcast(prefix netip.Prefix) netip.Addr {
+	var (
+		b    = prefix.Addr().As4()
+		host = uint32(1)<<(32-prefix.Bits()) - 1
+	)
+
+	for i := 0; i < 4; i++ {
+		b[3-i] |= byte(host >> (8 * i))
+	}
+
+	return netip.AddrFrom4(b)
+}
+
+// isUsable checks if the address is a host address of the subnet, i.e. it's
+// neither the network nor the broadcast address.
+func isUsable(prefix netip.Prefix, addr netip.Addr) bool {
+	return prefix.Contains(addr) && addr != prefix.Addr() && addr != broadcast(prefix)
+}
+
+// ListSubnets returns all subnets of the router.
+func (s *service) ListSubnets() ([]models.Subnet, error) {
+	subnets := []models.Subnet{}
+
+	err := s.db.
+		Where("router = ?", s.cfg.PrivateRouter.Address).
+		Order("cidr").
+		Find(&subnets).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return subnets, nil
+}
+
+// FindSubnet searches for a subnet of the router by ID.
+// Returns *models.Subnet instance if it finds it, or nil otherwise.
+func (s *service) FindSubnet(id int) (*models.Subnet, error) {
+	subnet := &models.Subnet{}
+
+	err := s.db.
+		Where("id = ? AND router = ?", id, s.cfg.PrivateRouter.Address).
+		First(subnet).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return subnet, nil
+}
+
+// CreateSubnet adds a subnet to the router. The subnet must not overlap any
+// of the router's subnets and the gateway, if set, must be one of its host
+// addresses.
+func (s *service) CreateSubnet(name, cidr, gateway string) (*models.Subnet, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, services.ErrInvalidArgument("subnet name is required")
+	}
+
+	prefix, err := parseSubnet(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	if gateway = strings.TrimSpace(gateway); gateway != "" {
+		addr, err := netip.ParseAddr(gateway)
+
+		if err != nil || !isUsable(prefix, addr) {
+			return nil, services.ErrInvalidArgument("gateway must be a host address of the subnet")
+		}
+	}
+
+	subnets, err := s.ListSubnets()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, subnet := range subnets {
+		other, err := netip.ParsePrefix(subnet.CIDR)
+
+		if err == nil && other.Overlaps(prefix) {
+			return nil, services.ErrInvalidArgument(
+				fmt.Sprintf("subnet overlaps subnet [%s] %s", subnet.Name, subnet.CIDR),
+			)
+		}
+	}
+
+	subnet := &models.Subnet{
+		Router:  s.cfg.PrivateRouter.Address,
+		Name:    name,
+		CIDR:    prefix.String(),
+		Gateway: gateway,
+	}
+
+	if err = s.db.Create(subnet).Error; err != nil {
+		return nil, err
+	}
+
+	return subnet, nil
+}
+
+// RemoveSubnet deletes a subnet and its reservations. Returns
+// ErrRecordNotFound if the subnet does not exist.
+func (s *service) RemoveSubnet(id int) error {
+	result := s.db.
+		Where("id = ? AND router = ?", id, s.cfg.PrivateRouter.Address).
+		Delete(&models.Subnet{})
+
+	if err := result.Error; err != nil {
+		return err
+	} else if result.RowsAffected == 0 {
+		return services.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Reservations returns all reservations of a subnet.
+func (s *service) Reservations(subnetID int) ([]models.IPReservation, error) {
+	reservations := []models.IPReservation{}
+
+	err := s.db.
+		Where("subnet_id = ?", subnetID).
+		Order("id").
+		Find(&reservations).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// Release deletes a reservation. Returns ErrRecordNotFound if the
+// reservation does not exist.
+func (s *service) Release(id int) error {
+	result := s.db.
+		Where("id = ?", id).
+		Delete(&models.IPReservation{})
+
+	if err := result.Error; err != nil {
+		return err
+	} else if result.RowsAffected == 0 {
+		return services.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package ipam
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/jinzhu/gorm"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	ListSubnets() ([]models.Subnet, error)
+	FindSubnet(id int) (*models.Subnet, error)
+	CreateSubnet(name, cidr, gateway string) (*models.Subnet, error)
+	RemoveSubnet(id int) error
+
+	Usage(ctx context.Context, subnetID int) (*models.SubnetUsage, error)
+	NextFree(ctx context.Context, subnetID int) (string, error)
+	Reserve(ctx context.Context, subnetID int, address, clientID, note string, operatorID *int) (*models.IPReservation, error)
+	Reservations(subnetID int) ([]models.IPReservation, error)
+	Release(id int) error
+	Conflicts(ctx context.Context) ([]models.AddressConflict, error)
+}
+
+// Sources of the used addresses.
+const (
+	SourceGateway     = "gateway"
+	SourceQueue       = "queue"
+	SourceLease       = "lease"
+	SourceARP         = "arp"
+	SourceReservation = "reservation"
+)
+
+// service manages the subnets of the router and allocates addresses from
+// them.
+type service struct {
+	cfg             *config.Config
+	db              *gorm.DB
+	mikrotikService mikrotik.Service
+	mutex           sync.Mutex
+}
+
+// NewService initialization.
+func NewService(cfg *config.Config, db *gorm.DB, mikrotikService mikrotik.Service) Service {
+	return &service{
+		cfg:             cfg,
+		db:              db,
+		mikrotikService: mikrotikService,
+	}
+}
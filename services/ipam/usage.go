@@ -0,0 +1,350 @@
+package ipam
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+)
+
+// usage collects the addresses of a subnet that are in use.
+type usage struct {
+	prefix    netip.Prefix
+	addresses map[netip.Addr]*models.UsedAddress
+}
+
+// mark records that the address was found in the source. Addresses that are
+// not host addresses of the subnet are ignored and nil is returned.
+func (u *usage) mark(addr netip.Addr, source string) *models.UsedAddress {
+	if !isUsable(u.prefix, addr) {
+		return nil
+	}
+
+	used, ok := u.addresses[addr]
+	if !ok {
+		used = &models.UsedAddress{Address: addr.String()}
+		u.addresses[addr] = used
+	}
+
+	for _, s := range used.Sources {
+		if s == source {
+			return used
+		}
+	}
+
+	used.Sources = append(used.Sources, source)
+	return used
+}
+
+// markTarget marks the addresses of a queue's target, which is either a
+// single address or a subnet, as used by the client. Targets bigger than the
+// subnet are ignored; they usually belong to catch-all queues that limit a
+// whole network rather than to a client.
+func (u *usage) markTarget(target string, client models.Client) {
+	var addrs []netip.Addr
+
+	if strings.Contains(target, "/") {
+		prefix, err := netip.ParsePrefix(target)
+		if err != nil || prefix.Bits() < u.prefix.Bits() || !u.prefix.Overlaps(prefix) {
+			return
+		}
+
+		for addr := prefix.Masked().Addr(); prefix.Contains(addr); addr = addr.Next() {
+			addrs = append(addrs, addr)
+		}
+	} else if addr, err := netip.ParseAddr(target); err == nil {
+		addrs = append(addrs, addr)
+	}
+
+	for _, addr := range addrs {
+		if used := u.mark(addr, SourceQueue); used != nil && used.ClientID == "" {
+			used.ClientID = client.ID
+			used.ClientName = client.Name
+		}
+	}
+}
+
+// collectUsage finds the addresses of the subnet that are in use by the
+// gateway, the router's simple queues, DHCP leases and ARP entries, and the
+// subnet's reservations.
+func (s *service) collectUsage(ctx context.Context, subnet *models.Subnet) (*usage, error) {
+	prefix, err := netip.ParsePrefix(subnet.CIDR)
+	if err != nil {
+		return nil, fmt.Errorf("ipam: invalid subnet [%d] cidr: %v", subnet.ID, err)
+	}
+
+	u := &usage{
+		prefix:    prefix,
+		addresses: map[netip.Addr]*models.UsedAddress{},
+	}
+
+	if addr, err := netip.ParseAddr(subnet.Gateway); err == nil {
+		u.mark(addr, SourceGateway)
+	}
+
+	clients, err := s.mikrotikService.RequestClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, client := range clients {
+		for _, target := range client.TargetAddresses() {
+			u.markTarget(target, client)
+		}
+	}
+
+	leases, err := s.mikrotikService.RequestDHCPLeases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lease := range leases {
+		addr, err := netip.ParseAddr(lease.Address)
+
+		if err != nil || lease.Disabled {
+			continue
+		}
+
+		if used := u.mark(addr, SourceLease); used != nil && used.MACAddress == "" {
+			used.MACAddress = lease.MACAddress
+		}
+	}
+
+	entries, err := s.mikrotikService.RequestARPEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		addr, err := netip.ParseAddr(entry.Address)
+
+		if err != nil || entry.Disabled {
+			continue
+		}
+
+		if used := u.mark(addr, SourceARP); used != nil && used.MACAddress == "" {
+			used.MACAddress = entry.MACAddress
+		}
+	}
+
+	reservations, err := s.Reservations(subnet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reservation := range reservations {
+		addr, err := netip.ParseAddr(reservation.Address)
+		if err != nil {
+			continue
+		}
+
+		if used := u.mark(addr, SourceReservation); used != nil && used.ClientID == "" {
+			used.ClientID = reservation.ClientID
+		}
+	}
+
+	return u, nil
+}
+
+// nextFree returns the lowest host address of the subnet that is not in use.
+// Returns false if the subnet is full.
+func (u *usage) nextFree() (netip.Addr, bool) {
+	for addr := u.prefix.Addr().Next(); isUsable(u.prefix, addr); addr = addr.Next() {
+		if _, ok := u.addresses[addr]; !ok {
+			return addr, true
+		}
+	}
+
+	return netip.Addr{}, false
+}
+
+// findSubnet searches for the subnet and returns ErrRecordNotFound if it
+// does not exist.
+func (s *service) findSubnet(id int) (*models.Subnet, error) {
+	subnet, err := s.FindSubnet(id)
+
+	if err != nil {
+		return nil, err
+	} else if subnet == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	return subnet, nil
+}
+
+// Usage returns the addresses of the subnet that are in use, sorted by
+// address, and the subnet's next free address. Returns ErrRecordNotFound if
+// the subnet does not exist.
+func (s *service) Usage(ctx context.Context, subnetID int) (*models.SubnetUsage, error) {
+	subnet, err := s.findSubnet(subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.collectUsage(ctx, subnet)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		addrs  = make([]netip.Addr, 0, len(u.addresses))
+		size   = 1<<(32-u.prefix.Bits()) - 2
+		result = &models.SubnetUsage{
+			Subnet:    *subnet,
+			Size:      size,
+			Used:      len(u.addresses),
+			Free:      size - len(u.addresses),
+			Addresses: make([]models.UsedAddress, 0, len(u.addresses)),
+		}
+	)
+
+	for addr := range u.addresses {
+		addrs = append(addrs, addr)
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].Less(addrs[j])
+	})
+
+	for _, addr := range addrs {
+		result.Addresses = append(result.Addresses, *u.addresses[addr])
+	}
+
+	if addr, ok := u.nextFree(); ok {
+		result.NextFree = addr.String()
+	}
+
+	return result, nil
+}
+
+// NextFree returns the lowest address of the subnet that is not in use.
+// Returns ErrRecordNotFound if the subnet does not exist.
+func (s *service) NextFree(ctx context.Context, subnetID int) (string, error) {
+	subnet, err := s.findSubnet(subnetID)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := s.collectUsage(ctx, subnet)
+	if err != nil {
+		return "", err
+	}
+
+	addr, ok := u.nextFree()
+	if !ok {
+		return "", services.ErrInvalidArgument(fmt.Sprintf("subnet [%s] has no free addresses", subnet.Name))
+	}
+
+	return addr.String(), nil
+}
+
+// Reserve sets an address of the subnet aside for a client. If the address
+// is empty, the subnet's next free address is reserved. Returns
+// ErrRecordNotFound if the subnet does not exist.
+func (s *service) Reserve(ctx context.Context, subnetID int, address, clientID, note string, operatorID *int) (*models.IPReservation, error) {
+	// Reservations are serialized so that two concurrent requests don't get
+	// the same next free address.
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	subnet, err := s.findSubnet(subnetID)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.collectUsage(ctx, subnet)
+	if err != nil {
+		return nil, err
+	}
+
+	var addr netip.Addr
+
+	if address = strings.TrimSpace(address); address == "" {
+		var ok bool
+
+		if addr, ok = u.nextFree(); !ok {
+			return nil, services.ErrInvalidArgument(fmt.Sprintf("subnet [%s] has no free addresses", subnet.Name))
+		}
+	} else {
+		if addr, err = netip.ParseAddr(address); err != nil || !isUsable(u.prefix, addr) {
+			return nil, services.ErrInvalidArgument("address must be a host address of the subnet")
+		}
+
+		if used, ok := u.addresses[addr]; ok {
+			return nil, services.ErrInvalidArgument(
+				fmt.Sprintf("address %s is already in use (%s)", addr, strings.Join(used.Sources, ", ")),
+			)
+		}
+	}
+
+	reservation := &models.IPReservation{
+		SubnetID:   subnet.ID,
+		Address:    addr.String(),
+		ClientID:   strings.TrimSpace(clientID),
+		Note:       strings.TrimSpace(note),
+		ReservedBy: operatorID,
+	}
+
+	if err = s.db.Create(reservation).Error; err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, nil).Info(
+		"ipam: address reserved",
+		"subnet_id", subnet.ID,
+		"address", reservation.Address,
+		"client_id", reservation.ClientID,
+	)
+
+	return reservation, nil
+}
+
+// Conflicts returns the addresses that are the target of more than one of
+// the router's simple queues, sorted by address.
+func (s *service) Conflicts(ctx context.Context) ([]models.AddressConflict, error) {
+	clients, err := s.mikrotikService.RequestClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		byAddr    = map[netip.Addr][]models.Client{}
+		conflicts = []models.AddressConflict{}
+		addrs     []netip.Addr
+	)
+
+	for _, client := range clients {
+		for _, target := range client.TargetAddresses() {
+			addr, err := netip.ParseAddr(target)
+			if err != nil {
+				continue
+			}
+
+			byAddr[addr] = append(byAddr[addr], client)
+		}
+	}
+
+	for addr, clients := range byAddr {
+		if len(clients) > 1 {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].Less(addrs[j])
+	})
+
+	for _, addr := range addrs {
+		conflicts = append(conflicts, models.AddressConflict{
+			Address: addr.String(),
+			Clients: byAddr[addr],
+		})
+	}
+
+	return conflicts, nil
+}
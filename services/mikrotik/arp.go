@@ -0,0 +1,182 @@
+package mikrotik
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	routeros "github.com/jda/routeros-api-go"
+)
+
+// RequestARPEntries returns all entries of the router's ARP table.
+func (s *service) RequestARPEntries(ctx context.Context) ([]models.ARPEntry, error) {
+	res, err := s.queryRouter(ctx, "/ip/arp/print")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.ARPEntry, 0, len(res.SubPairs))
+
+	for _, pair := range res.SubPairs {
+		entries = append(entries, models.ARPEntry{
+			ID:         pair[".id"],
+			Address:    pair["address"],
+			MACAddress: pair["mac-address"],
+			Interface:  pair["interface"],
+			Comment:    pair["comment"],
+			Dynamic:    parseBool(pair["dynamic"]),
+			Complete:   parseBool(pair["complete"]),
+			Disabled:   parseBool(pair["disabled"]),
+		})
+	}
+
+	return entries, nil
+}
+
+// AddStaticARPEntry locks the IP address to the MAC address. If the entry
+// has no interface, the interface of the current ARP entry of the address is
+// used.
+func (s *service) AddStaticARPEntry(ctx context.Context, entry *models.ARPEntry) (*models.ARPEntry, error) {
+	mac, err := net.ParseMAC(entry.MACAddress)
+	if err != nil {
+		return nil, services.ErrInvalidArgument(fmt.Sprintf("invalid MAC address [%s]", entry.MACAddress))
+	}
+
+	if net.ParseIP(entry.Address) == nil {
+		return nil, services.ErrInvalidArgument(fmt.Sprintf("invalid IP address [%s]", entry.Address))
+	}
+
+	entries, err := s.RequestARPEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	iface := entry.Interface
+	for _, e := range entries {
+		if e.Address != entry.Address {
+			continue
+		}
+
+		if !e.Dynamic {
+			return nil, services.ErrInvalidArgument(fmt.Sprintf("address [%s] already has a static ARP entry", entry.Address))
+		}
+
+		if iface == "" {
+			iface = e.Interface
+		}
+	}
+
+	if iface == "" {
+		return nil, services.ErrInvalidArgument("interface is required")
+	}
+
+	res, err := s.callRouter(ctx, "/ip/arp/add",
+		routeros.Pair{Key: "address", Value: entry.Address},
+		routeros.Pair{Key: "mac-address", Value: strings.ToUpper(mac.String())},
+		routeros.Pair{Key: "interface", Value: iface},
+		routeros.Pair{Key: "comment", Value: entry.Comment},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := res.GetPairVal("ret")
+
+	return &models.ARPEntry{
+		ID:         id,
+		Address:    entry.Address,
+		MACAddress: strings.ToUpper(mac.String()),
+		Interface:  iface,
+		Comment:    entry.Comment,
+	}, nil
+}
+
+// Lookup searches the ARP table, the DHCP leases and the simple queues for
+// an IP or MAC address. When searching by MAC address, the IP address found
+// in the ARP table or in the DHCP lease is used to find the client's queue.
+func (s *service) Lookup(ctx context.Context, query string) (*models.Lookup, error) {
+	result := &models.Lookup{}
+
+	if ip := net.ParseIP(query); ip != nil {
+		result.Address = ip.String()
+	} else if mac, err := net.ParseMAC(query); err == nil {
+		result.MACAddress = strings.ToUpper(mac.String())
+	} else {
+		return nil, services.ErrInvalidArgument(fmt.Sprintf("[%s] is not an IP or MAC address", query))
+	}
+
+	// matches checks if the address or MAC address is the one searched for.
+	matches := func(address, mac string) bool {
+		if result.Address != "" {
+			return address == result.Address
+		}
+
+		return strings.EqualFold(mac, result.MACAddress)
+	}
+
+	entries, err := s.RequestARPEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, e := range entries {
+		if matches(e.Address, e.MACAddress) {
+			result.ARPEntry = &entries[i]
+			break
+		}
+	}
+
+	leases, err := s.RequestDHCPLeases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, l := range leases {
+		if matches(l.Address, l.MACAddress) {
+			result.DHCPLease = &leases[i]
+			break
+		}
+	}
+
+	if e := result.ARPEntry; e != nil {
+		result.Address = e.Address
+		result.MACAddress = e.MACAddress
+		result.Interface = e.Interface
+	} else if l := result.DHCPLease; l != nil {
+		result.Address = l.Address
+		result.MACAddress = l.MACAddress
+	}
+
+	if result.Address == "" {
+		return result, nil
+	}
+
+	clients, err := s.RequestClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range clients {
+		for _, address := range clients[i].TargetAddresses() {
+			if address == result.Address || cidrContains(address, result.Address) {
+				result.Client = &clients[i]
+				return result, nil
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// cidrContains checks if the subnet contains the IP address.
+func cidrContains(subnet, address string) bool {
+	_, network, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return false
+	}
+
+	return network.Contains(net.ParseIP(address))
+}
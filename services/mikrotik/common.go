@@ -1,15 +1,17 @@
 package mikrotik
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/ab22/stormrage/logger"
 	routeros "github.com/jda/routeros-api-go"
 )
 
+// connectToRouter opens the connection to the router if it's not open yet.
+// The caller must hold the service's mutex.
 func (s *service) connectToRouter() error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
 	if s.client != nil {
 		return nil
 	}
@@ -33,20 +35,62 @@ func (s *service) connectToRouter() error {
 	return nil
 }
 
+// closeConn closes the connection to the router so that the next call opens
+// a new one. The caller must hold the service's mutex.
 func (s *service) closeConn() {
+	if s.client != nil {
+		s.client.Close()
+	}
+
 	s.client = nil
 }
 
-func (s *service) queryRouter(query string) (*routeros.Reply, error) {
+// exec runs fn with the router's client and logs the call with the request
+// scoped logger found in ctx, if any. The RouterOS API is a sequential
+// protocol over a single connection, so the service's mutex is held until
+// the whole reply is read.
+func (s *service) exec(ctx context.Context, command string, fn func(*routeros.Client) (routeros.Reply, error)) (*routeros.Reply, error) {
+	var (
+		start = time.Now()
+		log   = logger.FromContext(ctx, s.log).With("command", command)
+	)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	if err := s.connectToRouter(); err != nil {
+		log.Error("routeros call failed", "error", err)
 		return nil, err
 	}
 
-	res, err := s.client.Call(query, nil)
+	res, err := fn(s.client)
 	if err != nil {
 		s.closeConn()
+		log.Error("routeros call failed", "duration", time.Since(start), "error", err)
 		return nil, err
 	}
 
+	log.Debug("routeros call", "duration", time.Since(start), "replies", len(res.SubPairs))
 	return &res, nil
 }
+
+// queryRouter sends the command to the router without any parameters.
+func (s *service) queryRouter(ctx context.Context, query string) (*routeros.Reply, error) {
+	return s.callRouter(ctx, query)
+}
+
+// callRouter sends the command to the router with the specified attribute
+// words (=key=value).
+func (s *service) callRouter(ctx context.Context, command string, params ...routeros.Pair) (*routeros.Reply, error) {
+	return s.exec(ctx, command, func(c *routeros.Client) (routeros.Reply, error) {
+		return c.Call(command, params)
+	})
+}
+
+// findRouter sends a print command to the router filtered by the query
+// words (?key=value).
+func (s *service) findRouter(ctx context.Context, command string, q routeros.Query) (*routeros.Reply, error) {
+	return s.exec(ctx, command, func(c *routeros.Client) (routeros.Reply, error) {
+		return c.Query(command, q)
+	})
+}
@@ -0,0 +1,140 @@
+package mikrotik
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	routeros "github.com/jda/routeros-api-go"
+)
+
+// newDHCPLease creates a models.DHCPLease from a /ip/dhcp-server/lease
+// reply.
+func newDHCPLease(pair map[string]string) models.DHCPLease {
+	return models.DHCPLease{
+		ID:         pair[".id"],
+		Address:    pair["address"],
+		MACAddress: pair["mac-address"],
+		HostName:   pair["host-name"],
+		Server:     pair["server"],
+		Status:     pair["status"],
+		LastSeen:   pair["last-seen"],
+		Comment:    pair["comment"],
+		Dynamic:    parseBool(pair["dynamic"]),
+		Disabled:   parseBool(pair["disabled"]),
+	}
+}
+
+// RequestDHCPLeases returns all DHCP leases.
+func (s *service) RequestDHCPLeases(ctx context.Context) ([]models.DHCPLease, error) {
+	res, err := s.queryRouter(ctx, "/ip/dhcp-server/lease/print")
+	if err != nil {
+		return nil, err
+	}
+
+	leases := make([]models.DHCPLease, 0, len(res.SubPairs))
+
+	for _, pair := range res.SubPairs {
+		leases = append(leases, newDHCPLease(pair))
+	}
+
+	return leases, nil
+}
+
+// RequestDHCPLease searches for a DHCP lease by its ID.
+// Returns *models.DHCPLease instance if it finds it, or nil otherwise.
+func (s *service) RequestDHCPLease(ctx context.Context, id string) (*models.DHCPLease, error) {
+	res, err := s.findRouter(ctx, "/ip/dhcp-server/lease/print", routeros.Query{
+		Pairs: []routeros.Pair{
+			{Key: ".id", Value: id},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.SubPairs) == 0 {
+		return nil, nil
+	}
+
+	lease := newDHCPLease(res.SubPairs[0])
+	return &lease, nil
+}
+
+// MakeDHCPLeaseStatic converts a dynamic lease into a static lease, so the
+// device always gets the same address. Returns services.ErrRecordNotFound
+// if the lease does not exist.
+func (s *service) MakeDHCPLeaseStatic(ctx context.Context, id string) (*models.DHCPLease, error) {
+	lease, err := s.RequestDHCPLease(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if lease == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	if !lease.Dynamic {
+		return lease, nil
+	}
+
+	_, err = s.callRouter(ctx, "/ip/dhcp-server/lease/make-static", routeros.Pair{Key: ".id", Value: id})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.RequestDHCPLease(ctx, id)
+}
+
+// BindDHCPLease makes the lease static and assigns it the client's target
+// address, so the device behind the lease always gets the address shaped by
+// the client's queue. The client's target must be a single address. Returns
+// services.ErrRecordNotFound if the lease or the client do not exist.
+func (s *service) BindDHCPLease(ctx context.Context, id, clientID string) (*models.DHCPLease, error) {
+	client, err := s.RequestClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	} else if client == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	addresses := client.TargetAddresses()
+	if len(addresses) != 1 || net.ParseIP(addresses[0]) == nil {
+		return nil, services.ErrInvalidArgument(fmt.Sprintf("client [%s] must target a single IP address", client.Name))
+	}
+
+	if _, err = s.MakeDHCPLeaseStatic(ctx, id); err != nil {
+		return nil, err
+	}
+
+	_, err = s.callRouter(ctx, "/ip/dhcp-server/lease/set",
+		routeros.Pair{Key: ".id", Value: id},
+		routeros.Pair{Key: "address", Value: addresses[0]},
+		routeros.Pair{Key: "comment", Value: client.Name},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.RequestDHCPLease(ctx, id)
+}
+
+// markLeases sets the MAC address and host name of the clients whose target
+// address has a DHCP lease.
+func markLeases(clients []models.Client, leases []models.DHCPLease) {
+	byAddress := make(map[string]*models.DHCPLease, len(leases))
+
+	for i := range leases {
+		byAddress[leases[i].Address] = &leases[i]
+	}
+
+	for i := range clients {
+		for _, address := range clients[i].TargetAddresses() {
+			if lease, ok := byAddress[address]; ok {
+				clients[i].MACAddress = lease.MACAddress
+				clients[i].HostName = lease.HostName
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package mikrotik
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	routeros "github.com/jda/routeros-api-go"
+)
+
+const (
+	// exportFileName is the name of the file written on the router by the
+	// /export command. RouterOS appends the .rsc extension.
+	exportFileName = "stormrage-export"
+
+	// exportChunkSize is the number of bytes read on each /file/read call.
+	exportChunkSize = 32 * 1024
+
+	// exportWaitAttempts and exportWaitInterval bound the time spent waiting
+	// for the router to finish writing the export file.
+	exportWaitAttempts = 20
+	exportWaitInterval = 500 * time.Millisecond
+)
+
+// ExportConfiguration runs /export on the router and returns the resulting
+// script. The API does not return the export's output, so the script is
+// written to a file on the router, read back and removed afterwards.
+//
+// The file is read in chunks with /file/read, which is available on
+// RouterOS 7.13 and newer. Older versions only expose the file's contents
+// through /file/print, which truncates files bigger than 4KB, so an error is
+// returned instead of a partial export in that case.
+func (s *service) ExportConfiguration(ctx context.Context) (string, error) {
+	var fileName = exportFileName + ".rsc"
+
+	_, err := s.callRouter(ctx, "/export", routeros.Pair{Key: "file", Value: exportFileName})
+	if err != nil {
+		return "", err
+	}
+
+	file, err := s.waitForFile(ctx, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	defer s.callRouter(ctx, "/file/remove", routeros.Pair{Key: ".id", Value: file[".id"]})
+
+	size, err := strconv.Atoi(file["size"])
+	if err != nil {
+		return "", fmt.Errorf("mikrotik: invalid export file size [%s]", file["size"])
+	}
+
+	script, err := s.readFile(ctx, fileName, size)
+	if err == nil {
+		return script, nil
+	}
+
+	if contents := file["contents"]; len(contents) == size {
+		return contents, nil
+	}
+
+	return "", fmt.Errorf("mikrotik: could not read export file: %v", err)
+}
+
+// waitForFile polls /file/print until the file exists and its size stops
+// changing, which means the router finished writing it.
+func (s *service) waitForFile(ctx context.Context, name string) (map[string]string, error) {
+	var lastSize = "-1"
+
+	for i := 0; i < exportWaitAttempts; i++ {
+		res, err := s.findRouter(ctx, "/file/print", routeros.Query{
+			Pairs: []routeros.Pair{{Key: "name", Value: name}},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(res.SubPairs) > 0 {
+			file := res.SubPairs[0]
+
+			if file["size"] == lastSize {
+				return file, nil
+			}
+
+			lastSize = file["size"]
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(exportWaitInterval):
+		}
+	}
+
+	return nil, fmt.Errorf("mikrotik: timed out waiting for export file [%s]", name)
+}
+
+// readFile reads the whole file with /file/read.
+func (s *service) readFile(ctx context.Context, name string, size int) (string, error) {
+	var data []byte
+
+	for offset := 0; offset < size; {
+		res, err := s.callRouter(
+			ctx,
+			"/file/read",
+			routeros.Pair{Key: "file", Value: name},
+			routeros.Pair{Key: "offset", Value: strconv.Itoa(offset)},
+			routeros.Pair{Key: "chunk-size", Value: strconv.Itoa(exportChunkSize)},
+		)
+		if err != nil {
+			return "", err
+		}
+
+		chunk, err := res.GetPairVal("data")
+		if err != nil && len(res.SubPairs) > 0 {
+			chunk, err = res.SubPairs[0]["data"], nil
+		}
+
+		if err != nil || chunk == "" {
+			return "", fmt.Errorf("mikrotik: empty read at offset %d of [%s]", offset, name)
+		}
+
+		data = append(data, chunk...)
+		offset += len(chunk)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,299 @@
+package mikrotik
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	routeros "github.com/jda/routeros-api-go"
+)
+
+const (
+	// MaxVouchers is the maximum number of vouchers generated in a single
+	// batch.
+	MaxVouchers = 500
+
+	// voucherAlphabet leaves out characters that are easily confused when
+	// printed, such as 0/O and 1/l/I.
+	voucherAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	voucherNameSize     = 6
+	voucherPasswordSize = 6
+)
+
+// VoucherBatch describes the hotspot users created by
+// GenerateHotspotVouchers. Every user of the batch gets the same profile and
+// limits.
+type VoucherBatch struct {
+	Count           int
+	Prefix          string
+	Profile         string
+	Server          string
+	LimitUptime     string
+	LimitBytesTotal int64
+	Comment         string
+}
+
+// newHotspotUser creates a models.HotspotUser from a /ip/hotspot/user reply.
+func newHotspotUser(pair map[string]string) models.HotspotUser {
+	return models.HotspotUser{
+		ID:              pair[".id"],
+		Name:            pair["name"],
+		Password:        pair["password"],
+		Profile:         pair["profile"],
+		Server:          pair["server"],
+		LimitUptime:     pair["limit-uptime"],
+		LimitBytesTotal: parseInt(pair["limit-bytes-total"]),
+		Comment:         pair["comment"],
+		Disabled:        parseBool(pair["disabled"]),
+		Uptime:          pair["uptime"],
+		BytesIn:         parseInt(pair["bytes-in"]),
+		BytesOut:        parseInt(pair["bytes-out"]),
+	}
+}
+
+// hotspotUserParams converts the user into the attributes sent to the
+// /ip/hotspot/user add and set commands. The password is not sent if it's
+// empty, so it keeps its current value on updates.
+func hotspotUserParams(user *models.HotspotUser) []routeros.Pair {
+	var (
+		profile = user.Profile
+		server  = user.Server
+		uptime  = user.LimitUptime
+	)
+
+	if profile == "" {
+		profile = "default"
+	}
+
+	if server == "" {
+		server = "all"
+	}
+
+	// A limit of 0 removes the limit.
+	if uptime == "" {
+		uptime = "0s"
+	}
+
+	params := []routeros.Pair{
+		{Key: "name", Value: user.Name},
+		{Key: "profile", Value: profile},
+		{Key: "server", Value: server},
+		{Key: "limit-uptime", Value: uptime},
+		{Key: "limit-bytes-total", Value: strconv.FormatInt(user.LimitBytesTotal, 10)},
+		{Key: "comment", Value: user.Comment},
+	}
+
+	if user.Password != "" {
+		params = append(params, routeros.Pair{Key: "password", Value: user.Password})
+	}
+
+	return params
+}
+
+// RequestHotspotUsers returns all /ip/hotspot/user entries.
+func (s *service) RequestHotspotUsers(ctx context.Context) ([]models.HotspotUser, error) {
+	res, err := s.queryRouter(ctx, "/ip/hotspot/user/print")
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]models.HotspotUser, 0, len(res.SubPairs))
+
+	for _, pair := range res.SubPairs {
+		users = append(users, newHotspotUser(pair))
+	}
+
+	return users, nil
+}
+
+// RequestHotspotUser searches for a /ip/hotspot/user entry by its ID.
+// Returns *models.HotspotUser instance if it finds it, or nil otherwise.
+func (s *service) RequestHotspotUser(ctx context.Context, id string) (*models.HotspotUser, error) {
+	res, err := s.findRouter(ctx, "/ip/hotspot/user/print", routeros.Query{
+		Pairs: []routeros.Pair{
+			{Key: ".id", Value: id},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.SubPairs) == 0 {
+		return nil, nil
+	}
+
+	user := newHotspotUser(res.SubPairs[0])
+	return &user, nil
+}
+
+// addHotspotUser adds a /ip/hotspot/user entry and returns its ID.
+func (s *service) addHotspotUser(ctx context.Context, user *models.HotspotUser) (string, error) {
+	params := hotspotUserParams(user)
+
+	if user.Disabled {
+		params = append(params, routeros.Pair{Key: "disabled", Value: "yes"})
+	}
+
+	res, err := s.callRouter(ctx, "/ip/hotspot/user/add", params...)
+	if err != nil {
+		return "", err
+	}
+
+	return res.GetPairVal("ret")
+}
+
+// CreateHotspotUser adds a new /ip/hotspot/user entry and returns it.
+func (s *service) CreateHotspotUser(ctx context.Context, user *models.HotspotUser) (*models.HotspotUser, error) {
+	id, err := s.addHotspotUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.RequestHotspotUser(ctx, id)
+}
+
+// UpdateHotspotUser replaces the fields of a /ip/hotspot/user entry. The
+// password is left unchanged if it's empty.
+func (s *service) UpdateHotspotUser(ctx context.Context, id string, user *models.HotspotUser) (*models.HotspotUser, error) {
+	disabled := "no"
+	if user.Disabled {
+		disabled = "yes"
+	}
+
+	params := append([]routeros.Pair{{Key: ".id", Value: id}}, hotspotUserParams(user)...)
+	params = append(params, routeros.Pair{Key: "disabled", Value: disabled})
+
+	if _, err := s.callRouter(ctx, "/ip/hotspot/user/set", params...); err != nil {
+		return nil, err
+	}
+
+	return s.RequestHotspotUser(ctx, id)
+}
+
+// RemoveHotspotUser deletes a /ip/hotspot/user entry.
+func (s *service) RemoveHotspotUser(ctx context.Context, id string) error {
+	_, err := s.callRouter(ctx, "/ip/hotspot/user/remove", routeros.Pair{Key: ".id", Value: id})
+	return err
+}
+
+// RequestHotspotSessions returns all /ip/hotspot/active sessions.
+func (s *service) RequestHotspotSessions(ctx context.Context) ([]models.HotspotSession, error) {
+	res, err := s.queryRouter(ctx, "/ip/hotspot/active/print")
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.HotspotSession, 0, len(res.SubPairs))
+
+	for _, pair := range res.SubPairs {
+		sessions = append(sessions, models.HotspotSession{
+			ID:              pair[".id"],
+			User:            pair["user"],
+			Server:          pair["server"],
+			Address:         pair["address"],
+			MACAddress:      pair["mac-address"],
+			LoginBy:         pair["login-by"],
+			Uptime:          pair["uptime"],
+			SessionTimeLeft: pair["session-time-left"],
+			BytesIn:         parseInt(pair["bytes-in"]),
+			BytesOut:        parseInt(pair["bytes-out"]),
+		})
+	}
+
+	return sessions, nil
+}
+
+// LogoutHotspotSession removes an active hotspot session, which logs the
+// user out.
+func (s *service) LogoutHotspotSession(ctx context.Context, id string) error {
+	_, err := s.callRouter(ctx, "/ip/hotspot/active/remove", routeros.Pair{Key: ".id", Value: id})
+	return err
+}
+
+// randomString returns a random string of the voucher alphabet.
+func randomString(size int) (string, error) {
+	var (
+		b   = make([]byte, size)
+		max = big.NewInt(int64(len(voucherAlphabet)))
+	)
+
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+
+		b[i] = voucherAlphabet[n.Int64()]
+	}
+
+	return string(b), nil
+}
+
+// GenerateHotspotVouchers creates batch.Count hotspot users with random
+// names and passwords and returns them. If any of the users can't be
+// created, the users created so far are removed and the error is returned.
+func (s *service) GenerateHotspotVouchers(ctx context.Context, batch VoucherBatch) ([]models.HotspotUser, error) {
+	if batch.Count < 1 || batch.Count > MaxVouchers {
+		return nil, services.ErrInvalidArgument(fmt.Sprintf("count must be between 1 and %d", MaxVouchers))
+	}
+
+	var (
+		log   = logger.FromContext(ctx, s.log)
+		users = make([]models.HotspotUser, 0, batch.Count)
+		names = make(map[string]bool, batch.Count)
+	)
+
+	for len(users) < batch.Count {
+		name, err := randomString(voucherNameSize)
+		if err != nil {
+			return nil, err
+		}
+
+		password, err := randomString(voucherPasswordSize)
+		if err != nil {
+			return nil, err
+		}
+
+		name = strings.ToLower(batch.Prefix) + name
+		if names[name] {
+			continue
+		}
+
+		names[name] = true
+		users = append(users, models.HotspotUser{
+			Name:            name,
+			Password:        password,
+			Profile:         batch.Profile,
+			Server:          batch.Server,
+			LimitUptime:     batch.LimitUptime,
+			LimitBytesTotal: batch.LimitBytesTotal,
+			Comment:         batch.Comment,
+		})
+	}
+
+	for i := range users {
+		id, err := s.addHotspotUser(ctx, &users[i])
+		if err == nil {
+			users[i].ID = id
+			continue
+		}
+
+		for _, created := range users[:i] {
+			if err := s.RemoveHotspotUser(ctx, created.ID); err != nil {
+				log.Error("mikrotik: could not remove voucher", "name", created.Name, "error", err)
+			}
+		}
+
+		return nil, fmt.Errorf("mikrotik: could not create voucher [%s]: %v", users[i].Name, err)
+	}
+
+	log.Info("mikrotik: hotspot vouchers generated", "count", len(users), "profile", batch.Profile)
+	return users, nil
+}
@@ -1,33 +1,100 @@
 package mikrotik
 
 import (
+	"context"
 	"sync"
 
 	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/logger"
 	"github.com/ab22/stormrage/models"
 	routeros "github.com/jda/routeros-api-go"
 )
 
 // Service interface describes all functions that must be implemented.
 type Service interface {
-	RequestClients() ([]models.Client, error)
+	RequestClients(ctx context.Context) ([]models.Client, error)
+	RequestClient(ctx context.Context, id string) (*models.Client, error)
+	SuspendClient(ctx context.Context, id, comment string) (*models.Client, error)
+	RestoreClient(ctx context.Context, id string) (*models.Client, error)
+	RequestQueueStats(ctx context.Context) ([]models.QueueStats, error)
+	SetClientLimits(ctx context.Context, id string, limits models.QueueLimits) error
+
+	RequestPPPSecrets(ctx context.Context) ([]models.PPPSecret, error)
+	RequestPPPSecret(ctx context.Context, id string) (*models.PPPSecret, error)
+	CreatePPPSecret(ctx context.Context, secret *models.PPPSecret) (*models.PPPSecret, error)
+	UpdatePPPSecret(ctx context.Context, id string, secret *models.PPPSecret) (*models.PPPSecret, error)
+	SetPPPSecretDisabled(ctx context.Context, id string, disabled bool) error
+	RemovePPPSecret(ctx context.Context, id string) error
+	RequestPPPSessions(ctx context.Context) ([]models.PPPSession, error)
+	KickPPPSession(ctx context.Context, id string) error
+
+	RequestHotspotUsers(ctx context.Context) ([]models.HotspotUser, error)
+	RequestHotspotUser(ctx context.Context, id string) (*models.HotspotUser, error)
+	CreateHotspotUser(ctx context.Context, user *models.HotspotUser) (*models.HotspotUser, error)
+	UpdateHotspotUser(ctx context.Context, id string, user *models.HotspotUser) (*models.HotspotUser, error)
+	RemoveHotspotUser(ctx context.Context, id string) error
+	RequestHotspotSessions(ctx context.Context) ([]models.HotspotSession, error)
+	LogoutHotspotSession(ctx context.Context, id string) error
+	GenerateHotspotVouchers(ctx context.Context, batch VoucherBatch) ([]models.HotspotUser, error)
+
+	RequestDHCPLeases(ctx context.Context) ([]models.DHCPLease, error)
+	RequestDHCPLease(ctx context.Context, id string) (*models.DHCPLease, error)
+	MakeDHCPLeaseStatic(ctx context.Context, id string) (*models.DHCPLease, error)
+	BindDHCPLease(ctx context.Context, id, clientID string) (*models.DHCPLease, error)
+
+	RequestARPEntries(ctx context.Context) ([]models.ARPEntry, error)
+	AddStaticARPEntry(ctx context.Context, entry *models.ARPEntry) (*models.ARPEntry, error)
+	Lookup(ctx context.Context, query string) (*models.Lookup, error)
+
+	RequestQueueTypes(ctx context.Context) ([]models.QueueType, error)
+	RequestQueueType(ctx context.Context, id string) (*models.QueueType, error)
+	CreateQueueType(ctx context.Context, queueType *models.QueueType) (*models.QueueType, error)
+	UpdateQueueType(ctx context.Context, id string, queueType *models.QueueType) (*models.QueueType, error)
+	RemoveQueueType(ctx context.Context, id string) error
+	RequestQueueTree(ctx context.Context) ([]*models.QueueTree, error)
+	RequestQueueTreeEntry(ctx context.Context, id string) (*models.QueueTree, error)
+	CreateQueueTreeEntry(ctx context.Context, entry *models.QueueTree) (*models.QueueTree, error)
+	UpdateQueueTreeEntry(ctx context.Context, id string, entry *models.QueueTree) (*models.QueueTree, error)
+	RemoveQueueTreeEntry(ctx context.Context, id string) error
+
+	RequestInterfaces(ctx context.Context) ([]models.Interface, error)
+	SetInterfaceDisabled(ctx context.Context, id string, disabled bool) error
+	MonitorInterfaceTraffic(ctx context.Context, name string) (*models.InterfaceTraffic, error)
+	RequestWirelessRegistrations(ctx context.Context) ([]models.WirelessRegistration, error)
+	MonitorWirelessStation(ctx context.Context, mac string) (*models.WirelessRegistration, error)
+
+	Ping(ctx context.Context, address string, count int) (*models.PingResult, error)
+	RequestRouterStatus(ctx context.Context) (*models.RouterStatus, error)
+	RequestLogs(ctx context.Context, filter LogFilter) ([]models.LogEntry, error)
+	ExportConfiguration(ctx context.Context) (string, error)
 }
 
 type service struct {
 	cfg    *config.Config
+	log    logger.Logger
 	client *routeros.Client
 	mutex  sync.Mutex
+
+	// Cached router status document.
+	status      *models.RouterStatus
+	statusMutex sync.Mutex
 }
 
 // NewService initialization.
-func NewService(cfg *config.Config) Service {
+func NewService(cfg *config.Config, log logger.Logger) Service {
 	s := &service{
 		client: nil,
 		cfg:    cfg,
+		log:    log,
 		mutex:  sync.Mutex{},
 	}
 
-	s.connectToRouter()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.connectToRouter(); err != nil {
+		log.Warn("mikrotik: could not connect to router on startup", "error", err)
+	}
 
 	return s
 }
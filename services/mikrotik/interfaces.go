@@ -0,0 +1,90 @@
+package mikrotik
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	routeros "github.com/jda/routeros-api-go"
+)
+
+// parseInt parses the numeric values returned by RouterOS. Empty or invalid
+// values are returned as 0.
+func parseInt(v string) int64 {
+	n, _ := strconv.ParseInt(v, 10, 64)
+	return n
+}
+
+// RequestInterfaces returns all of the router's interfaces.
+func (s *service) RequestInterfaces(ctx context.Context) ([]models.Interface, error) {
+	res, err := s.queryRouter(ctx, "/interface/print")
+	if err != nil {
+		return nil, err
+	}
+
+	interfaces := make([]models.Interface, 0, len(res.SubPairs))
+
+	for _, pair := range res.SubPairs {
+		interfaces = append(interfaces, models.Interface{
+			ID:         pair[".id"],
+			Name:       pair["name"],
+			Type:       pair["type"],
+			MACAddress: pair["mac-address"],
+			MTU:        pair["mtu"],
+			Comment:    pair["comment"],
+			Running:    parseBool(pair["running"]),
+			Disabled:   parseBool(pair["disabled"]),
+			RxBytes:    parseInt(pair["rx-byte"]),
+			TxBytes:    parseInt(pair["tx-byte"]),
+			RxPackets:  parseInt(pair["rx-packet"]),
+			TxPackets:  parseInt(pair["tx-packet"]),
+			RxErrors:   parseInt(pair["rx-error"]),
+			TxErrors:   parseInt(pair["tx-error"]),
+			RxDrops:    parseInt(pair["rx-drop"]),
+			TxDrops:    parseInt(pair["tx-drop"]),
+		})
+	}
+
+	return interfaces, nil
+}
+
+// SetInterfaceDisabled enables or disables an interface.
+func (s *service) SetInterfaceDisabled(ctx context.Context, id string, disabled bool) error {
+	command := "/interface/enable"
+
+	if disabled {
+		command = "/interface/disable"
+	}
+
+	_, err := s.callRouter(ctx, command, routeros.Pair{Key: ".id", Value: id})
+	return err
+}
+
+// MonitorInterfaceTraffic returns the current traffic rates of the
+// interface. The vendored RouterOS client can't read the continuous replies
+// of monitor-traffic, so a single sample is requested with the 'once'
+// attribute and callers must poll to follow the interface's traffic.
+func (s *service) MonitorInterfaceTraffic(ctx context.Context, name string) (*models.InterfaceTraffic, error) {
+	res, err := s.callRouter(ctx, "/interface/monitor-traffic",
+		routeros.Pair{Key: "interface", Value: name},
+		routeros.Pair{Key: "once", Value: ""},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.SubPairs) == 0 {
+		return nil, services.ErrRecordNotFound
+	}
+
+	pair := res.SubPairs[0]
+
+	return &models.InterfaceTraffic{
+		Name:               name,
+		RxBitsPerSecond:    parseInt(pair["rx-bits-per-second"]),
+		TxBitsPerSecond:    parseInt(pair["tx-bits-per-second"]),
+		RxPacketsPerSecond: parseInt(pair["rx-packets-per-second"]),
+		TxPacketsPerSecond: parseInt(pair["tx-packets-per-second"]),
+	}, nil
+}
@@ -0,0 +1,160 @@
+package mikrotik
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+)
+
+// LogFilter contains the filters applied to the router's log entries. Empty
+// fields don't filter.
+type LogFilter struct {
+	// Topic must be one of the entry's topics, e.g. "dhcp" or "error".
+	Topic string
+
+	// Text must be contained in the entry's message. Case insensitive.
+	Text string
+
+	// From and To limit the entry's time.
+	From *time.Time
+	To   *time.Time
+
+	// AfterID only returns entries that were logged after the entry with
+	// this ID. It is ignored if the router's newest entry has a lower ID,
+	// which happens when the router reboots and numbers its log again.
+	AfterID string
+}
+
+// logTimeLayouts contains the formats RouterOS uses to print log times.
+// Entries of the current day only show the time, entries of the current year
+// don't show the year and RouterOS v7 prints full dates.
+var logTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"Jan/02/2006 15:04:05",
+	"Jan/02 15:04:05",
+	"15:04:05",
+}
+
+// parseLogTime parses the time of a log entry in the server's time zone.
+func parseLogTime(value string, now time.Time) (time.Time, bool) {
+	// Go's month layout only matches capitalized month names.
+	if len(value) > 0 {
+		value = strings.ToUpper(value[:1]) + value[1:]
+	}
+
+	for _, layout := range logTimeLayouts {
+		t, err := time.ParseInLocation(layout, value, now.Location())
+		if err != nil {
+			continue
+		}
+
+		switch layout {
+		case "15:04:05":
+			t = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, now.Location())
+		case "Jan/02 15:04:05":
+			t = t.AddDate(now.Year(), 0, 0)
+
+			// Entries from december read in january belong to the
+			// previous year.
+			if t.After(now) {
+				t = t.AddDate(-1, 0, 0)
+			}
+		}
+
+		return t, true
+	}
+
+	return time.Time{}, false
+}
+
+// ParseEntryID converts a RouterOS item ID (*1A) into a number so that IDs
+// can be compared. Returns -1 if the ID is not valid.
+func ParseEntryID(id string) int64 {
+	n, err := strconv.ParseInt(strings.TrimPrefix(id, "*"), 16, 64)
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
+
+// matches checks if the entry passes all filters.
+func (f *LogFilter) matches(entry *models.LogEntry, now time.Time) bool {
+	if f.Topic != "" {
+		found := false
+
+		for _, topic := range strings.Split(entry.Topics, ",") {
+			if strings.EqualFold(topic, f.Topic) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if f.Text != "" && !strings.Contains(strings.ToLower(entry.Message), strings.ToLower(f.Text)) {
+		return false
+	}
+
+	if f.AfterID != "" && ParseEntryID(entry.ID) <= ParseEntryID(f.AfterID) {
+		return false
+	}
+
+	if f.From != nil || f.To != nil {
+		t, ok := parseLogTime(entry.Time, now)
+
+		if !ok {
+			return false
+		}
+
+		if f.From != nil && t.Before(*f.From) {
+			return false
+		}
+
+		if f.To != nil && t.After(*f.To) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RequestLogs returns the router's log entries that match the filter, oldest
+// first.
+func (s *service) RequestLogs(ctx context.Context, filter LogFilter) ([]models.LogEntry, error) {
+	res, err := s.queryRouter(ctx, "/log/print")
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		now     = time.Now()
+		entries = make([]models.LogEntry, 0, len(res.SubPairs))
+	)
+
+	if n := len(res.SubPairs); n > 0 && filter.AfterID != "" &&
+		ParseEntryID(res.SubPairs[n-1][".id"]) < ParseEntryID(filter.AfterID) {
+		filter.AfterID = ""
+	}
+
+	for _, pair := range res.SubPairs {
+		entry := models.LogEntry{
+			ID:      pair[".id"],
+			Time:    pair["time"],
+			Topics:  pair["topics"],
+			Message: pair["message"],
+		}
+
+		if filter.matches(&entry, now) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,59 @@
+package mikrotik
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	routeros "github.com/jda/routeros-api-go"
+)
+
+// pingInterval is the time between the packets sent by Ping. It's shorter
+// than RouterOS' default of 1s so that probing many addresses doesn't hold
+// the router's connection for long.
+const pingInterval = "200ms"
+
+// parseRTT parses the round trip times returned by RouterOS, e.g. "1ms" or
+// "12ms345us", in milliseconds. Invalid values are returned as 0.
+func parseRTT(v string) float64 {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0
+	}
+
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Ping sends count ICMP echo requests from the router to the address. Each
+// reply of /ping contains the statistics of all packets sent so far, so the
+// result is read from the last reply.
+func (s *service) Ping(ctx context.Context, address string, count int) (*models.PingResult, error) {
+	res, err := s.callRouter(ctx, "/ping",
+		routeros.Pair{Key: "address", Value: address},
+		routeros.Pair{Key: "count", Value: strconv.Itoa(count)},
+		routeros.Pair{Key: "interval", Value: pingInterval},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.PingResult{
+		Address:    address,
+		Sent:       count,
+		PacketLoss: 100,
+	}
+
+	if len(res.SubPairs) == 0 {
+		return result, nil
+	}
+
+	last := res.SubPairs[len(res.SubPairs)-1]
+
+	result.Sent = int(parseInt(last["sent"]))
+	result.Received = int(parseInt(last["received"]))
+	result.PacketLoss = int(parseInt(last["packet-loss"]))
+	result.AvgRTT = parseRTT(last["avg-rtt"])
+
+	return result, nil
+}
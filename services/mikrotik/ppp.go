@@ -0,0 +1,195 @@
+package mikrotik
+
+import (
+	"context"
+
+	"github.com/ab22/stormrage/models"
+	routeros "github.com/jda/routeros-api-go"
+)
+
+// parseBool parses the boolean values returned by RouterOS.
+func parseBool(v string) bool {
+	return v == "true" || v == "yes"
+}
+
+// newPPPSecret creates a models.PPPSecret from a /ppp/secret reply.
+func newPPPSecret(pair map[string]string) models.PPPSecret {
+	return models.PPPSecret{
+		ID:            pair[".id"],
+		Name:          pair["name"],
+		Password:      pair["password"],
+		Service:       pair["service"],
+		Profile:       pair["profile"],
+		LocalAddress:  pair["local-address"],
+		RemoteAddress: pair["remote-address"],
+		CallerID:      pair["caller-id"],
+		Comment:       pair["comment"],
+		Disabled:      parseBool(pair["disabled"]),
+		LastLoggedOut: pair["last-logged-out"],
+	}
+}
+
+// pppSecretParams converts the secret into the attributes sent to the
+// /ppp/secret add and set commands. An empty password is not sent, so it
+// keeps its current value on updates. The other optional fields are only
+// sent when they are set, unless clearEmpty is true, in which case empty
+// fields are sent to remove their current value.
+func pppSecretParams(secret *models.PPPSecret, clearEmpty bool) []routeros.Pair {
+	var (
+		service = secret.Service
+		profile = secret.Profile
+	)
+
+	if service == "" {
+		service = "any"
+	}
+
+	if profile == "" {
+		profile = "default"
+	}
+
+	params := []routeros.Pair{
+		{Key: "name", Value: secret.Name},
+		{Key: "service", Value: service},
+		{Key: "profile", Value: profile},
+	}
+
+	if secret.Password != "" {
+		params = append(params, routeros.Pair{Key: "password", Value: secret.Password})
+	}
+
+	optional := []routeros.Pair{
+		{Key: "local-address", Value: secret.LocalAddress},
+		{Key: "remote-address", Value: secret.RemoteAddress},
+		{Key: "caller-id", Value: secret.CallerID},
+		{Key: "comment", Value: secret.Comment},
+	}
+
+	for _, p := range optional {
+		if clearEmpty || p.Value != "" {
+			params = append(params, p)
+		}
+	}
+
+	return params
+}
+
+// RequestPPPSecrets returns all /ppp/secret entries.
+func (s *service) RequestPPPSecrets(ctx context.Context) ([]models.PPPSecret, error) {
+	res, err := s.queryRouter(ctx, "/ppp/secret/print")
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]models.PPPSecret, 0, len(res.SubPairs))
+
+	for _, pair := range res.SubPairs {
+		secrets = append(secrets, newPPPSecret(pair))
+	}
+
+	return secrets, nil
+}
+
+// RequestPPPSecret searches for a /ppp/secret entry by its ID.
+// Returns *models.PPPSecret instance if it finds it, or nil otherwise.
+func (s *service) RequestPPPSecret(ctx context.Context, id string) (*models.PPPSecret, error) {
+	res, err := s.findRouter(ctx, "/ppp/secret/print", routeros.Query{
+		Pairs: []routeros.Pair{
+			{Key: ".id", Value: id},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.SubPairs) == 0 {
+		return nil, nil
+	}
+
+	secret := newPPPSecret(res.SubPairs[0])
+	return &secret, nil
+}
+
+// CreatePPPSecret adds a new /ppp/secret entry and returns it.
+func (s *service) CreatePPPSecret(ctx context.Context, secret *models.PPPSecret) (*models.PPPSecret, error) {
+	params := pppSecretParams(secret, false)
+
+	if secret.Disabled {
+		params = append(params, routeros.Pair{Key: "disabled", Value: "yes"})
+	}
+
+	res, err := s.callRouter(ctx, "/ppp/secret/add", params...)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.GetPairVal("ret")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.RequestPPPSecret(ctx, id)
+}
+
+// UpdatePPPSecret replaces the fields of a /ppp/secret entry. Empty
+// addresses, caller ID and comment are removed from the entry, while an
+// empty password is left unchanged.
+func (s *service) UpdatePPPSecret(ctx context.Context, id string, secret *models.PPPSecret) (*models.PPPSecret, error) {
+	params := append([]routeros.Pair{{Key: ".id", Value: id}}, pppSecretParams(secret, true)...)
+
+	if _, err := s.callRouter(ctx, "/ppp/secret/set", params...); err != nil {
+		return nil, err
+	}
+
+	return s.RequestPPPSecret(ctx, id)
+}
+
+// SetPPPSecretDisabled enables or disables a /ppp/secret entry. Disabling a
+// secret doesn't disconnect its active session.
+func (s *service) SetPPPSecretDisabled(ctx context.Context, id string, disabled bool) error {
+	command := "/ppp/secret/enable"
+
+	if disabled {
+		command = "/ppp/secret/disable"
+	}
+
+	_, err := s.callRouter(ctx, command, routeros.Pair{Key: ".id", Value: id})
+	return err
+}
+
+// RemovePPPSecret deletes a /ppp/secret entry.
+func (s *service) RemovePPPSecret(ctx context.Context, id string) error {
+	_, err := s.callRouter(ctx, "/ppp/secret/remove", routeros.Pair{Key: ".id", Value: id})
+	return err
+}
+
+// RequestPPPSessions returns all /ppp/active sessions.
+func (s *service) RequestPPPSessions(ctx context.Context) ([]models.PPPSession, error) {
+	res, err := s.queryRouter(ctx, "/ppp/active/print")
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.PPPSession, 0, len(res.SubPairs))
+
+	for _, pair := range res.SubPairs {
+		sessions = append(sessions, models.PPPSession{
+			ID:       pair[".id"],
+			Name:     pair["name"],
+			Service:  pair["service"],
+			CallerID: pair["caller-id"],
+			Address:  pair["address"],
+			Uptime:   pair["uptime"],
+			Encoding: pair["encoding"],
+		})
+	}
+
+	return sessions, nil
+}
+
+// KickPPPSession removes an active PPP session, which forces the client to
+// reconnect.
+func (s *service) KickPPPSession(ctx context.Context, id string) error {
+	_, err := s.callRouter(ctx, "/ppp/active/remove", routeros.Pair{Key: ".id", Value: id})
+	return err
+}
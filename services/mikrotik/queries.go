@@ -1,9 +1,34 @@
 package mikrotik
 
-import "github.com/ab22/stormrage/models"
+import (
+	"context"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 
-func (s *service) RequestClients() ([]models.Client, error) {
-	res, err := s.queryRouter("/queue/simple/print")
+	"github.com/ab22/stormrage/models"
+	routeros "github.com/jda/routeros-api-go"
+)
+
+// newClient creates a models.Client from a /queue/simple reply.
+func newClient(pair map[string]string) models.Client {
+	return models.Client{
+		ID:             pair[".id"],
+		Name:           pair["name"],
+		Target:         pair["target"],
+		MaxLimit:       pair["max-limit"],
+		BurstLimit:     pair["burst-limit"],
+		BurstThreshold: pair["burst-threshold"],
+		BurstTime:      pair["burst-time"],
+	}
+}
+
+// RequestClients returns all simple queues. Each client reports if it's
+// suspended and the MAC address and host name of the DHCP lease of its
+// target address.
+func (s *service) RequestClients(ctx context.Context) ([]models.Client, error) {
+	res, err := s.queryRouter(ctx, "/queue/simple/print")
 	if err != nil {
 		return nil, err
 	}
@@ -11,16 +36,164 @@ func (s *service) RequestClients() ([]models.Client, error) {
 	clients := make([]models.Client, 0, len(res.SubPairs))
 
 	for _, pair := range res.SubPairs {
-		clients = append(clients, models.Client{
-			ID:             pair[".id"],
-			Name:           pair["name"],
-			Target:         pair["target"],
-			MaxLimit:       pair["max-limit"],
-			BurstLimit:     pair["burst-limit"],
-			BurstThreshold: pair["burst-threshold"],
-			BurstTime:      pair["burst-time"],
-		})
+		clients = append(clients, newClient(pair))
 	}
 
+	suspended, err := s.requestSuspendedAddresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	leases, err := s.RequestDHCPLeases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	markSuspended(clients, suspended)
+	markLeases(clients, leases)
+
 	return clients, nil
 }
+
+// RequestClient searches for a simple queue by its ID.
+// Returns *models.Client instance if it finds it, or nil otherwise.
+func (s *service) RequestClient(ctx context.Context, id string) (*models.Client, error) {
+	res, err := s.findRouter(ctx, "/queue/simple/print", routeros.Query{
+		Pairs: []routeros.Pair{
+			{Key: ".id", Value: id},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.SubPairs) == 0 {
+		return nil, nil
+	}
+
+	suspended, err := s.requestSuspendedAddresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	leases, err := s.RequestDHCPLeases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := []models.Client{newClient(res.SubPairs[0])}
+	markSuspended(clients, suspended)
+	markLeases(clients, leases)
+
+	return &clients[0], nil
+}
+
+// parseCounterPair parses the "upload/download" values of the simple queue
+// counters, e.g. "1024/4096".
+func parseCounterPair(v string) (int64, int64) {
+	upload, download, _ := strings.Cut(v, "/")
+	return parseInt(upload), parseInt(download)
+}
+
+// RequestQueueStats returns the byte counters and current rates of every
+// simple queue. The counters are reset when the router reboots or the queue
+// is reset.
+func (s *service) RequestQueueStats(ctx context.Context) ([]models.QueueStats, error) {
+	res, err := s.callRouter(ctx, "/queue/simple/print", routeros.Pair{Key: "stats", Value: ""})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]models.QueueStats, 0, len(res.SubPairs))
+
+	for _, pair := range res.SubPairs {
+		queue := models.QueueStats{
+			ID:   pair[".id"],
+			Name: pair["name"],
+		}
+
+		queue.UploadBytes, queue.DownloadBytes = parseCounterPair(pair["bytes"])
+		queue.UploadRate, queue.DownloadRate = parseCounterPair(pair["rate"])
+
+		stats = append(stats, queue)
+	}
+
+	return stats, nil
+}
+
+// rateLimitRegexp matches the upload/download limits of a simple queue,
+// e.g. "512k/2M".
+var rateLimitRegexp = regexp.MustCompile(`^[0-9]+[kMG]?/[0-9]+[kMG]?$`)
+
+// IsValidRateLimit checks if v has the upload/download format of the
+// max-limit, burst-limit and burst-threshold of a simple queue.
+func IsValidRateLimit(v string) bool {
+	return rateLimitRegexp.MatchString(v)
+}
+
+// rateMultipliers are the values of the suffixes of the queue rates.
+var rateMultipliers = map[byte]float64{
+	'k': 1e3,
+	'M': 1e6,
+	'G': 1e9,
+}
+
+// ParseRate parses a queue rate in bits per second, e.g. "512k", "2M" or
+// "1000000". Returns false if the rate is invalid.
+func ParseRate(v string) (int64, bool) {
+	multiplier := 1.0
+
+	if len(v) > 0 {
+		if m, ok := rateMultipliers[v[len(v)-1]]; ok {
+			multiplier = m
+			v = v[:len(v)-1]
+		}
+	}
+
+	rate, err := strconv.ParseFloat(v, 64)
+	if err != nil || rate < 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return 0, false
+	}
+
+	return int64(math.Min(rate*multiplier, math.MaxInt64)), true
+}
+
+// ParseRateLimit parses the upload/download limits of a simple queue, e.g.
+// "512k/2M", in bits per second. A limit of 0 means unlimited and is
+// returned as math.MaxInt64 so that it sorts after every other limit.
+// Invalid limits are returned as 0.
+func ParseRateLimit(v string) (upload, download int64) {
+	parse := func(v string) int64 {
+		rate, ok := ParseRate(v)
+		if !ok {
+			return 0
+		} else if rate == 0 {
+			return math.MaxInt64
+		}
+
+		return rate
+	}
+
+	up, down, _ := strings.Cut(v, "/")
+	return parse(up), parse(down)
+}
+
+// SetClientLimits changes the limits of the client's simple queue. Empty
+// limits are left unchanged.
+func (s *service) SetClientLimits(ctx context.Context, id string, limits models.QueueLimits) error {
+	params := []routeros.Pair{{Key: ".id", Value: id}}
+
+	for _, p := range []routeros.Pair{
+		{Key: "max-limit", Value: limits.MaxLimit},
+		{Key: "burst-limit", Value: limits.BurstLimit},
+		{Key: "burst-threshold", Value: limits.BurstThreshold},
+		{Key: "burst-time", Value: limits.BurstTime},
+	} {
+		if p.Value != "" {
+			params = append(params, p)
+		}
+	}
+
+	_, err := s.callRouter(ctx, "/queue/simple/set", params...)
+	return err
+}
@@ -0,0 +1,309 @@
+package mikrotik
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	routeros "github.com/jda/routeros-api-go"
+)
+
+// pcqKind is the kind of the per connection queue types.
+const pcqKind = "pcq"
+
+// queueDefaults contains the values RouterOS gives to the optional queue
+// type and queue tree fields. They are sent to clear a field on updates,
+// since most of these fields don't accept an empty value.
+var queueDefaults = map[string]string{
+	"pcq-rate":        "0",
+	"pcq-classifier":  "",
+	"pcq-limit":       "50",
+	"pcq-total-limit": "2000",
+	"limit-at":        "0",
+	"max-limit":       "0",
+	"queue":           "default-small",
+	"priority":        "8",
+}
+
+// optionalQueueParams appends the optional fields that are set. If
+// clearEmpty is true, empty fields are sent with their default value
+// instead, so that they lose their current value.
+func optionalQueueParams(params, optional []routeros.Pair, clearEmpty bool) []routeros.Pair {
+	for _, p := range optional {
+		if p.Value != "" {
+			params = append(params, p)
+		} else if clearEmpty {
+			params = append(params, routeros.Pair{Key: p.Key, Value: queueDefaults[p.Key]})
+		}
+	}
+
+	return params
+}
+
+// newQueueType creates a models.QueueType from a /queue/type reply.
+func newQueueType(pair map[string]string) models.QueueType {
+	return models.QueueType{
+		ID:            pair[".id"],
+		Name:          pair["name"],
+		Kind:          pair["kind"],
+		PCQRate:       pair["pcq-rate"],
+		PCQClassifier: pair["pcq-classifier"],
+		PCQLimit:      pair["pcq-limit"],
+		PCQTotalLimit: pair["pcq-total-limit"],
+		Default:       parseBool(pair["default"]),
+	}
+}
+
+// queueTypeParams converts the queue type into the attributes sent to the
+// /queue/type add and set commands. Empty PCQ fields get the router's
+// default value. They are only sent when clearEmpty is true, so that
+// updates reset them.
+func queueTypeParams(queueType *models.QueueType, clearEmpty bool) []routeros.Pair {
+	kind := queueType.Kind
+
+	if kind == "" {
+		kind = pcqKind
+	}
+
+	params := []routeros.Pair{
+		{Key: "name", Value: queueType.Name},
+		{Key: "kind", Value: kind},
+	}
+
+	if kind != pcqKind {
+		return params
+	}
+
+	optional := []routeros.Pair{
+		{Key: "pcq-rate", Value: queueType.PCQRate},
+		{Key: "pcq-classifier", Value: queueType.PCQClassifier},
+		{Key: "pcq-limit", Value: queueType.PCQLimit},
+		{Key: "pcq-total-limit", Value: queueType.PCQTotalLimit},
+	}
+
+	return optionalQueueParams(params, optional, clearEmpty)
+}
+
+// RequestQueueTypes returns all /queue/type entries.
+func (s *service) RequestQueueTypes(ctx context.Context) ([]models.QueueType, error) {
+	res, err := s.queryRouter(ctx, "/queue/type/print")
+	if err != nil {
+		return nil, err
+	}
+
+	queueTypes := make([]models.QueueType, 0, len(res.SubPairs))
+
+	for _, pair := range res.SubPairs {
+		queueTypes = append(queueTypes, newQueueType(pair))
+	}
+
+	return queueTypes, nil
+}
+
+// RequestQueueType searches for a /queue/type entry by its ID.
+// Returns *models.QueueType instance if it finds it, or nil otherwise.
+func (s *service) RequestQueueType(ctx context.Context, id string) (*models.QueueType, error) {
+	res, err := s.findRouter(ctx, "/queue/type/print", routeros.Query{
+		Pairs: []routeros.Pair{
+			{Key: ".id", Value: id},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.SubPairs) == 0 {
+		return nil, nil
+	}
+
+	queueType := newQueueType(res.SubPairs[0])
+	return &queueType, nil
+}
+
+// CreateQueueType adds a new /queue/type entry and returns it. The kind
+// defaults to pcq.
+func (s *service) CreateQueueType(ctx context.Context, queueType *models.QueueType) (*models.QueueType, error) {
+	res, err := s.callRouter(ctx, "/queue/type/add", queueTypeParams(queueType, false)...)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.GetPairVal("ret")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.RequestQueueType(ctx, id)
+}
+
+// UpdateQueueType replaces the fields of a /queue/type entry.
+func (s *service) UpdateQueueType(ctx context.Context, id string, queueType *models.QueueType) (*models.QueueType, error) {
+	params := append([]routeros.Pair{{Key: ".id", Value: id}}, queueTypeParams(queueType, true)...)
+
+	if _, err := s.callRouter(ctx, "/queue/type/set", params...); err != nil {
+		return nil, err
+	}
+
+	return s.RequestQueueType(ctx, id)
+}
+
+// RemoveQueueType deletes a /queue/type entry.
+func (s *service) RemoveQueueType(ctx context.Context, id string) error {
+	_, err := s.callRouter(ctx, "/queue/type/remove", routeros.Pair{Key: ".id", Value: id})
+	return err
+}
+
+// newQueueTree creates a models.QueueTree from a /queue/tree reply.
+func newQueueTree(pair map[string]string) *models.QueueTree {
+	return &models.QueueTree{
+		ID:         pair[".id"],
+		Name:       pair["name"],
+		Parent:     pair["parent"],
+		PacketMark: pair["packet-mark"],
+		LimitAt:    pair["limit-at"],
+		MaxLimit:   pair["max-limit"],
+		Priority:   int(parseInt(pair["priority"])),
+		Queue:      pair["queue"],
+		Comment:    pair["comment"],
+		Disabled:   parseBool(pair["disabled"]),
+		Invalid:    parseBool(pair["invalid"]),
+		Children:   []*models.QueueTree{},
+	}
+}
+
+// queueTreeParams converts the entry into the attributes sent to the
+// /queue/tree add and set commands. Empty optional fields and a 0 priority
+// get the router's default value. They are only sent when clearEmpty is
+// true, so that updates reset them.
+func queueTreeParams(entry *models.QueueTree, clearEmpty bool) ([]routeros.Pair, error) {
+	if entry.Priority < 0 || entry.Priority > 8 {
+		return nil, services.ErrInvalidArgument("priority must be between 1 and 8, or 0 for the default")
+	}
+
+	params := []routeros.Pair{
+		{Key: "name", Value: entry.Name},
+		{Key: "parent", Value: entry.Parent},
+		{Key: "packet-mark", Value: entry.PacketMark},
+		{Key: "comment", Value: entry.Comment},
+	}
+
+	priority := ""
+	if entry.Priority != 0 {
+		priority = strconv.Itoa(entry.Priority)
+	}
+
+	optional := []routeros.Pair{
+		{Key: "limit-at", Value: entry.LimitAt},
+		{Key: "max-limit", Value: entry.MaxLimit},
+		{Key: "queue", Value: entry.Queue},
+		{Key: "priority", Value: priority},
+	}
+
+	return optionalQueueParams(params, optional, clearEmpty), nil
+}
+
+// RequestQueueTree returns all /queue/tree entries as a tree. Entries are
+// nested under the entry named by their parent attribute. The roots are the
+// entries attached to an interface or to global, plus the entries whose
+// parent does not exist. Entries keep the router's order.
+func (s *service) RequestQueueTree(ctx context.Context) ([]*models.QueueTree, error) {
+	res, err := s.queryRouter(ctx, "/queue/tree/print")
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		entries = make([]*models.QueueTree, 0, len(res.SubPairs))
+		byName  = make(map[string]*models.QueueTree, len(res.SubPairs))
+		roots   = []*models.QueueTree{}
+	)
+
+	for _, pair := range res.SubPairs {
+		entry := newQueueTree(pair)
+		entries = append(entries, entry)
+		byName[entry.Name] = entry
+	}
+
+	for _, entry := range entries {
+		if parent, ok := byName[entry.Parent]; ok && parent != entry {
+			parent.Children = append(parent.Children, entry)
+		} else {
+			roots = append(roots, entry)
+		}
+	}
+
+	return roots, nil
+}
+
+// RequestQueueTreeEntry searches for a /queue/tree entry by its ID. The
+// entry's children are not included.
+// Returns *models.QueueTree instance if it finds it, or nil otherwise.
+func (s *service) RequestQueueTreeEntry(ctx context.Context, id string) (*models.QueueTree, error) {
+	res, err := s.findRouter(ctx, "/queue/tree/print", routeros.Query{
+		Pairs: []routeros.Pair{
+			{Key: ".id", Value: id},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.SubPairs) == 0 {
+		return nil, nil
+	}
+
+	return newQueueTree(res.SubPairs[0]), nil
+}
+
+// CreateQueueTreeEntry adds a new /queue/tree entry and returns it.
+func (s *service) CreateQueueTreeEntry(ctx context.Context, entry *models.QueueTree) (*models.QueueTree, error) {
+	params, err := queueTreeParams(entry, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Disabled {
+		params = append(params, routeros.Pair{Key: "disabled", Value: "yes"})
+	}
+
+	res, err := s.callRouter(ctx, "/queue/tree/add", params...)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.GetPairVal("ret")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.RequestQueueTreeEntry(ctx, id)
+}
+
+// UpdateQueueTreeEntry replaces the fields of a /queue/tree entry.
+func (s *service) UpdateQueueTreeEntry(ctx context.Context, id string, entry *models.QueueTree) (*models.QueueTree, error) {
+	params, err := queueTreeParams(entry, true)
+	if err != nil {
+		return nil, err
+	}
+
+	disabled := "no"
+	if entry.Disabled {
+		disabled = "yes"
+	}
+
+	params = append([]routeros.Pair{{Key: ".id", Value: id}}, params...)
+	params = append(params, routeros.Pair{Key: "disabled", Value: disabled})
+
+	if _, err = s.callRouter(ctx, "/queue/tree/set", params...); err != nil {
+		return nil, err
+	}
+
+	return s.RequestQueueTreeEntry(ctx, id)
+}
+
+// RemoveQueueTreeEntry deletes a /queue/tree entry.
+func (s *service) RemoveQueueTreeEntry(ctx context.Context, id string) error {
+	_, err := s.callRouter(ctx, "/queue/tree/remove", routeros.Pair{Key: ".id", Value: id})
+	return err
+}
@@ -0,0 +1,114 @@
+package mikrotik
+
+import (
+	"context"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	routeros "github.com/jda/routeros-api-go"
+)
+
+// requestSuspendedAddresses returns the entries of the suspended clients'
+// address list mapped by address to the entry's ID.
+func (s *service) requestSuspendedAddresses(ctx context.Context) (map[string]string, error) {
+	res, err := s.findRouter(ctx, "/ip/firewall/address-list/print", routeros.Query{
+		Pairs: []routeros.Pair{
+			{Key: "list", Value: s.cfg.PrivateRouter.SuspendedAddressList},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make(map[string]string, len(res.SubPairs))
+
+	for _, pair := range res.SubPairs {
+		addresses[pair["address"]] = pair[".id"]
+	}
+
+	return addresses, nil
+}
+
+// markSuspended sets the Suspended flag of the clients that have any of
+// their target addresses in the suspended clients' address list.
+func markSuspended(clients []models.Client, suspended map[string]string) {
+	for i := range clients {
+		for _, address := range clients[i].TargetAddresses() {
+			if _, ok := suspended[address]; ok {
+				clients[i].Suspended = true
+				break
+			}
+		}
+	}
+}
+
+// SuspendClient adds all of the client's target addresses to the suspended
+// clients' address list. The comment is saved on each address list entry.
+// Addresses that are already in the list are left untouched. Returns
+// services.ErrRecordNotFound if the client does not exist.
+func (s *service) SuspendClient(ctx context.Context, id, comment string) (*models.Client, error) {
+	client, err := s.RequestClient(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if client == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	suspended, err := s.requestSuspendedAddresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, address := range client.TargetAddresses() {
+		if _, ok := suspended[address]; ok {
+			continue
+		}
+
+		_, err = s.callRouter(ctx, "/ip/firewall/address-list/add",
+			routeros.Pair{Key: "list", Value: s.cfg.PrivateRouter.SuspendedAddressList},
+			routeros.Pair{Key: "address", Value: address},
+			routeros.Pair{Key: "comment", Value: comment},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	client.Suspended = true
+	return client, nil
+}
+
+// RestoreClient removes all of the client's target addresses from the
+// suspended clients' address list. Returns services.ErrRecordNotFound if
+// the client does not exist.
+func (s *service) RestoreClient(ctx context.Context, id string) (*models.Client, error) {
+	client, err := s.RequestClient(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if client == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	suspended, err := s.requestSuspendedAddresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, address := range client.TargetAddresses() {
+		entryID, ok := suspended[address]
+
+		if !ok {
+			continue
+		}
+
+		_, err = s.callRouter(ctx, "/ip/firewall/address-list/remove",
+			routeros.Pair{Key: ".id", Value: entryID},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	client.Suspended = false
+	return client, nil
+}
@@ -0,0 +1,162 @@
+package mikrotik
+
+import (
+	"context"
+	"time"
+
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	routeros "github.com/jda/routeros-api-go"
+)
+
+// countEntries returns the number of entries of a menu using the print
+// command's count-only attribute.
+func (s *service) countEntries(ctx context.Context, menu string) (int64, error) {
+	res, err := s.callRouter(ctx, menu+"/print", routeros.Pair{Key: "count-only", Value: ""})
+	if err != nil {
+		return 0, err
+	}
+
+	ret, err := res.GetPairVal("ret")
+	if err != nil {
+		return 0, err
+	}
+
+	return parseInt(ret), nil
+}
+
+// requestHealth reads /system/health. RouterOS v6 replies with a single
+// entry that has one attribute per sensor, while v7 replies with one entry
+// per sensor with its name, value and type. Both formats are returned as a
+// map of sensor name to value.
+func (s *service) requestHealth(ctx context.Context) (map[string]string, error) {
+	res, err := s.queryRouter(ctx, "/system/health/print")
+	if err != nil {
+		return nil, err
+	}
+
+	health := map[string]string{}
+
+	for _, pair := range res.SubPairs {
+		if name, ok := pair["name"]; ok {
+			health[name] = pair["value"] + pair["type"]
+			continue
+		}
+
+		for k, v := range pair {
+			if k != ".id" {
+				health[k] = v
+			}
+		}
+	}
+
+	return health, nil
+}
+
+// requestRouterBoard reads /system/routerboard. Returns nil if the device is
+// not a RouterBOARD, e.g. a CHR or x86 installation.
+func (s *service) requestRouterBoard(ctx context.Context) (*models.RouterBoard, error) {
+	res, err := s.queryRouter(ctx, "/system/routerboard/print")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.SubPairs) == 0 || !parseBool(res.SubPairs[0]["routerboard"]) {
+		return nil, nil
+	}
+
+	pair := res.SubPairs[0]
+
+	return &models.RouterBoard{
+		Model:           pair["model"],
+		SerialNumber:    pair["serial-number"],
+		FirmwareType:    pair["firmware-type"],
+		CurrentFirmware: pair["current-firmware"],
+		UpgradeFirmware: pair["upgrade-firmware"],
+	}, nil
+}
+
+// RequestRouterStatus gathers the router's status document. The document is
+// cached for the configured status cache time, so repeated page loads don't
+// query the router every time.
+func (s *service) RequestRouterStatus(ctx context.Context) (*models.RouterStatus, error) {
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+
+	ttl := time.Duration(s.cfg.PrivateRouter.StatusCacheSeconds) * time.Second
+	if s.status != nil && time.Since(s.status.CheckedAt) < ttl {
+		return s.status, nil
+	}
+
+	status, err := s.requestRouterStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.status = status
+	return status, nil
+}
+
+func (s *service) requestRouterStatus(ctx context.Context) (*models.RouterStatus, error) {
+	var (
+		log    = logger.FromContext(ctx, s.log)
+		status = &models.RouterStatus{
+			Address:   s.cfg.PrivateRouter.Address,
+			CheckedAt: time.Now(),
+		}
+	)
+
+	res, err := s.queryRouter(ctx, "/system/resource/print")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.SubPairs) > 0 {
+		pair := res.SubPairs[0]
+
+		status.Version = pair["version"]
+		status.BoardName = pair["board-name"]
+		status.Architecture = pair["architecture-name"]
+		status.Uptime = pair["uptime"]
+		status.CPU = pair["cpu"]
+		status.CPUCount = parseInt(pair["cpu-count"])
+		status.CPULoad = parseInt(pair["cpu-load"])
+		status.FreeMemory = parseInt(pair["free-memory"])
+		status.TotalMemory = parseInt(pair["total-memory"])
+		status.FreeHDD = parseInt(pair["free-hdd-space"])
+		status.TotalHDD = parseInt(pair["total-hdd-space"])
+	}
+
+	res, err = s.queryRouter(ctx, "/system/identity/print")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.SubPairs) > 0 {
+		status.Identity = res.SubPairs[0]["name"]
+	}
+
+	// Health and RouterBOARD menus don't exist on every device, so their
+	// errors are only logged.
+	if status.Health, err = s.requestHealth(ctx); err != nil {
+		log.Debug("mikrotik: could not read system health", "error", err)
+	}
+
+	if status.RouterBoard, err = s.requestRouterBoard(ctx); err != nil {
+		log.Debug("mikrotik: could not read routerboard", "error", err)
+	}
+
+	if status.QueueCount, err = s.countEntries(ctx, "/queue/simple"); err != nil {
+		return nil, err
+	}
+
+	if status.LeaseCount, err = s.countEntries(ctx, "/ip/dhcp-server/lease"); err != nil {
+		return nil, err
+	}
+
+	if status.PPPCount, err = s.countEntries(ctx, "/ppp/active"); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
@@ -0,0 +1,123 @@
+package mikrotik
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	routeros "github.com/jda/routeros-api-go"
+)
+
+// parseLeadingInt parses the number at the start of the signal and CCQ
+// values returned by RouterOS, e.g. "-65dBm@6Mbps" is parsed as -65. Invalid
+// values are returned as 0.
+func parseLeadingInt(v string) int {
+	end := 0
+
+	for end < len(v) && ((end == 0 && v[end] == '-') || (v[end] >= '0' && v[end] <= '9')) {
+		end++
+	}
+
+	n, _ := strconv.Atoi(v[:end])
+	return n
+}
+
+// newWirelessRegistration creates a models.WirelessRegistration from a
+// /interface/wireless/registration-table reply.
+func newWirelessRegistration(pair map[string]string) models.WirelessRegistration {
+	return models.WirelessRegistration{
+		ID:             pair[".id"],
+		Interface:      pair["interface"],
+		MACAddress:     pair["mac-address"],
+		RadioName:      pair["radio-name"],
+		LastIP:         pair["last-ip"],
+		SignalStrength: parseLeadingInt(pair["signal-strength"]),
+		SignalToNoise:  parseLeadingInt(pair["signal-to-noise"]),
+		TxCCQ:          parseLeadingInt(pair["tx-ccq"]),
+		RxCCQ:          parseLeadingInt(pair["rx-ccq"]),
+		TxRate:         pair["tx-rate"],
+		RxRate:         pair["rx-rate"],
+		Uptime:         pair["uptime"],
+	}
+}
+
+// matchRegistrations sets the client of each registration. Registrations
+// are matched by their last IP against the clients' target addresses and,
+// if that fails, by their MAC address against the MAC address of the
+// clients' DHCP leases.
+func matchRegistrations(registrations []models.WirelessRegistration, clients []models.Client) {
+	var (
+		byAddress = map[string]*models.Client{}
+		byMAC     = map[string]*models.Client{}
+	)
+
+	for i := range clients {
+		for _, address := range clients[i].TargetAddresses() {
+			byAddress[address] = &clients[i]
+		}
+
+		if clients[i].MACAddress != "" {
+			byMAC[strings.ToUpper(clients[i].MACAddress)] = &clients[i]
+		}
+	}
+
+	for i := range registrations {
+		client, ok := byAddress[registrations[i].LastIP]
+
+		if !ok {
+			client, ok = byMAC[strings.ToUpper(registrations[i].MACAddress)]
+		}
+
+		if ok {
+			registrations[i].ClientID = client.ID
+			registrations[i].ClientName = client.Name
+		}
+	}
+}
+
+// RequestWirelessRegistrations returns the stations connected to the
+// router's wireless interfaces, matched to their clients.
+func (s *service) RequestWirelessRegistrations(ctx context.Context) ([]models.WirelessRegistration, error) {
+	res, err := s.queryRouter(ctx, "/interface/wireless/registration-table/print")
+	if err != nil {
+		return nil, err
+	}
+
+	registrations := make([]models.WirelessRegistration, 0, len(res.SubPairs))
+
+	for _, pair := range res.SubPairs {
+		registrations = append(registrations, newWirelessRegistration(pair))
+	}
+
+	clients, err := s.RequestClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matchRegistrations(registrations, clients)
+	return registrations, nil
+}
+
+// MonitorWirelessStation returns the current registration of the station
+// with the MAC address. The registration is not matched to a client, so it
+// can be polled cheaply. Returns ErrRecordNotFound if the station is not
+// connected.
+func (s *service) MonitorWirelessStation(ctx context.Context, mac string) (*models.WirelessRegistration, error) {
+	res, err := s.findRouter(ctx, "/interface/wireless/registration-table/print", routeros.Query{
+		Pairs: []routeros.Pair{
+			{Key: "mac-address", Value: strings.ToUpper(mac)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.SubPairs) == 0 {
+		return nil, services.ErrRecordNotFound
+	}
+
+	registration := newWirelessRegistration(res.SubPairs[0])
+	return &registration, nil
+}
@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/jinzhu/gorm"
+)
+
+// Availability summarizes the client's probes and returns the outages that
+// overlap the period since the date. The status is taken from the client's
+// last probe.
+func (s *service) Availability(clientID string, since time.Time) (*models.ClientAvailability, error) {
+	var (
+		total, up int
+		latency   sql.NullFloat64
+		last      models.ClientProbe
+		result    = &models.ClientAvailability{
+			ClientID: clientID,
+			Since:    since,
+			Status:   StatusUnknown,
+			Outages:  []models.ClientOutage{},
+		}
+	)
+
+	err := s.db.Raw(`
+		SELECT count(*), count(CASE WHEN up THEN 1 END), avg(latency_ms)
+		FROM client_probes
+		WHERE client_id = ? AND created_at >= ?`, clientID, since).
+		Row().
+		Scan(&total, &up, &latency)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Probes = total
+
+	if total > 0 {
+		availability := float64(up) * 100 / float64(total)
+		result.Availability = &availability
+	}
+
+	if latency.Valid {
+		result.AvgLatencyMs = &latency.Float64
+	}
+
+	err = s.db.
+		Where("client_id = ?", clientID).
+		Order("created_at DESC").
+		First(&last).Error
+
+	if err == nil {
+		result.LastProbeAt = &last.CreatedAt
+		result.Status = StatusDown
+
+		if last.Up {
+			result.Status = StatusUp
+		}
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	err = s.db.
+		Where("client_id = ? AND (ended_at IS NULL OR ended_at >= ?)", clientID, since).
+		Order("started_at DESC").
+		Find(&result.Outages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Probes returns the client's probes since the date, oldest first.
+func (s *service) Probes(clientID string, since time.Time) ([]models.ClientProbe, error) {
+	probes := []models.ClientProbe{}
+
+	err := s.db.
+		Where("client_id = ? AND created_at >= ?", clientID, since).
+		Order("created_at").
+		Find(&probes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return probes, nil
+}
+
+// OngoingOutages returns the outages of the clients that are currently down.
+func (s *service) OngoingOutages() ([]models.ClientOutage, error) {
+	outages := []models.ClientOutage{}
+
+	err := s.db.
+		Where("ended_at IS NULL").
+		Order("started_at").
+		Find(&outages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return outages, nil
+}
@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/jinzhu/gorm"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	Availability(clientID string, since time.Time) (*models.ClientAvailability, error)
+	Probes(clientID string, since time.Time) ([]models.ClientProbe, error)
+	OngoingOutages() ([]models.ClientOutage, error)
+}
+
+// Client statuses reported by Availability.
+const (
+	StatusUp      = "up"
+	StatusDown    = "down"
+	StatusUnknown = "unknown"
+)
+
+// service probes the clients' addresses in the background and keeps their
+// availability history.
+type service struct {
+	cfg             *config.Config
+	db              *gorm.DB
+	log             logger.Logger
+	mikrotikService mikrotik.Service
+
+	// down contains the clients with an ongoing outage and the outage's ID.
+	// It's only used by the probing goroutine.
+	down map[string]int
+
+	// next is the position of the client that the next round probes first.
+	// Rounds that time out leave it at the first client they skipped, so
+	// every client gets probed even if a round can't probe all of them.
+	next int
+}
+
+// NewService initialization. If monitoring is enabled, a goroutine that
+// probes every client each Monitor.IntervalSeconds is started.
+func NewService(cfg *config.Config, db *gorm.DB, log logger.Logger, mikrotikService mikrotik.Service) Service {
+	s := &service{
+		cfg:             cfg,
+		db:              db,
+		log:             log,
+		mikrotikService: mikrotikService,
+		down:            map[string]int{},
+	}
+
+	if cfg.Monitor.IntervalSeconds > 0 {
+		go s.run()
+	}
+
+	return s
+}
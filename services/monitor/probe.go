@@ -0,0 +1,213 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+)
+
+// run probes every client each Monitor.IntervalSeconds. The outages that
+// were ongoing when the server stopped are loaded first, so they are closed
+// when the clients come back up.
+func (s *service) run() {
+	interval := time.Duration(s.cfg.Monitor.IntervalSeconds) * time.Second
+
+	if err := s.loadOutages(); err != nil {
+		s.log.Error("monitor: could not load ongoing outages", "error", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		s.probeAll(ctx)
+		cancel()
+
+		if err := s.prune(); err != nil {
+			s.log.Error("monitor: could not delete old probes", "error", err)
+		}
+	}
+}
+
+// loadOutages reads the ongoing outages from the database.
+func (s *service) loadOutages() error {
+	outages, err := s.OngoingOutages()
+	if err != nil {
+		return err
+	}
+
+	for _, outage := range outages {
+		s.down[outage.ClientID] = outage.ID
+	}
+
+	return nil
+}
+
+// probeAddress returns the client's first single host target address. Clients
+// whose queue only targets subnets are not probed.
+func probeAddress(client models.Client) string {
+	for _, address := range client.TargetAddresses() {
+		if !strings.Contains(address, "/") {
+			return address
+		}
+	}
+
+	return ""
+}
+
+// probeAll pings every client's address from the router and records the
+// results. The outages of clients that no longer exist are closed. Clients
+// that could not be pinged keep their current state. The round starts where
+// the last one that timed out stopped.
+func (s *service) probeAll(ctx context.Context) {
+	var (
+		start  = time.Now()
+		exists = map[string]bool{}
+		probed = 0
+		down   = 0
+	)
+
+	clients, err := s.mikrotikService.RequestClients(ctx)
+	if err != nil {
+		s.log.Error("monitor: could not request clients", "error", err)
+		return
+	}
+
+	for _, client := range clients {
+		exists[client.ID] = true
+	}
+
+	if len(clients) > 0 {
+		s.next %= len(clients)
+	}
+
+	for i := range clients {
+		var (
+			position = (s.next + i) % len(clients)
+			client   = clients[position]
+			address  = probeAddress(client)
+		)
+
+		if address == "" {
+			continue
+		}
+
+		result, err := s.mikrotikService.Ping(ctx, address, s.cfg.Monitor.PingCount)
+		if err != nil {
+			if ctx.Err() != nil {
+				s.next = position
+				s.log.Warn(
+					"monitor: probe round timed out",
+					"probed", probed,
+					"skipped", len(clients)-i,
+					"clients", len(clients),
+				)
+
+				return
+			}
+
+			s.log.Warn("monitor: could not ping client", "client_id", client.ID, "address", address, "error", err)
+			continue
+		}
+
+		probed++
+
+		if err = s.record(client, result); err != nil {
+			s.log.Error("monitor: could not record probe", "client_id", client.ID, "error", err)
+		}
+
+		if result.Received == 0 {
+			down++
+		}
+	}
+
+	for clientID, outageID := range s.down {
+		if !exists[clientID] {
+			if err = s.closeOutage(clientID, outageID); err != nil {
+				s.log.Error("monitor: could not close outage", "client_id", clientID, "error", err)
+			}
+		}
+	}
+
+	s.log.Info("monitor: clients probed", "probed", probed, "down", down, "duration", time.Since(start))
+}
+
+// record stores the probe and opens or closes the client's outage if the
+// client went down or came back up.
+func (s *service) record(client models.Client, result *models.PingResult) error {
+	probe := &models.ClientProbe{
+		ClientID:   client.ID,
+		Address:    result.Address,
+		Up:         result.Received > 0,
+		PacketLoss: result.PacketLoss,
+	}
+
+	if probe.Up {
+		latency := result.AvgRTT
+		probe.LatencyMs = &latency
+	}
+
+	if err := s.db.Create(probe).Error; err != nil {
+		return err
+	}
+
+	outageID, isDown := s.down[client.ID]
+
+	switch {
+	case !probe.Up && !isDown:
+		outage := &models.ClientOutage{
+			ClientID:  client.ID,
+			Address:   probe.Address,
+			StartedAt: probe.CreatedAt,
+		}
+
+		if err := s.db.Create(outage).Error; err != nil {
+			return err
+		}
+
+		s.down[client.ID] = outage.ID
+		s.log.Warn("monitor: client down", "client_id", client.ID, "client_name", client.Name, "address", probe.Address)
+
+	case probe.Up && isDown:
+		if err := s.closeOutage(client.ID, outageID); err != nil {
+			return err
+		}
+
+		s.log.Info("monitor: client up", "client_id", client.ID, "client_name", client.Name, "address", probe.Address)
+	}
+
+	return nil
+}
+
+// closeOutage sets the end of the client's ongoing outage.
+func (s *service) closeOutage(clientID string, outageID int) error {
+	err := s.db.
+		Model(&models.ClientOutage{ID: outageID}).
+		UpdateColumn("ended_at", time.Now()).Error
+	if err != nil {
+		return err
+	}
+
+	delete(s.down, clientID)
+	return nil
+}
+
+// prune deletes the probes and finished outages older than the configured
+// retention.
+func (s *service) prune() error {
+	cutoff := time.Now().AddDate(0, 0, -s.cfg.Monitor.RetentionDays)
+
+	err := s.db.
+		Where("created_at < ?", cutoff).
+		Delete(&models.ClientProbe{}).Error
+	if err != nil {
+		return err
+	}
+
+	return s.db.
+		Where("ended_at < ?", cutoff).
+		Delete(&models.ClientOutage{}).Error
+}
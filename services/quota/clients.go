@@ -0,0 +1,286 @@
+package quota
+
+import (
+	"context"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/jinzhu/gorm"
+)
+
+// newStatus returns the client's consumption in the cycle that contains
+// now.
+func newStatus(assignment models.ClientQuota, quota models.Quota, used int64, now time.Time) models.QuotaStatus {
+	status := models.QuotaStatus{
+		ClientQuota:    assignment,
+		Quota:          quota,
+		UsedBytes:      used,
+		RemainingBytes: max(quota.LimitBytes-used, 0),
+	}
+
+	status.CycleStart = cycleStart(now, quota.CycleDay)
+	status.CycleEnd = status.CycleStart.AddDate(0, 1, 0)
+
+	return status
+}
+
+// findAssignment searches for the quota assigned to a client, or returns
+// nil.
+func (s *service) findAssignment(clientID string) (*models.ClientQuota, error) {
+	assignment := &models.ClientQuota{}
+
+	err := s.db.
+		Where("client_id = ?", clientID).
+		First(assignment).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return assignment, nil
+}
+
+// quotasByID returns all quotas by ID.
+func (s *service) quotasByID() (map[int]models.Quota, error) {
+	quotas, err := s.ListQuotas()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]models.Quota, len(quotas))
+	for _, quota := range quotas {
+		byID[quota.ID] = quota
+	}
+
+	return byID, nil
+}
+
+// ListStatuses returns the consumption of every client with a quota.
+func (s *service) ListStatuses() ([]models.QuotaStatus, error) {
+	var (
+		now         = time.Now()
+		assignments = []models.ClientQuota{}
+		totals      = map[time.Time]map[string]int64{}
+	)
+
+	quotas, err := s.quotasByID()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.
+		Order("client_id").
+		Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]models.QuotaStatus, 0, len(assignments))
+
+	for _, assignment := range assignments {
+		quota := quotas[assignment.QuotaID]
+		start := cycleStart(now, quota.CycleDay)
+
+		if _, ok := totals[start]; !ok {
+			if totals[start], err = s.trafficService.Totals(start); err != nil {
+				return nil, err
+			}
+		}
+
+		statuses = append(statuses, newStatus(assignment, quota, totals[start][assignment.ClientID], now))
+	}
+
+	return statuses, nil
+}
+
+// Status returns the client's consumption in its current cycle, or nil if
+// the client has no quota.
+func (s *service) Status(clientID string) (*models.QuotaStatus, error) {
+	assignment, err := s.findAssignment(clientID)
+	if err != nil || assignment == nil {
+		return nil, err
+	}
+
+	return s.status(assignment)
+}
+
+// status returns the consumption of the assignment's client.
+func (s *service) status(assignment *models.ClientQuota) (*models.QuotaStatus, error) {
+	now := time.Now()
+
+	quota, err := s.FindQuota(assignment.QuotaID)
+	if err != nil {
+		return nil, err
+	} else if quota == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	usage, err := s.trafficService.Usage(assignment.ClientID, cycleStart(now, quota.CycleDay), now, "")
+	if err != nil {
+		return nil, err
+	}
+
+	status := newStatus(*assignment, *quota, usage.TotalBytes, now)
+	return &status, nil
+}
+
+// Assign sets the client's quota. The client is throttled or restored right
+// away according to its consumption in the current cycle.
+func (s *service) Assign(ctx context.Context, clientID string, quotaID int) (*models.QuotaStatus, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	quota, err := s.FindQuota(quotaID)
+	if err != nil {
+		return nil, err
+	} else if quota == nil {
+		return nil, services.ErrInvalidArgument("quota does not exist")
+	}
+
+	assignment, err := s.findAssignment(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment == nil {
+		assignment = &models.ClientQuota{
+			ClientID:   clientID,
+			QuotaID:    quota.ID,
+			CycleStart: cycleStart(now, quota.CycleDay),
+		}
+
+		if err = s.db.Create(assignment).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	assignment.QuotaID = quota.ID
+
+	usage, err := s.trafficService.Usage(clientID, cycleStart(now, quota.CycleDay), now, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.apply(ctx, assignment, *quota, usage.TotalBytes, now); err != nil {
+		return nil, err
+	}
+
+	if err = s.db.Save(assignment).Error; err != nil {
+		return nil, err
+	}
+
+	status := newStatus(*assignment, *quota, usage.TotalBytes, now)
+	return &status, nil
+}
+
+// Unassign removes the client's quota and restores its limits if it was
+// throttled. Returns ErrRecordNotFound if the client has no quota.
+func (s *service) Unassign(ctx context.Context, clientID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	assignment, err := s.findAssignment(clientID)
+	if err != nil {
+		return err
+	} else if assignment == nil {
+		return services.ErrRecordNotFound
+	}
+
+	if assignment.Throttled {
+		if err = s.restore(ctx, assignment, "its quota was removed"); err != nil {
+			return err
+		}
+	}
+
+	return s.db.
+		Where("client_id = ?", clientID).
+		Delete(&models.ClientQuota{}).Error
+}
+
+// Override stops throttling the client until the end of its current cycle
+// and restores its limits right away. Returns ErrRecordNotFound if the
+// client has no quota.
+func (s *service) Override(ctx context.Context, clientID string, operatorID *int) (*models.QuotaStatus, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	assignment, err := s.findAssignment(clientID)
+	if err != nil {
+		return nil, err
+	} else if assignment == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	quota, err := s.FindQuota(assignment.QuotaID)
+	if err != nil {
+		return nil, err
+	} else if quota == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	if assignment.Throttled {
+		if err = s.restore(ctx, assignment, "an administrator lifted its cap"); err != nil {
+			return nil, err
+		}
+	}
+
+	until := cycleStart(time.Now(), quota.CycleDay).AddDate(0, 1, 0)
+	assignment.OverrideUntil = &until
+	assignment.OverrideBy = operatorID
+
+	if err = s.db.Save(assignment).Error; err != nil {
+		return nil, err
+	}
+
+	s.log.Info("quota: override set", "client_id", clientID, "until", until, "operator_id", operatorID)
+	return s.status(assignment)
+}
+
+// RemoveOverride removes the client's override. The client is throttled
+// again on the next check if it's over its cap. Returns ErrRecordNotFound if
+// the client has no quota.
+func (s *service) RemoveOverride(clientID string) (*models.QuotaStatus, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	assignment, err := s.findAssignment(clientID)
+	if err != nil {
+		return nil, err
+	} else if assignment == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	assignment.OverrideUntil = nil
+	assignment.OverrideBy = nil
+
+	if err = s.db.Save(assignment).Error; err != nil {
+		return nil, err
+	}
+
+	return s.status(assignment)
+}
+
+// ThrottledClients returns the IDs of the clients that are throttled.
+func (s *service) ThrottledClients() (map[string]bool, error) {
+	assignments := []models.ClientQuota{}
+
+	err := s.db.
+		Where("throttled = ?", true).
+		Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	throttled := make(map[string]bool, len(assignments))
+	for _, assignment := range assignments {
+		throttled[assignment.ClientID] = true
+	}
+
+	return throttled, nil
+}
@@ -0,0 +1,187 @@
+package quota
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/jinzhu/gorm"
+)
+
+// maxCycleDay is the last day a cycle can start on, so that every month has
+// it.
+const maxCycleDay = 28
+
+// cycleStart returns the start of the cycle that contains t. Cycles start at
+// midnight of the day of the month.
+func cycleStart(t time.Time, day int) time.Time {
+	y, m, d := t.Date()
+
+	if d < day {
+		m--
+	}
+
+	return time.Date(y, m, day, 0, 0, 0, 0, t.Location())
+}
+
+// validQuota checks the quota's fields. A cycle day of 0 is set to 1.
+func validQuota(quota *models.Quota) error {
+	quota.Name = strings.TrimSpace(quota.Name)
+	if quota.Name == "" {
+		return services.ErrInvalidArgument("quota name is required")
+	}
+
+	if quota.LimitBytes <= 0 {
+		return services.ErrInvalidArgument("limitBytes must be positive")
+	}
+
+	if !mikrotik.IsValidRateLimit(quota.ThrottleLimit) {
+		return services.ErrInvalidArgument("throttleLimit must have the form upload/download, e.g. 512k/1M")
+	}
+
+	if quota.CycleDay == 0 {
+		quota.CycleDay = 1
+	}
+
+	if quota.CycleDay < 1 || quota.CycleDay > maxCycleDay {
+		return services.ErrInvalidArgument(fmt.Sprintf("cycleDay must be between 1 and %d", maxCycleDay))
+	}
+
+	return nil
+}
+
+// ListQuotas returns all quotas.
+func (s *service) ListQuotas() ([]models.Quota, error) {
+	quotas := []models.Quota{}
+
+	err := s.db.
+		Order("name").
+		Find(&quotas).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return quotas, nil
+}
+
+// FindQuota searches for a quota by ID.
+// Returns *models.Quota instance if it finds it, or nil otherwise.
+func (s *service) FindQuota(id int) (*models.Quota, error) {
+	quota := &models.Quota{}
+
+	err := s.db.
+		Where("id = ?", id).
+		First(quota).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return quota, nil
+}
+
+// findQuotaByName searches for a quota by name, or returns nil.
+func (s *service) findQuotaByName(name string) (*models.Quota, error) {
+	quota := &models.Quota{}
+
+	err := s.db.
+		Where("name = ?", name).
+		First(quota).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return quota, nil
+}
+
+// CreateQuota adds a new quota. Quota names must be unique.
+func (s *service) CreateQuota(quota *models.Quota) (*models.Quota, error) {
+	if err := validQuota(quota); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findQuotaByName(quota.Name)
+	if err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, services.ErrInvalidArgument("quota name is already in use")
+	}
+
+	created := &models.Quota{
+		Name:          quota.Name,
+		LimitBytes:    quota.LimitBytes,
+		ThrottleLimit: quota.ThrottleLimit,
+		CycleDay:      quota.CycleDay,
+	}
+
+	if err = s.db.Create(created).Error; err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// UpdateQuota replaces the fields of a quota. The clients' throttling is
+// updated on the next check. Returns ErrRecordNotFound if the quota does
+// not exist.
+func (s *service) UpdateQuota(id int, quota *models.Quota) (*models.Quota, error) {
+	if err := validQuota(quota); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findQuotaByName(quota.Name)
+	if err != nil {
+		return nil, err
+	} else if existing != nil && existing.ID != id {
+		return nil, services.ErrInvalidArgument("quota name is already in use")
+	}
+
+	updated, err := s.FindQuota(id)
+	if err != nil {
+		return nil, err
+	} else if updated == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	updated.Name = quota.Name
+	updated.LimitBytes = quota.LimitBytes
+	updated.ThrottleLimit = quota.ThrottleLimit
+	updated.CycleDay = quota.CycleDay
+
+	if err = s.db.Save(updated).Error; err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// RemoveQuota deletes a quota that is not assigned to any client.
+func (s *service) RemoveQuota(id int) error {
+	var assigned int
+
+	err := s.db.
+		Model(&models.ClientQuota{}).
+		Where("quota_id = ?", id).
+		Count(&assigned).Error
+	if err != nil {
+		return err
+	}
+
+	if assigned > 0 {
+		return services.ErrInvalidArgument(fmt.Sprintf("quota is assigned to %d clients", assigned))
+	}
+
+	return s.db.
+		Where("id = ?", id).
+		Delete(&models.Quota{}).Error
+}
@@ -0,0 +1,232 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/alert"
+)
+
+// unlimited is the value of max-limit and burst-limit that removes the
+// limit.
+const unlimited = "0/0"
+
+// run checks the clients' consumption every Quota.IntervalSeconds.
+func (s *service) run() {
+	interval := time.Duration(s.cfg.Quota.IntervalSeconds) * time.Second
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		err := s.enforce(ctx, time.Now())
+		cancel()
+
+		if err != nil {
+			s.log.Error("quota: could not check the clients' quotas", "error", err)
+		}
+	}
+}
+
+// enforce throttles the clients that reached their cap and restores the
+// ones that are no longer over it, e.g. because a new cycle started. A
+// failure with one client doesn't stop the others.
+func (s *service) enforce(ctx context.Context, now time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var (
+		assignments = []models.ClientQuota{}
+		totals      = map[time.Time]map[string]int64{}
+	)
+
+	quotas, err := s.quotasByID()
+	if err != nil {
+		return err
+	}
+
+	if err = s.db.Find(&assignments).Error; err != nil {
+		return err
+	}
+
+	for i := range assignments {
+		assignment := &assignments[i]
+
+		quota, ok := quotas[assignment.QuotaID]
+		if !ok {
+			continue
+		}
+
+		start := cycleStart(now, quota.CycleDay)
+
+		if _, ok = totals[start]; !ok {
+			if totals[start], err = s.trafficService.Totals(start); err != nil {
+				return err
+			}
+		}
+
+		throttled := assignment.Throttled
+		cycle := assignment.CycleStart
+
+		err = s.apply(ctx, assignment, quota, totals[start][assignment.ClientID], now)
+		if err != nil {
+			s.log.Error("quota: could not update client", "client_id", assignment.ClientID, "error", err)
+			continue
+		}
+
+		if throttled == assignment.Throttled && cycle.Equal(assignment.CycleStart) {
+			continue
+		}
+
+		if err = s.db.Save(assignment).Error; err != nil {
+			s.log.Error("quota: could not save client state", "client_id", assignment.ClientID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// apply starts the client's new cycle if the current one ended, and
+// throttles or restores the client according to the bytes used in the
+// cycle. The assignment is updated but not saved.
+func (s *service) apply(ctx context.Context, assignment *models.ClientQuota, quota models.Quota, used int64, now time.Time) error {
+	start := cycleStart(now, quota.CycleDay)
+	newCycle := !assignment.CycleStart.Equal(start)
+
+	if newCycle {
+		assignment.OverrideUntil = nil
+		assignment.OverrideBy = nil
+	}
+
+	var (
+		overridden = assignment.OverrideUntil != nil && now.Before(*assignment.OverrideUntil)
+		exceeded   = used >= quota.LimitBytes && !overridden
+		err        error
+	)
+
+	switch {
+	case exceeded && !assignment.Throttled:
+		err = s.throttle(ctx, assignment, quota, used, now)
+
+	case !exceeded && assignment.Throttled:
+		reason := "it's no longer over its cap"
+		if newCycle {
+			reason = "a new cycle started"
+		}
+
+		err = s.restore(ctx, assignment, reason)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	assignment.CycleStart = start
+	return nil
+}
+
+// formatGB formats a number of bytes in gigabytes.
+func formatGB(n int64) string {
+	return fmt.Sprintf("%.2f GB", float64(n)/(1<<30))
+}
+
+// throttle lowers the max-limit of the client's queue to the quota's
+// throttle limit and disables bursting. The queue's limits are kept in the
+// assignment so they can be restored.
+func (s *service) throttle(ctx context.Context, assignment *models.ClientQuota, quota models.Quota, used int64, now time.Time) error {
+	client, err := s.mikrotikService.RequestClient(ctx, assignment.ClientID)
+	if err != nil {
+		return err
+	} else if client == nil {
+		return fmt.Errorf("quota: client [%s] no longer exists", assignment.ClientID)
+	}
+
+	err = s.mikrotikService.SetClientLimits(ctx, client.ID, models.QueueLimits{
+		MaxLimit:   quota.ThrottleLimit,
+		BurstLimit: unlimited,
+	})
+	if err != nil {
+		return err
+	}
+
+	assignment.Throttled = true
+	assignment.ThrottledAt = &now
+	assignment.OriginalMaxLimit = client.MaxLimit
+	assignment.OriginalBurstLimit = client.BurstLimit
+
+	s.log.Info("quota: client throttled", "client_id", client.ID, "quota", quota.Name, "used_bytes", used)
+
+	alert.Notify(ctx, s.log, s.notifiers, models.Alert{
+		Key:     RuleQuotaExceeded + ":" + client.ID,
+		Rule:    RuleQuotaExceeded,
+		Status:  alert.StatusFiring,
+		Subject: fmt.Sprintf("Client %s reached its data cap", client.Name),
+		Message: fmt.Sprintf(
+			"Client %s used %s of the %s of quota %s. Its max-limit was lowered from %s to %s.",
+			client.Name, formatGB(used), formatGB(quota.LimitBytes), quota.Name, client.MaxLimit, quota.ThrottleLimit,
+		),
+		StartedAt: now,
+	})
+
+	return nil
+}
+
+// restore sets the limits the client's queue had before it was throttled.
+// If the client no longer exists, only its state is cleared.
+func (s *service) restore(ctx context.Context, assignment *models.ClientQuota, reason string) error {
+	var (
+		now        = time.Now()
+		maxLimit   = assignment.OriginalMaxLimit
+		burstLimit = assignment.OriginalBurstLimit
+	)
+
+	if maxLimit == "" {
+		maxLimit = unlimited
+	}
+
+	if burstLimit == "" {
+		burstLimit = unlimited
+	}
+
+	client, err := s.mikrotikService.RequestClient(ctx, assignment.ClientID)
+	if err != nil {
+		return err
+	}
+
+	if client != nil {
+		err = s.mikrotikService.SetClientLimits(ctx, client.ID, models.QueueLimits{
+			MaxLimit:   maxLimit,
+			BurstLimit: burstLimit,
+		})
+		if err != nil {
+			return err
+		}
+
+		s.log.Info("quota: client limits restored", "client_id", client.ID, "reason", reason)
+
+		resolved := models.Alert{
+			Key:        RuleQuotaExceeded + ":" + client.ID,
+			Rule:       RuleQuotaExceeded,
+			Status:     alert.StatusResolved,
+			Subject:    fmt.Sprintf("Client %s reached its data cap", client.Name),
+			Message:    fmt.Sprintf("The max-limit of client %s was restored to %s because %s.", client.Name, maxLimit, reason),
+			ResolvedAt: &now,
+		}
+
+		if assignment.ThrottledAt != nil {
+			resolved.StartedAt = *assignment.ThrottledAt
+		}
+
+		alert.Notify(ctx, s.log, s.notifiers, resolved)
+	}
+
+	assignment.Throttled = false
+	assignment.ThrottledAt = nil
+	assignment.OriginalMaxLimit = ""
+	assignment.OriginalBurstLimit = ""
+
+	return nil
+}
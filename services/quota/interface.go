@@ -0,0 +1,67 @@
+package quota
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/alert"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/ab22/stormrage/services/traffic"
+	"github.com/jinzhu/gorm"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	ListQuotas() ([]models.Quota, error)
+	FindQuota(id int) (*models.Quota, error)
+	CreateQuota(quota *models.Quota) (*models.Quota, error)
+	UpdateQuota(id int, quota *models.Quota) (*models.Quota, error)
+	RemoveQuota(id int) error
+
+	ListStatuses() ([]models.QuotaStatus, error)
+	Status(clientID string) (*models.QuotaStatus, error)
+	Assign(ctx context.Context, clientID string, quotaID int) (*models.QuotaStatus, error)
+	Unassign(ctx context.Context, clientID string) error
+	Override(ctx context.Context, clientID string, operatorID *int) (*models.QuotaStatus, error)
+	RemoveOverride(clientID string) (*models.QuotaStatus, error)
+	ThrottledClients() (map[string]bool, error)
+}
+
+// RuleQuotaExceeded is the rule of the alerts sent when a client is
+// throttled and when its limits are restored.
+const RuleQuotaExceeded = "quota_exceeded"
+
+// service enforces the clients' data caps.
+type service struct {
+	cfg             *config.Config
+	db              *gorm.DB
+	log             logger.Logger
+	mikrotikService mikrotik.Service
+	trafficService  traffic.Service
+	notifiers       []alert.Notifier
+
+	// Serializes the changes of the clients' queues and quota state.
+	mutex sync.Mutex
+}
+
+// NewService initialization. If enabled, a goroutine that throttles the
+// clients over their cap every Quota.IntervalSeconds is started.
+func NewService(cfg *config.Config, db *gorm.DB, log logger.Logger, mikrotikService mikrotik.Service, trafficService traffic.Service, notifiers []alert.Notifier) Service {
+	s := &service{
+		cfg:             cfg,
+		db:              db,
+		log:             log,
+		mikrotikService: mikrotikService,
+		trafficService:  trafficService,
+		notifiers:       notifiers,
+	}
+
+	if cfg.Quota.IntervalSeconds > 0 {
+		go s.run()
+	}
+
+	return s
+}
@@ -0,0 +1,255 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/jinzhu/gorm"
+)
+
+// newStatus returns the limits the schedule sets on the client at now and
+// their next change.
+func newStatus(assignment models.ClientSchedule, schedule *models.BandwidthSchedule, now time.Time) models.ClientScheduleStatus {
+	status := models.ClientScheduleStatus{
+		ClientSchedule: assignment,
+		ScheduleName:   schedule.Name,
+		CurrentLimits:  assignment.BaseLimits(),
+	}
+
+	loc, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		return status
+	}
+
+	if rule := activeRule(schedule, loc, now); rule != nil {
+		status.CurrentLimits = rule.QueueLimits
+	}
+
+	if at, rule, ok := nextChange(schedule, loc, now); ok {
+		status.NextChange = &models.ScheduleChange{
+			At:     at,
+			RuleID: ruleID(rule),
+			Limits: assignment.BaseLimits(),
+		}
+
+		if rule != nil {
+			status.NextChange.Limits = rule.QueueLimits
+		}
+	}
+
+	return status
+}
+
+// findAssignment searches for the schedule assigned to a client, or returns
+// nil.
+func (s *service) findAssignment(clientID string) (*models.ClientSchedule, error) {
+	assignment := &models.ClientSchedule{}
+
+	err := s.db.
+		Where("client_id = ?", clientID).
+		First(assignment).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return assignment, nil
+}
+
+// schedulesByID returns all schedules with their rules by ID.
+func (s *service) schedulesByID() (map[int]*models.BandwidthSchedule, error) {
+	schedules, err := s.ListSchedules()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*models.BandwidthSchedule, len(schedules))
+	for i := range schedules {
+		byID[schedules[i].ID] = &schedules[i]
+	}
+
+	return byID, nil
+}
+
+// ListStatuses returns the limits and next change of every client with a
+// schedule.
+func (s *service) ListStatuses() ([]models.ClientScheduleStatus, error) {
+	var (
+		now         = time.Now()
+		assignments = []models.ClientSchedule{}
+	)
+
+	schedules, err := s.schedulesByID()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.
+		Order("client_id").
+		Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]models.ClientScheduleStatus, 0, len(assignments))
+
+	for _, assignment := range assignments {
+		schedule, ok := schedules[assignment.ScheduleID]
+		if !ok {
+			continue
+		}
+
+		statuses = append(statuses, newStatus(assignment, schedule, now))
+	}
+
+	return statuses, nil
+}
+
+// Status returns the client's limits and their next change, or nil if the
+// client has no schedule.
+func (s *service) Status(clientID string) (*models.ClientScheduleStatus, error) {
+	assignment, err := s.findAssignment(clientID)
+	if err != nil || assignment == nil {
+		return nil, err
+	}
+
+	schedule, err := s.FindSchedule(assignment.ScheduleID)
+	if err != nil {
+		return nil, err
+	} else if schedule == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	status := newStatus(*assignment, schedule, time.Now())
+	return &status, nil
+}
+
+// Assign sets the client's schedule and applies its limits right away. The
+// base limits are the ones applied when no rule is active. If they are not
+// given, the queue's current limits are used for new assignments and the
+// previous base limits are kept otherwise.
+func (s *service) Assign(ctx context.Context, clientID string, scheduleID int, base *models.QueueLimits) (*models.ClientScheduleStatus, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	schedule, err := s.FindSchedule(scheduleID)
+	if err != nil {
+		return nil, err
+	} else if schedule == nil {
+		return nil, services.ErrInvalidArgument("schedule does not exist")
+	}
+
+	if base != nil {
+		if err = validLimits(*base); err != nil {
+			return nil, err
+		}
+	}
+
+	throttled, err := s.quotaService.ThrottledClients()
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.findAssignment(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	save := s.db.Save
+	if assignment == nil {
+		save = s.db.Create
+
+		if base == nil {
+			// The queue of a throttled client has the quota's limits.
+			if throttled[clientID] {
+				return nil, services.ErrInvalidArgument("the client is throttled by its quota, its base limits must be given")
+			}
+
+			client, err := s.mikrotikService.RequestClient(ctx, clientID)
+			if err != nil {
+				return nil, err
+			} else if client == nil {
+				return nil, services.ErrRecordNotFound
+			}
+
+			base = &models.QueueLimits{
+				MaxLimit:       client.MaxLimit,
+				BurstLimit:     client.BurstLimit,
+				BurstThreshold: client.BurstThreshold,
+				BurstTime:      client.BurstTime,
+			}
+		}
+
+		assignment = &models.ClientSchedule{
+			ClientID:   clientID,
+			ScheduleID: schedule.ID,
+		}
+	}
+
+	if base != nil {
+		assignment.BaseMaxLimit = base.MaxLimit
+		assignment.BaseBurstLimit = base.BurstLimit
+		assignment.BaseBurstThreshold = base.BurstThreshold
+		assignment.BaseBurstTime = base.BurstTime
+	}
+
+	assignment.ScheduleID = schedule.ID
+	assignment.AppliedAt = nil
+
+	if _, err = s.apply(ctx, assignment, schedule, throttled[clientID], now); err != nil {
+		return nil, err
+	}
+
+	if err = save(assignment).Error; err != nil {
+		return nil, err
+	}
+
+	status := newStatus(*assignment, schedule, now)
+	return &status, nil
+}
+
+// Unassign removes the client's schedule and restores its base limits if a
+// rule is applied. Returns ErrRecordNotFound if the client has no schedule.
+func (s *service) Unassign(ctx context.Context, clientID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	assignment, err := s.findAssignment(clientID)
+	if err != nil {
+		return err
+	} else if assignment == nil {
+		return services.ErrRecordNotFound
+	}
+
+	throttled, err := s.quotaService.ThrottledClients()
+	if err != nil {
+		return err
+	}
+
+	if assignment.ActiveRuleID != nil && assignment.AppliedAt != nil && !throttled[clientID] {
+		client, err := s.mikrotikService.RequestClient(ctx, clientID)
+		if err != nil {
+			return err
+		}
+
+		if client != nil {
+			err = s.mikrotikService.SetClientLimits(ctx, client.ID, withDefaults(assignment.BaseLimits()))
+			if err != nil {
+				return err
+			}
+
+			s.log.Info("schedule: client base limits restored", "client_id", client.ID)
+		}
+	}
+
+	return s.db.
+		Where("client_id = ?", clientID).
+		Delete(&models.ClientSchedule{}).Error
+}
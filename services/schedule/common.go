@@ -0,0 +1,359 @@
+package schedule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/jinzhu/gorm"
+)
+
+// burstTimeRegexp matches the upload/download burst-time of a simple
+// queue, e.g. "8s/8s".
+var burstTimeRegexp = regexp.MustCompile(`^[0-9]+[smh]?/[0-9]+[smh]?$`)
+
+// validLimits checks the limits of a rule or the base limits of a client.
+// Only the max-limit is required.
+func validLimits(limits models.QueueLimits) error {
+	if !mikrotik.IsValidRateLimit(limits.MaxLimit) {
+		return services.ErrInvalidArgument("maxLimit must have the form upload/download, e.g. 512k/1M")
+	}
+
+	if limits.BurstLimit != "" && !mikrotik.IsValidRateLimit(limits.BurstLimit) {
+		return services.ErrInvalidArgument("burstLimit must have the form upload/download, e.g. 1M/4M")
+	}
+
+	if limits.BurstThreshold != "" && !mikrotik.IsValidRateLimit(limits.BurstThreshold) {
+		return services.ErrInvalidArgument("burstThreshold must have the form upload/download, e.g. 768k/3M")
+	}
+
+	if limits.BurstTime != "" && !burstTimeRegexp.MatchString(limits.BurstTime) {
+		return services.ErrInvalidArgument("burstTime must have the form upload/download, e.g. 8s/8s")
+	}
+
+	return nil
+}
+
+// validRule checks the rule's fields. Its days are normalized to the order
+// of the week, without duplicates, and its times to HH:MM.
+func validRule(rule *models.BandwidthRule) error {
+	selected := map[string]bool{}
+
+	for _, day := range rule.Days {
+		day = strings.ToLower(strings.TrimSpace(day))
+
+		if !isWeekday(day) {
+			return services.ErrInvalidArgument(fmt.Sprintf("invalid day [%s], it must be one of %s", day, strings.Join(weekdays, ", ")))
+		}
+
+		selected[day] = true
+	}
+
+	rule.Days = []string{}
+	for _, day := range weekdays {
+		if selected[day] {
+			rule.Days = append(rule.Days, day)
+		}
+	}
+
+	start, err := parseClock(rule.Start)
+	if err != nil {
+		return services.ErrInvalidArgument(err.Error())
+	}
+
+	end, err := parseClock(rule.End)
+	if err != nil {
+		return services.ErrInvalidArgument(err.Error())
+	}
+
+	if start == end {
+		return services.ErrInvalidArgument("the start and end of a rule must be different")
+	}
+
+	rule.Start = formatClock(start)
+	rule.End = formatClock(end)
+
+	return validLimits(rule.QueueLimits)
+}
+
+// isWeekday checks if day is the name of a day of the week.
+func isWeekday(day string) bool {
+	for _, d := range weekdays {
+		if d == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validSchedule checks the schedule's fields and rules. An empty time zone
+// is set to UTC.
+func validSchedule(schedule *models.BandwidthSchedule) error {
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	if schedule.Name == "" {
+		return services.ErrInvalidArgument("schedule name is required")
+	}
+
+	if schedule.TimeZone == "" {
+		schedule.TimeZone = "UTC"
+	}
+
+	if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+		return services.ErrInvalidArgument(fmt.Sprintf("unknown time zone [%s]", schedule.TimeZone))
+	}
+
+	for i := range schedule.Rules {
+		if err := validRule(&schedule.Rules[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadRules sets the rules of the schedules, in order.
+func (s *service) loadRules(schedules []models.BandwidthSchedule) error {
+	var (
+		rules = []models.BandwidthRule{}
+		ids   = make([]int, 0, len(schedules))
+		byID  = make(map[int]*models.BandwidthSchedule, len(schedules))
+	)
+
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	for i := range schedules {
+		schedules[i].Rules = []models.BandwidthRule{}
+		ids = append(ids, schedules[i].ID)
+		byID[schedules[i].ID] = &schedules[i]
+	}
+
+	err := s.db.
+		Where("schedule_id IN (?)", ids).
+		Order("schedule_id, position").
+		Find(&rules).Error
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		rule.Days = splitDays(rule.DayList)
+
+		schedule := byID[rule.ScheduleID]
+		schedule.Rules = append(schedule.Rules, rule)
+	}
+
+	return nil
+}
+
+// ListSchedules returns all schedules with their rules.
+func (s *service) ListSchedules() ([]models.BandwidthSchedule, error) {
+	schedules := []models.BandwidthSchedule{}
+
+	err := s.db.
+		Order("name").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.loadRules(schedules); err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+// FindSchedule searches for a schedule by ID.
+// Returns *models.BandwidthSchedule instance with its rules if it finds it,
+// or nil otherwise.
+func (s *service) FindSchedule(id int) (*models.BandwidthSchedule, error) {
+	schedule := models.BandwidthSchedule{}
+
+	err := s.db.
+		Where("id = ?", id).
+		First(&schedule).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	schedules := []models.BandwidthSchedule{schedule}
+	if err = s.loadRules(schedules); err != nil {
+		return nil, err
+	}
+
+	return &schedules[0], nil
+}
+
+// findScheduleByName searches for a schedule by name, or returns nil.
+func (s *service) findScheduleByName(name string) (*models.BandwidthSchedule, error) {
+	schedule := &models.BandwidthSchedule{}
+
+	err := s.db.
+		Where("name = ?", name).
+		First(schedule).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return schedule, nil
+}
+
+// saveRules replaces the rules of the schedule.
+func saveRules(tx *gorm.DB, scheduleID int, rules []models.BandwidthRule) ([]models.BandwidthRule, error) {
+	err := tx.
+		Where("schedule_id = ?", scheduleID).
+		Delete(&models.BandwidthRule{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	saved := make([]models.BandwidthRule, 0, len(rules))
+
+	for i, rule := range rules {
+		created := models.BandwidthRule{
+			ScheduleID:  scheduleID,
+			Position:    i,
+			Days:        rule.Days,
+			DayList:     strings.Join(rule.Days, ","),
+			Start:       rule.Start,
+			End:         rule.End,
+			QueueLimits: rule.QueueLimits,
+		}
+
+		if err = tx.Create(&created).Error; err != nil {
+			return nil, err
+		}
+
+		saved = append(saved, created)
+	}
+
+	return saved, nil
+}
+
+// CreateSchedule adds a new schedule with its rules. Schedule names must be
+// unique.
+func (s *service) CreateSchedule(schedule *models.BandwidthSchedule) (*models.BandwidthSchedule, error) {
+	if err := validSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findScheduleByName(schedule.Name)
+	if err != nil {
+		return nil, err
+	} else if existing != nil {
+		return nil, services.ErrInvalidArgument("schedule name is already in use")
+	}
+
+	created := &models.BandwidthSchedule{
+		Name:     schedule.Name,
+		TimeZone: schedule.TimeZone,
+	}
+
+	tx := s.db.Begin()
+
+	if err = tx.Create(created).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if created.Rules, err = saveRules(tx, created.ID, schedule.Rules); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// UpdateSchedule replaces the fields and rules of a schedule. Its clients'
+// limits are updated on the next check. Returns ErrRecordNotFound if the
+// schedule does not exist.
+func (s *service) UpdateSchedule(id int, schedule *models.BandwidthSchedule) (*models.BandwidthSchedule, error) {
+	if err := validSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.findScheduleByName(schedule.Name)
+	if err != nil {
+		return nil, err
+	} else if existing != nil && existing.ID != id {
+		return nil, services.ErrInvalidArgument("schedule name is already in use")
+	}
+
+	updated, err := s.FindSchedule(id)
+	if err != nil {
+		return nil, err
+	} else if updated == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	updated.Name = schedule.Name
+	updated.TimeZone = schedule.TimeZone
+
+	tx := s.db.Begin()
+
+	if err = tx.Save(updated).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if updated.Rules, err = saveRules(tx, updated.ID, schedule.Rules); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// The clients' limits are applied again, since their rules changed.
+	err = tx.
+		Model(&models.ClientSchedule{}).
+		Where("schedule_id = ?", id).
+		UpdateColumn("applied_at", gorm.Expr("NULL")).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// RemoveSchedule deletes a schedule that is not assigned to any client.
+func (s *service) RemoveSchedule(id int) error {
+	var assigned int
+
+	err := s.db.
+		Model(&models.ClientSchedule{}).
+		Where("schedule_id = ?", id).
+		Count(&assigned).Error
+	if err != nil {
+		return err
+	}
+
+	if assigned > 0 {
+		return services.ErrInvalidArgument(fmt.Sprintf("schedule is assigned to %d clients", assigned))
+	}
+
+	return s.db.
+		Where("id = ?", id).
+		Delete(&models.BandwidthSchedule{}).Error
+}
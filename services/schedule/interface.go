@@ -0,0 +1,59 @@
+package schedule
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/ab22/stormrage/services/quota"
+	"github.com/jinzhu/gorm"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	ListSchedules() ([]models.BandwidthSchedule, error)
+	FindSchedule(id int) (*models.BandwidthSchedule, error)
+	CreateSchedule(schedule *models.BandwidthSchedule) (*models.BandwidthSchedule, error)
+	UpdateSchedule(id int, schedule *models.BandwidthSchedule) (*models.BandwidthSchedule, error)
+	RemoveSchedule(id int) error
+
+	ListStatuses() ([]models.ClientScheduleStatus, error)
+	Status(clientID string) (*models.ClientScheduleStatus, error)
+	Assign(ctx context.Context, clientID string, scheduleID int, base *models.QueueLimits) (*models.ClientScheduleStatus, error)
+	Unassign(ctx context.Context, clientID string) error
+}
+
+// service applies the clients' bandwidth schedules to their simple queues.
+type service struct {
+	cfg             *config.Config
+	db              *gorm.DB
+	log             logger.Logger
+	mikrotikService mikrotik.Service
+	quotaService    quota.Service
+
+	// Serializes the changes of the clients' queues and schedule state.
+	mutex sync.Mutex
+}
+
+// NewService initialization. If enabled, a goroutine that applies the
+// clients' schedules every Schedule.IntervalSeconds is started. The
+// schedules are applied once when it starts, so the changes missed while
+// the server was down are caught up.
+func NewService(cfg *config.Config, db *gorm.DB, log logger.Logger, mikrotikService mikrotik.Service, quotaService quota.Service) Service {
+	s := &service{
+		cfg:             cfg,
+		db:              db,
+		log:             log,
+		mikrotikService: mikrotikService,
+		quotaService:    quotaService,
+	}
+
+	if cfg.Schedule.IntervalSeconds > 0 {
+		go s.run()
+	}
+
+	return s
+}
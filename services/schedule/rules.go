@@ -0,0 +1,151 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+)
+
+// weekdays are the names of the days of a rule, by time.Weekday.
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// nextChangeDays is how far ahead the next change of a schedule is
+// searched for. A week and a day covers every rule, including the ones that
+// end on the next day.
+const nextChangeDays = 8
+
+// parseClock returns the minutes since midnight of a "HH:MM" time.
+func parseClock(v string) (int, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time [%s], it must have the form HH:MM", v)
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// formatClock returns the "HH:MM" time of the minutes since midnight.
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// splitDays returns the days of a rule's day list.
+func splitDays(list string) []string {
+	if list == "" {
+		return []string{}
+	}
+
+	return strings.Split(list, ",")
+}
+
+// onDay checks if the rule applies on the day of the week. Rules without
+// days apply every day.
+func onDay(rule models.BandwidthRule, day time.Weekday) bool {
+	if len(rule.Days) == 0 {
+		return true
+	}
+
+	for _, d := range rule.Days {
+		if d == weekdays[day] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isActive checks if the rule is active at t. Rules whose end is before
+// their start are active from the start on their days until the end of the
+// next day. The rule's times must have been validated.
+func isActive(rule models.BandwidthRule, t time.Time) bool {
+	var (
+		start, _ = parseClock(rule.Start)
+		end, _   = parseClock(rule.End)
+		now      = t.Hour()*60 + t.Minute()
+		day      = t.Weekday()
+	)
+
+	if start < end {
+		return onDay(rule, day) && now >= start && now < end
+	}
+
+	return (onDay(rule, day) && now >= start) ||
+		(onDay(rule, (day+6)%7) && now < end)
+}
+
+// activeRule returns the first rule that is active at t in the schedule's
+// time zone, or nil if none is.
+func activeRule(schedule *models.BandwidthSchedule, loc *time.Location, t time.Time) *models.BandwidthRule {
+	t = t.In(loc)
+
+	for i := range schedule.Rules {
+		if isActive(schedule.Rules[i], t) {
+			return &schedule.Rules[i]
+		}
+	}
+
+	return nil
+}
+
+// ruleID returns the ID of the rule, or nil.
+func ruleID(rule *models.BandwidthRule) *int {
+	if rule == nil {
+		return nil
+	}
+
+	id := rule.ID
+	return &id
+}
+
+// sameRule checks if two rule IDs are the same rule, or both nil.
+func sameRule(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// nextChange returns the first time after t when the active rule of the
+// schedule changes, and the rule active from then on. Returns false if the
+// active rule never changes.
+func nextChange(schedule *models.BandwidthSchedule, loc *time.Location, t time.Time) (time.Time, *models.BandwidthRule, bool) {
+	var (
+		local      = t.In(loc)
+		current    = ruleID(activeRule(schedule, loc, t))
+		candidates []time.Time
+	)
+
+	// The active rule can only change when a rule starts or ends.
+	for offset := 0; offset <= nextChangeDays; offset++ {
+		y, m, d := local.AddDate(0, 0, offset).Date()
+
+		for _, rule := range schedule.Rules {
+			for _, clock := range []string{rule.Start, rule.End} {
+				minutes, _ := parseClock(clock)
+				at := time.Date(y, m, d, minutes/60, minutes%60, 0, 0, loc)
+
+				if at.After(t) {
+					candidates = append(candidates, at)
+				}
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Before(candidates[j])
+	})
+
+	for _, at := range candidates {
+		rule := activeRule(schedule, loc, at)
+
+		if !sameRule(current, ruleID(rule)) {
+			return at, rule, true
+		}
+	}
+
+	return time.Time{}, nil, false
+}
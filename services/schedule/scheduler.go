@@ -0,0 +1,161 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+)
+
+// Limits set on the queue in place of the empty limits of a rule, so that
+// the previous rule's limits don't remain.
+const (
+	unlimited   = "0/0"
+	noBurstTime = "0s/0s"
+)
+
+// catchUpTimeout is the minimum time limit of the check made at startup,
+// which can change the limits of every client.
+const catchUpTimeout = 30 * time.Second
+
+// run applies the clients' schedules when it starts and every
+// Schedule.IntervalSeconds.
+func (s *service) run() {
+	interval := time.Duration(s.cfg.Schedule.IntervalSeconds) * time.Second
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.tick(max(interval, catchUpTimeout))
+
+	for range ticker.C {
+		s.tick(interval)
+	}
+}
+
+// tick applies the clients' schedules with the given time limit.
+func (s *service) tick(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := s.evaluate(ctx, time.Now()); err != nil {
+		s.log.Error("schedule: could not apply the clients' schedules", "error", err)
+	}
+}
+
+// withDefaults replaces the empty limits with the values that remove them.
+func withDefaults(limits models.QueueLimits) models.QueueLimits {
+	if limits.MaxLimit == "" {
+		limits.MaxLimit = unlimited
+	}
+
+	if limits.BurstLimit == "" {
+		limits.BurstLimit = unlimited
+	}
+
+	if limits.BurstThreshold == "" {
+		limits.BurstThreshold = unlimited
+	}
+
+	if limits.BurstTime == "" {
+		limits.BurstTime = noBurstTime
+	}
+
+	return limits
+}
+
+// evaluate sets the limits of the clients whose active rule changed since
+// they were last applied. A failure with one client doesn't stop the
+// others.
+func (s *service) evaluate(ctx context.Context, now time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	assignments := []models.ClientSchedule{}
+
+	schedules, err := s.schedulesByID()
+	if err != nil {
+		return err
+	}
+
+	throttled, err := s.quotaService.ThrottledClients()
+	if err != nil {
+		return err
+	}
+
+	if err = s.db.Find(&assignments).Error; err != nil {
+		return err
+	}
+
+	for i := range assignments {
+		assignment := &assignments[i]
+
+		schedule, ok := schedules[assignment.ScheduleID]
+		if !ok {
+			continue
+		}
+
+		changed, err := s.apply(ctx, assignment, schedule, throttled[assignment.ClientID], now)
+		if err != nil {
+			s.log.Error("schedule: could not update client", "client_id", assignment.ClientID, "error", err)
+			continue
+		}
+
+		if !changed {
+			continue
+		}
+
+		if err = s.db.Save(assignment).Error; err != nil {
+			s.log.Error("schedule: could not save client state", "client_id", assignment.ClientID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// apply sets the limits of the rule that is active at now on the client's
+// queue, or its base limits if none is, unless they are already applied.
+// The queues of throttled clients are left to the quota service and their
+// limits are applied once they are restored. The assignment is updated but
+// not saved. Returns whether it was updated.
+func (s *service) apply(ctx context.Context, assignment *models.ClientSchedule, schedule *models.BandwidthSchedule, throttled bool, now time.Time) (bool, error) {
+	loc, err := time.LoadLocation(schedule.TimeZone)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		rule   = activeRule(schedule, loc, now)
+		id     = ruleID(rule)
+		limits = assignment.BaseLimits()
+	)
+
+	if throttled {
+		if assignment.AppliedAt == nil && sameRule(assignment.ActiveRuleID, id) {
+			return false, nil
+		}
+
+		assignment.ActiveRuleID = id
+		assignment.AppliedAt = nil
+		return true, nil
+	}
+
+	if assignment.AppliedAt != nil && sameRule(assignment.ActiveRuleID, id) {
+		return false, nil
+	}
+
+	if rule != nil {
+		limits = rule.QueueLimits
+	}
+
+	err = s.mikrotikService.SetClientLimits(ctx, assignment.ClientID, withDefaults(limits))
+	if err != nil {
+		return false, err
+	}
+
+	assignment.ActiveRuleID = id
+	assignment.AppliedAt = &now
+
+	s.log.Info("schedule: client limits applied", "client_id", assignment.ClientID, "schedule", schedule.Name, "rule_id", id, "max_limit", limits.MaxLimit)
+	return true, nil
+}
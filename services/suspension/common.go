@@ -0,0 +1,89 @@
+package suspension
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+)
+
+// Suspend cuts the traffic of each client by adding their target addresses
+// to the router's suspended clients' address list. Every client is processed
+// even if some of them fail, and the result of each one is returned in the
+// same order.
+func (s *service) Suspend(ctx context.Context, clientIDs []string, reason string, operatorID *int) []Result {
+	comment := fmt.Sprintf("stormrage: %s", reason)
+
+	return s.apply(ctx, clientIDs, ActionSuspend, reason, operatorID, func(id string) (*models.Client, error) {
+		return s.mikrotikService.SuspendClient(ctx, id, comment)
+	})
+}
+
+// Restore removes the clients' target addresses from the router's suspended
+// clients' address list.
+func (s *service) Restore(ctx context.Context, clientIDs []string, reason string, operatorID *int) []Result {
+	return s.apply(ctx, clientIDs, ActionRestore, reason, operatorID, func(id string) (*models.Client, error) {
+		return s.mikrotikService.RestoreClient(ctx, id)
+	})
+}
+
+// apply runs the action for each client and records an event for each client
+// that was updated on the router.
+func (s *service) apply(ctx context.Context, clientIDs []string, action, reason string, operatorID *int, fn func(string) (*models.Client, error)) []Result {
+	var (
+		log     = logger.FromContext(ctx, nil)
+		results = make([]Result, 0, len(clientIDs))
+	)
+
+	for _, id := range clientIDs {
+		result := Result{ClientID: id}
+
+		client, err := fn(id)
+		if err != nil {
+			result.Error = err.Error()
+			result.Err = err
+			results = append(results, result)
+
+			log.Warn("suspension: could not update client", "action", action, "client_id", id, "error", err)
+			continue
+		}
+
+		result.Client = client
+
+		event := &models.SuspensionEvent{
+			ClientID:   client.ID,
+			ClientName: client.Name,
+			Target:     client.Target,
+			Action:     action,
+			Reason:     reason,
+			OperatorID: operatorID,
+		}
+
+		if err = s.db.Create(event).Error; err != nil {
+			// The router was already updated, so the error is only logged.
+			log.Error("suspension: could not record event", "action", action, "client_id", id, "error", err)
+		}
+
+		log.Info("suspension: client updated", "action", action, "client_id", id, "reason", reason)
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// History returns all suspension events of a client, newest first.
+func (s *service) History(clientID string) ([]models.SuspensionEvent, error) {
+	events := []models.SuspensionEvent{}
+
+	err := s.db.
+		Where("client_id = ?", clientID).
+		Order("created_at DESC").
+		Find(&events).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
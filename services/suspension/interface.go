@@ -0,0 +1,45 @@
+package suspension
+
+import (
+	"context"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/jinzhu/gorm"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	Suspend(ctx context.Context, clientIDs []string, reason string, operatorID *int) []Result
+	Restore(ctx context.Context, clientIDs []string, reason string, operatorID *int) []Result
+	History(clientID string) ([]models.SuspensionEvent, error)
+}
+
+// Actions recorded in the suspension events.
+const (
+	ActionSuspend = "suspend"
+	ActionRestore = "restore"
+)
+
+// Result contains the outcome of suspending or restoring a single client.
+type Result struct {
+	ClientID string         `json:"clientId"`
+	Client   *models.Client `json:"client"`
+	Error    string         `json:"error,omitempty"`
+	Err      error          `json:"-"`
+}
+
+// service suspends clients on the router and keeps a record of all
+// suspensions.
+type service struct {
+	db              *gorm.DB
+	mikrotikService mikrotik.Service
+}
+
+// NewService initialization.
+func NewService(db *gorm.DB, mikrotikService mikrotik.Service) Service {
+	return &service{
+		db:              db,
+		mikrotikService: mikrotikService,
+	}
+}
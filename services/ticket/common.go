@@ -0,0 +1,288 @@
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/jinzhu/gorm"
+)
+
+// errInvalidTarget is returned when a ticket's target is not an address of
+// the client's queue.
+var errInvalidTarget = services.ErrInvalidArgument("target must be an address of the client's queue")
+
+// validStatus checks if the status is one of the ticket statuses.
+func validStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// canTransition checks if a ticket's status can be changed from one status
+// to the other.
+func canTransition(from, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validAssignee checks that the assignee is an existing user. A nil
+// assignee leaves the ticket unassigned.
+func (s *service) validAssignee(assigneeID *int) error {
+	if assigneeID == nil {
+		return nil
+	}
+
+	u, err := s.userService.FindByID(*assigneeID)
+	if err != nil {
+		return err
+	} else if u == nil {
+		return services.ErrInvalidArgument("assignee does not exist")
+	}
+
+	return nil
+}
+
+// List returns the tickets that match the filter, newest first, without
+// their diagnostics and comments.
+func (s *service) List(filter Filter) ([]models.Ticket, error) {
+	var (
+		tickets = []models.Ticket{}
+		query   = s.db
+	)
+
+	if filter.Status != "" {
+		if !validStatus(filter.Status) {
+			return nil, services.ErrInvalidArgument("status must be open, in_progress or resolved")
+		}
+
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if filter.ClientID != "" {
+		query = query.Where("client_id = ?", filter.ClientID)
+	}
+
+	if filter.AssigneeID != nil {
+		query = query.Where("assignee_id = ?", *filter.AssigneeID)
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
+// findTicket searches for a ticket by ID without its diagnostics and
+// comments, or returns nil.
+func (s *service) findTicket(id int) (*models.Ticket, error) {
+	ticket := &models.Ticket{}
+
+	err := s.db.
+		Where("id = ?", id).
+		First(ticket).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return ticket, nil
+}
+
+// Find searches for a ticket by ID.
+// Returns *models.Ticket instance with its diagnostics and comments if it
+// finds it, or nil otherwise.
+func (s *service) Find(id int) (*models.Ticket, error) {
+	ticket, err := s.findTicket(id)
+	if err != nil || ticket == nil {
+		return nil, err
+	}
+
+	diagnostics := &models.TicketDiagnostics{}
+
+	err = s.db.
+		Where("ticket_id = ?", id).
+		First(diagnostics).Error
+	if err == nil {
+		ticket.Diagnostics = diagnostics
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	ticket.Comments = []models.TicketComment{}
+
+	err = s.db.
+		Where("ticket_id = ?", id).
+		Order("created_at, id").
+		Find(&ticket.Comments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ticket, nil
+}
+
+// Create opens a ticket for a client and attaches the client's diagnostics.
+// Returns ErrRecordNotFound if the client does not exist. If the router
+// can't be reached the ticket is still created, with the error recorded in
+// its diagnostics.
+func (s *service) Create(ctx context.Context, ticket *models.Ticket) (*models.Ticket, error) {
+	created := &models.Ticket{
+		ClientID:    ticket.ClientID,
+		Target:      strings.TrimSpace(ticket.Target),
+		Subject:     strings.TrimSpace(ticket.Subject),
+		Description: strings.TrimSpace(ticket.Description),
+		Status:      StatusOpen,
+		AssigneeID:  ticket.AssigneeID,
+		CreatedBy:   ticket.CreatedBy,
+	}
+
+	if created.Subject == "" {
+		return nil, services.ErrInvalidArgument("subject can't be empty")
+	}
+
+	if err := s.validAssignee(created.AssigneeID); err != nil {
+		return nil, err
+	}
+
+	client, routerErr := s.mikrotikService.RequestClient(ctx, created.ClientID)
+	if routerErr != nil {
+		s.log.Warn("ticket: could not request client, creating ticket without router diagnostics", "client_id", created.ClientID, "error", routerErr)
+	} else if client == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	if created.Target != "" {
+		if err := validTarget(created.Target, client); err != nil {
+			return nil, err
+		}
+	}
+
+	diagnostics := s.diagnose(ctx, created, client, routerErr)
+
+	tx := s.db.Begin()
+
+	if err := tx.Create(created).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	diagnostics.TicketID = created.ID
+
+	if err := tx.Create(diagnostics).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	s.log.Info("ticket: created", "ticket_id", created.ID, "client_id", created.ClientID, "created_by", created.CreatedBy)
+
+	created.Diagnostics = diagnostics
+	created.Comments = []models.TicketComment{}
+
+	return created, nil
+}
+
+// SetStatus changes the status of a ticket. Resolved tickets can only be
+// reopened. Returns ErrRecordNotFound if the ticket does not exist.
+func (s *service) SetStatus(id int, status string) (*models.Ticket, error) {
+	if !validStatus(status) {
+		return nil, services.ErrInvalidArgument("status must be open, in_progress or resolved")
+	}
+
+	ticket, err := s.findTicket(id)
+	if err != nil {
+		return nil, err
+	} else if ticket == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	if ticket.Status == status {
+		return ticket, nil
+	} else if !canTransition(ticket.Status, status) {
+		return nil, services.ErrInvalidArgument(fmt.Sprintf("status can't be changed from %s to %s", ticket.Status, status))
+	}
+
+	ticket.Status = status
+	ticket.ResolvedAt = nil
+
+	if status == StatusResolved {
+		now := time.Now()
+		ticket.ResolvedAt = &now
+	}
+
+	if err = s.db.Save(ticket).Error; err != nil {
+		return nil, err
+	}
+
+	return ticket, nil
+}
+
+// Assign sets the user in charge of a ticket, or unassigns it if the
+// assignee is nil. Returns ErrRecordNotFound if the ticket does not exist.
+func (s *service) Assign(id int, assigneeID *int) (*models.Ticket, error) {
+	ticket, err := s.findTicket(id)
+	if err != nil {
+		return nil, err
+	} else if ticket == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	if err = s.validAssignee(assigneeID); err != nil {
+		return nil, err
+	}
+
+	ticket.AssigneeID = assigneeID
+
+	if err = s.db.Save(ticket).Error; err != nil {
+		return nil, err
+	}
+
+	return ticket, nil
+}
+
+// AddComment adds a comment to a ticket. Returns ErrRecordNotFound if the
+// ticket does not exist.
+func (s *service) AddComment(id int, comment *models.TicketComment) (*models.TicketComment, error) {
+	created := &models.TicketComment{
+		TicketID: id,
+		UserID:   comment.UserID,
+		Body:     strings.TrimSpace(comment.Body),
+	}
+
+	if created.Body == "" {
+		return nil, services.ErrInvalidArgument("body can't be empty")
+	}
+
+	ticket, err := s.findTicket(id)
+	if err != nil {
+		return nil, err
+	} else if ticket == nil {
+		return nil, services.ErrRecordNotFound
+	}
+
+	if err = s.db.Create(created).Error; err != nil {
+		return nil, err
+	}
+
+	// Commenting counts as activity on the ticket.
+	if err = s.db.Model(ticket).UpdateColumn("updated_at", created.CreatedAt).Error; err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
@@ -0,0 +1,112 @@
+package ticket
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+)
+
+const (
+	// diagnosticPings is the number of packets sent to the client's
+	// address when a ticket is created.
+	diagnosticPings = 3
+
+	// monitorWindow is how far back the monitoring service's probes are
+	// looked up for the client's status.
+	monitorWindow = 24 * time.Hour
+)
+
+// pingAddress returns the address pinged for the diagnostics: the ticket's
+// target, or the client's first host address. Subnets can't be pinged, so
+// an empty string is returned if there is no host address.
+func pingAddress(target string, client *models.Client) string {
+	for _, address := range append([]string{target}, client.TargetAddresses()...) {
+		if address != "" && !strings.Contains(address, "/") {
+			return address
+		}
+	}
+
+	return ""
+}
+
+// diagnose collects the state of the client when a ticket is created. The
+// client is nil if the router could not be queried, in which case routerErr
+// is recorded and only the monitoring service's status is collected.
+// Failures don't prevent the ticket from being created, so they are
+// recorded in the diagnostics instead of returned.
+func (s *service) diagnose(ctx context.Context, ticket *models.Ticket, client *models.Client, routerErr error) *models.TicketDiagnostics {
+	diagnostics := &models.TicketDiagnostics{}
+
+	availability, err := s.monitorService.Availability(ticket.ClientID, time.Now().Add(-monitorWindow))
+	if err != nil {
+		s.log.Warn("ticket: could not get client availability", "client_id", ticket.ClientID, "error", err)
+	} else {
+		diagnostics.MonitorStatus = availability.Status
+		diagnostics.LastProbeAt = availability.LastProbeAt
+	}
+
+	if routerErr != nil {
+		diagnostics.Error = routerErr.Error()
+		return diagnostics
+	}
+
+	diagnostics.ClientName = client.Name
+	diagnostics.Suspended = client.Suspended
+	diagnostics.QueueLimits = models.QueueLimits{
+		MaxLimit:       client.MaxLimit,
+		BurstLimit:     client.BurstLimit,
+		BurstThreshold: client.BurstThreshold,
+		BurstTime:      client.BurstTime,
+	}
+
+	diagnostics.PingAddress = pingAddress(ticket.Target, client)
+	if diagnostics.PingAddress == "" {
+		return diagnostics
+	}
+
+	result, err := s.mikrotikService.Ping(ctx, diagnostics.PingAddress, diagnosticPings)
+	if err != nil {
+		s.log.Warn("ticket: could not ping client", "client_id", ticket.ClientID, "address", diagnostics.PingAddress, "error", err)
+		diagnostics.Error = err.Error()
+		return diagnostics
+	}
+
+	reachable := result.Received > 0
+
+	diagnostics.PingSent = result.Sent
+	diagnostics.PingReceived = result.Received
+	diagnostics.PingPacketLoss = &result.PacketLoss
+	diagnostics.Reachable = &reachable
+
+	if reachable {
+		diagnostics.PingAvgRTT = &result.AvgRTT
+	}
+
+	return diagnostics
+}
+
+// validTarget checks that the target is an address of the client's queue.
+// If the router could not be queried, the client is nil and only the
+// format of the address or subnet is checked.
+func validTarget(target string, client *models.Client) error {
+	if client == nil {
+		if _, err := netip.ParseAddr(target); err == nil {
+			return nil
+		} else if _, err := netip.ParsePrefix(target); err == nil {
+			return nil
+		}
+
+		return errInvalidTarget
+	}
+
+	for _, address := range client.TargetAddresses() {
+		if address == target {
+			return nil
+		}
+	}
+
+	return errInvalidTarget
+}
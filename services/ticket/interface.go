@@ -0,0 +1,65 @@
+package ticket
+
+import (
+	"context"
+
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/ab22/stormrage/services/monitor"
+	"github.com/ab22/stormrage/services/user"
+	"github.com/jinzhu/gorm"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	List(filter Filter) ([]models.Ticket, error)
+	Find(id int) (*models.Ticket, error)
+	Create(ctx context.Context, ticket *models.Ticket) (*models.Ticket, error)
+	SetStatus(id int, status string) (*models.Ticket, error)
+	Assign(id int, assigneeID *int) (*models.Ticket, error)
+	AddComment(id int, comment *models.TicketComment) (*models.TicketComment, error)
+}
+
+// Ticket statuses. Tickets are created open; resolved tickets can only be
+// reopened.
+const (
+	StatusOpen       = "open"
+	StatusInProgress = "in_progress"
+	StatusResolved   = "resolved"
+)
+
+// transitions contains the statuses each status can be changed to.
+var transitions = map[string][]string{
+	StatusOpen:       {StatusInProgress, StatusResolved},
+	StatusInProgress: {StatusOpen, StatusResolved},
+	StatusResolved:   {StatusOpen},
+}
+
+// Filter restricts the tickets returned by List. Empty fields match every
+// ticket.
+type Filter struct {
+	ClientID   string
+	Status     string
+	AssigneeID *int
+}
+
+// service keeps the clients' support tickets.
+type service struct {
+	db              *gorm.DB
+	log             logger.Logger
+	mikrotikService mikrotik.Service
+	monitorService  monitor.Service
+	userService     user.Service
+}
+
+// NewService initialization.
+func NewService(db *gorm.DB, log logger.Logger, mikrotikService mikrotik.Service, monitorService monitor.Service, userService user.Service) Service {
+	return &service{
+		db:              db,
+		log:             log,
+		mikrotikService: mikrotikService,
+		monitorService:  monitorService,
+		userService:     userService,
+	}
+}
@@ -0,0 +1,184 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+	"github.com/ab22/stormrage/services/user"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// Number of random bytes that make up a token's secret.
+	secretSize = 32
+
+	// Length of the secret's prefix stored in plain text.
+	prefixSize = 8
+
+	// lastUsedResolution avoids writing to the database on every single
+	// request done with the same token.
+	lastUsedResolution = time.Minute
+)
+
+// generateSecret creates a new random hex encoded secret.
+func generateSecret() (string, error) {
+	b := make([]byte, secretSize)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// hashSecret returns the hex encoded SHA-256 hash of the secret. Secrets are
+// long random strings, so a fast hash is enough and lets us find the token
+// by its hash.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// isValidScope checks if the scope is one of the known scopes.
+func isValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Create generates a new token for the user. The plain text secret is
+// returned only once and it's never stored.
+func (s *service) Create(userID int, name string, scopes []string, expiresAt *time.Time) (*models.APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", services.ErrInvalidArgument("token name is required")
+	}
+
+	for _, scope := range scopes {
+		if !isValidScope(scope) {
+			return nil, "", services.ErrInvalidArgument(fmt.Sprintf("unknown scope [%s]", scope))
+		}
+	}
+
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		return nil, "", services.ErrInvalidArgument("expiration date must be in the future")
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := &models.APIToken{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    secret[:prefixSize],
+		TokenHash: hashSecret(secret),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: expiresAt,
+	}
+
+	if err = s.db.Create(token).Error; err != nil {
+		return nil, "", err
+	}
+
+	return token, secret, nil
+}
+
+// FindByUser returns all of the user's tokens that were not revoked.
+func (s *service) FindByUser(userID int) ([]models.APIToken, error) {
+	tokens := []models.APIToken{}
+
+	err := s.db.
+		Where("user_id = ?", userID).
+		Order("id").
+		Find(&tokens).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Revoke deletes one of the user's tokens. Returns ErrRecordNotFound if the
+// token does not exist or belongs to another user.
+func (s *service) Revoke(userID, tokenID int) error {
+	result := s.db.
+		Where("id = ? AND user_id = ?", tokenID, userID).
+		Delete(&models.APIToken{})
+
+	if err := result.Error; err != nil {
+		return err
+	} else if result.RowsAffected == 0 {
+		return services.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Authenticate searches for the token that matches the secret and returns it
+// along with its owner. Returns nil if the token does not exist, was revoked,
+// already expired or if its owner is no longer active. The token's last used
+// date is updated on success.
+func (s *service) Authenticate(secret string) (*models.APIToken, *models.User, error) {
+	if secret == "" {
+		return nil, nil, nil
+	}
+
+	token := &models.APIToken{}
+
+	err := s.db.
+		Where("token_hash = ?", hashSecret(secret)).
+		First(token).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, nil, err
+		}
+
+		return nil, nil, nil
+	}
+
+	if token.IsExpired() {
+		return nil, nil, nil
+	}
+
+	owner := &models.User{}
+
+	err = s.db.
+		Where("id = ?", token.UserID).
+		First(owner).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, nil, err
+		}
+
+		return nil, nil, nil
+	}
+
+	if owner.Status != int(user.Active) {
+		return nil, nil, nil
+	}
+
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > lastUsedResolution {
+		err = s.db.
+			Model(token).
+			UpdateColumn("last_used_at", now).Error
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return token, owner, nil
+}
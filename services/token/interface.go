@@ -0,0 +1,64 @@
+package token
+
+import (
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/jinzhu/gorm"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	Create(userID int, name string, scopes []string, expiresAt *time.Time) (*models.APIToken, string, error)
+	FindByUser(userID int) ([]models.APIToken, error)
+	Revoke(userID, tokenID int) error
+	Authenticate(secret string) (*models.APIToken, *models.User, error)
+}
+
+// Scopes that can be granted to an API token. Each API route declares the
+// scope that a token needs in order to call it. Routes that don't declare a
+// scope can only be called with a session cookie.
+const (
+	ScopeClientsRead  = "clients:read"
+	ScopeClientsWrite = "clients:write"
+	ScopePPPRead      = "ppp:read"
+	ScopePPPWrite     = "ppp:write"
+	ScopeNetworkRead  = "network:read"
+	ScopeNetworkWrite = "network:write"
+	ScopeHotspotRead  = "hotspot:read"
+	ScopeHotspotWrite = "hotspot:write"
+	ScopeBackups      = "backups"
+	ScopeBillingRead  = "billing:read"
+	ScopeBillingWrite = "billing:write"
+	ScopeTicketsRead  = "tickets:read"
+	ScopeTicketsWrite = "tickets:write"
+)
+
+// Scopes contains all valid scopes.
+var Scopes = []string{
+	ScopeClientsRead,
+	ScopeClientsWrite,
+	ScopePPPRead,
+	ScopePPPWrite,
+	ScopeNetworkRead,
+	ScopeNetworkWrite,
+	ScopeHotspotRead,
+	ScopeHotspotWrite,
+	ScopeBackups,
+	ScopeBillingRead,
+	ScopeBillingWrite,
+	ScopeTicketsRead,
+	ScopeTicketsWrite,
+}
+
+// Contains all of the logic for the APIToken model.
+type service struct {
+	db *gorm.DB
+}
+
+// NewService initialization.
+func NewService(db *gorm.DB) Service {
+	return &service{
+		db: db,
+	}
+}
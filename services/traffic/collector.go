@@ -0,0 +1,185 @@
+package traffic
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+)
+
+// insertBatchSize is the number of samples stored per INSERT statement.
+const insertBatchSize = 500
+
+// run samples the queue counters every Traffic.IntervalSeconds and deletes
+// the samples older than their resolution's retention once an hour.
+func (s *service) run() {
+	var (
+		interval  = time.Duration(s.cfg.Traffic.IntervalSeconds) * time.Second
+		lastPrune time.Time
+	)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		err := s.collect(ctx, time.Now())
+		cancel()
+
+		if err != nil {
+			s.log.Error("traffic: could not sample queues", "error", err)
+		}
+
+		if time.Since(lastPrune) >= time.Hour {
+			if err = s.prune(); err != nil {
+				s.log.Error("traffic: could not delete old samples", "error", err)
+			}
+
+			lastPrune = time.Now()
+		}
+	}
+}
+
+// bucket returns the start of the resolution's bucket that contains t.
+// Daily buckets start at midnight of the server's time zone.
+func bucket(name string, t time.Time) time.Time {
+	switch name {
+	case ResolutionFiveMinutes:
+		return t.Truncate(5 * time.Minute)
+	case ResolutionHourly:
+		return t.Truncate(time.Hour)
+	default:
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// delta returns the bytes counted since the previous sample. If the counter
+// went backwards, it was reset and everything it counted is new.
+func delta(previous, current int64) int64 {
+	if current < previous {
+		return current
+	}
+
+	return current - previous
+}
+
+// collect samples the queue counters and adds the traffic since the
+// previous sample to the buckets of every resolution. The first sample of a
+// queue is only used as the starting point of its counters.
+func (s *service) collect(ctx context.Context, now time.Time) error {
+	stats, err := s.mikrotikService.RequestQueueStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		samples []models.TrafficSample
+		seen    = make(map[string]bool, len(stats))
+	)
+
+	for _, queue := range stats {
+		seen[queue.ID] = true
+
+		previous, ok := s.last[queue.ID]
+		s.last[queue.ID] = counters{
+			upload:   queue.UploadBytes,
+			download: queue.DownloadBytes,
+		}
+
+		if !ok {
+			continue
+		}
+
+		upload := delta(previous.upload, queue.UploadBytes)
+		download := delta(previous.download, queue.DownloadBytes)
+
+		if upload == 0 && download == 0 && queue.UploadRate == 0 && queue.DownloadRate == 0 {
+			continue
+		}
+
+		for _, r := range s.resolutions {
+			samples = append(samples, models.TrafficSample{
+				ClientID:         queue.ID,
+				Resolution:       r.name,
+				Bucket:           bucket(r.name, now),
+				UploadBytes:      upload,
+				DownloadBytes:    download,
+				PeakUploadRate:   queue.UploadRate,
+				PeakDownloadRate: queue.DownloadRate,
+			})
+		}
+	}
+
+	// Forget the queues that were removed.
+	for id := range s.last {
+		if !seen[id] {
+			delete(s.last, id)
+		}
+	}
+
+	for len(samples) > 0 {
+		n := min(len(samples), insertBatchSize)
+
+		if err = s.store(samples[:n]); err != nil {
+			return err
+		}
+
+		samples = samples[n:]
+	}
+
+	return nil
+}
+
+// store adds the samples to the existing buckets, or creates them.
+func (s *service) store(samples []models.TrafficSample) error {
+	var (
+		query strings.Builder
+		args  = make([]interface{}, 0, len(samples)*7)
+	)
+
+	query.WriteString(`INSERT INTO traffic_samples
+		(client_id, resolution, bucket, upload_bytes, download_bytes, peak_upload_rate, peak_download_rate)
+		VALUES `)
+
+	for i, sample := range samples {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+
+		query.WriteString("(?, ?, ?, ?, ?, ?, ?)")
+		args = append(args,
+			sample.ClientID,
+			sample.Resolution,
+			sample.Bucket,
+			sample.UploadBytes,
+			sample.DownloadBytes,
+			sample.PeakUploadRate,
+			sample.PeakDownloadRate,
+		)
+	}
+
+	query.WriteString(`
+		ON CONFLICT (client_id, resolution, bucket) DO UPDATE SET
+			upload_bytes = traffic_samples.upload_bytes + EXCLUDED.upload_bytes,
+			download_bytes = traffic_samples.download_bytes + EXCLUDED.download_bytes,
+			peak_upload_rate = GREATEST(traffic_samples.peak_upload_rate, EXCLUDED.peak_upload_rate),
+			peak_download_rate = GREATEST(traffic_samples.peak_download_rate, EXCLUDED.peak_download_rate)`)
+
+	return s.db.Exec(query.String(), args...).Error
+}
+
+// prune deletes the samples older than their resolution's retention.
+func (s *service) prune() error {
+	for _, r := range s.resolutions {
+		err := s.db.
+			Where("resolution = ? AND bucket < ?", r.name, time.Now().AddDate(0, 0, -r.retentionDays)).
+			Delete(&models.TrafficSample{}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
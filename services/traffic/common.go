@@ -0,0 +1,177 @@
+package traffic
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services"
+)
+
+// resolutionFor returns the finest resolution whose samples are still kept
+// at the date.
+func (s *service) resolutionFor(from time.Time) string {
+	for _, r := range s.resolutions {
+		if from.After(time.Now().AddDate(0, 0, -r.retentionDays)) {
+			return r.name
+		}
+	}
+
+	return ResolutionDaily
+}
+
+// validPeriod checks the period and resolution of a usage query. If the
+// resolution is empty, the finest one available for the period is
+// returned.
+func (s *service) validPeriod(from, to time.Time, resolution string) (string, error) {
+	if !from.Before(to) {
+		return "", services.ErrInvalidArgument("from must be before to")
+	}
+
+	if resolution == "" {
+		return s.resolutionFor(from), nil
+	}
+
+	for _, r := range s.resolutions {
+		if r.name == resolution {
+			return resolution, nil
+		}
+	}
+
+	return "", services.ErrInvalidArgument(fmt.Sprintf("resolution must be %s, %s or %s", ResolutionFiveMinutes, ResolutionHourly, ResolutionDaily))
+}
+
+// Usage returns the client's traffic between the dates, with the samples
+// of the resolution. Only the buckets that start within the period are
+// counted.
+func (s *service) Usage(clientID string, from, to time.Time, resolution string) (*models.ClientUsage, error) {
+	resolution, err := s.validPeriod(from, to, resolution)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &models.ClientUsage{
+		ClientID:   clientID,
+		From:       from,
+		To:         to,
+		Resolution: resolution,
+		Samples:    []models.TrafficSample{},
+	}
+
+	err = s.db.
+		Where("client_id = ? AND resolution = ? AND bucket >= ? AND bucket < ?", clientID, resolution, from, to).
+		Order("bucket").
+		Find(&usage.Samples).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sample := range usage.Samples {
+		usage.UploadBytes += sample.UploadBytes
+		usage.DownloadBytes += sample.DownloadBytes
+	}
+
+	usage.TotalBytes = usage.UploadBytes + usage.DownloadBytes
+	return usage, nil
+}
+
+// Top returns the limit clients that used the most traffic between the
+// dates. The start of the period is rounded down to the start of its bucket,
+// e.g. to midnight when only daily samples are kept, so that the first
+// bucket is counted. The clients' names are added if the router can be
+// queried.
+func (s *service) Top(ctx context.Context, from, to time.Time, limit int) ([]models.ClientUsage, error) {
+	resolution, err := s.validPeriod(from, to, "")
+	if err != nil {
+		return nil, err
+	}
+
+	from = bucket(resolution, from)
+
+	rows, err := s.db.Raw(`
+		SELECT client_id, sum(upload_bytes), sum(download_bytes)
+		FROM traffic_samples
+		WHERE resolution = ? AND bucket >= ? AND bucket < ?
+		GROUP BY client_id
+		ORDER BY sum(upload_bytes + download_bytes) DESC, client_id
+		LIMIT ?`, resolution, from, to, limit).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	top := []models.ClientUsage{}
+
+	for rows.Next() {
+		usage := models.ClientUsage{
+			From:       from,
+			To:         to,
+			Resolution: resolution,
+		}
+
+		if err = rows.Scan(&usage.ClientID, &usage.UploadBytes, &usage.DownloadBytes); err != nil {
+			return nil, err
+		}
+
+		usage.TotalBytes = usage.UploadBytes + usage.DownloadBytes
+		top = append(top, usage)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	clients, err := s.mikrotikService.RequestClients(ctx)
+	if err != nil {
+		s.log.Warn("traffic: could not request client names", "error", err)
+		return top, nil
+	}
+
+	names := make(map[string]string, len(clients))
+	for _, client := range clients {
+		names[client.ID] = client.Name
+	}
+
+	for i := range top {
+		top[i].ClientName = names[top[i].ClientID]
+	}
+
+	return top, nil
+}
+
+// Totals returns the bytes uploaded and downloaded by each client since the
+// date, by client ID. Clients without traffic are left out. Like in Top, the
+// date is rounded down to the start of its bucket.
+func (s *service) Totals(since time.Time) (map[string]int64, error) {
+	resolution := s.resolutionFor(since)
+
+	rows, err := s.db.Raw(`
+		SELECT client_id, sum(upload_bytes + download_bytes)
+		FROM traffic_samples
+		WHERE resolution = ? AND bucket >= ?
+		GROUP BY client_id`, resolution, bucket(resolution, since)).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := map[string]int64{}
+
+	for rows.Next() {
+		var (
+			clientID string
+			total    int64
+		)
+
+		if err = rows.Scan(&clientID, &total); err != nil {
+			return nil, err
+		}
+
+		totals[clientID] = total
+	}
+
+	return totals, rows.Err()
+}
@@ -0,0 +1,75 @@
+package traffic
+
+import (
+	"context"
+	"time"
+
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/models"
+	"github.com/ab22/stormrage/services/mikrotik"
+	"github.com/jinzhu/gorm"
+)
+
+// Service interface describes all functions that must be implemented.
+type Service interface {
+	Usage(clientID string, from, to time.Time, resolution string) (*models.ClientUsage, error)
+	Top(ctx context.Context, from, to time.Time, limit int) ([]models.ClientUsage, error)
+	Totals(since time.Time) (map[string]int64, error)
+}
+
+// Resolutions of the stored samples, from the finest to the coarsest.
+const (
+	ResolutionFiveMinutes = "5m"
+	ResolutionHourly      = "1h"
+	ResolutionDaily       = "1d"
+)
+
+// counters are the byte counters of a queue when it was last sampled.
+type counters struct {
+	upload   int64
+	download int64
+}
+
+// resolution describes how long the samples of a resolution are kept.
+type resolution struct {
+	name          string
+	retentionDays int
+}
+
+// service samples the simple queue counters in the background and keeps
+// the clients' traffic history at several resolutions.
+type service struct {
+	cfg             *config.Config
+	db              *gorm.DB
+	log             logger.Logger
+	mikrotikService mikrotik.Service
+	resolutions     []resolution
+
+	// last contains the counters of each queue, by ID, when it was last
+	// sampled. It's only used by the sampling goroutine.
+	last map[string]counters
+}
+
+// NewService initialization. If traffic accounting is enabled, a goroutine
+// that samples the queue counters every Traffic.IntervalSeconds is started.
+func NewService(cfg *config.Config, db *gorm.DB, log logger.Logger, mikrotikService mikrotik.Service) Service {
+	s := &service{
+		cfg:             cfg,
+		db:              db,
+		log:             log,
+		mikrotikService: mikrotikService,
+		last:            map[string]counters{},
+		resolutions: []resolution{
+			{name: ResolutionFiveMinutes, retentionDays: cfg.Traffic.FiveMinuteRetentionDays},
+			{name: ResolutionHourly, retentionDays: cfg.Traffic.HourlyRetentionDays},
+			{name: ResolutionDaily, retentionDays: cfg.Traffic.DailyRetentionDays},
+		},
+	}
+
+	if cfg.Traffic.IntervalSeconds > 0 {
+		go s.run()
+	}
+
+	return s
+}
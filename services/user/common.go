@@ -7,6 +7,25 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Searches for a User by ID.
+// Returns *models.User instance if it finds it, or nil otherwise.
+func (s *service) FindByID(id int) (*models.User, error) {
+	user := &models.User{}
+
+	err := s.db.
+		Where("id = ?", id).
+		First(user).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+
+	return user, nil
+}
+
 // Searches for a User by Email.
 // Returns *models.User instance if it finds it, or nil otherwise.
 func (s *service) FindByEmail(email string) (*models.User, error) {
@@ -87,6 +106,7 @@ func (s *service) CreateUser(email, password, firstName, lastName string, status
 		FirstName: firstName,
 		LastName:  lastName,
 		Status:    int(status),
+		Role:      RoleOperator,
 	}
 
 	err = s.db.Create(&user).Error
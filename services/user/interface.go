@@ -7,6 +7,7 @@ import (
 
 // Service interface describes all functions that must be implemented.
 type Service interface {
+	FindByID(id int) (*models.User, error)
 	FindByEmail(email string) (*models.User, error)
 	FindByUsername(username string) (*models.User, error)
 	EncryptPassword(password string) ([]byte, error)
@@ -25,6 +26,13 @@ const (
 	Active
 )
 
+// User roles. Admins can call the routes that manage the server itself, such
+// as router backups. Every other user is an operator.
+const (
+	RoleAdmin    = "admin"
+	RoleOperator = "operator"
+)
+
 // Contains all of the logic for the User model.
 type service struct {
 	db *gorm.DB
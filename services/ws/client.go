@@ -1,11 +1,13 @@
 package ws
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/services/mikrotik"
 	"github.com/gorilla/websocket"
 )
 
@@ -32,6 +34,26 @@ const (
 
 	// Client's buffered channel size.
 	messageChannelSize = 16
+
+	// Time between samples of the interface traffic stream.
+	trafficMonitorPeriod = time.Second
+
+	// Time between reads of the router's log when following it.
+	logFollowPeriod = 2 * time.Second
+
+	// Time between samples of the wireless signal stream.
+	signalMonitorPeriod = time.Second
+
+	// Number of past log entries sent when the client starts following the
+	// router's log.
+	logFollowBacklog = 20
+)
+
+// Names of the streams a client can subscribe to.
+const (
+	trafficStream = "traffic"
+	logStream     = "log"
+	signalStream  = "signal"
 )
 
 type WebsocketClient interface {
@@ -47,12 +69,15 @@ type WebsocketClient interface {
 
 // Client contains all information associated with a websocket client conn.
 type websocketClient struct {
-	ID         int
-	conn       *websocket.Conn
-	server     WebsocketServer
-	msgCh      chan []byte
-	closeCh    chan bool
-	pingWriter *pingWriter
+	ID              int
+	conn            *websocket.Conn
+	server          WebsocketServer
+	mikrotikService mikrotik.Service
+	msgCh           chan []byte
+	closeCh         chan bool
+	pingWriter      *pingWriter
+	streams         map[string]*poller
+	streamsMutex    sync.Mutex
 }
 
 // generateClientID increments the global client id in a thread safe way.
@@ -67,7 +92,7 @@ func generateClientID() int {
 
 // NewClient initializes a new Client struct, sets the default read limits and
 // deadlines and creates a Pong Handler for the connection.
-func NewClient(conn *websocket.Conn, server WebsocketServer) WebsocketClient {
+func NewClient(conn *websocket.Conn, server WebsocketServer, mikrotikService mikrotik.Service) WebsocketClient {
 	conn.SetReadLimit(maxMessageSize)
 	conn.SetReadDeadline(time.Now().Add(pongWait))
 	conn.SetPongHandler(func(string) error {
@@ -76,12 +101,14 @@ func NewClient(conn *websocket.Conn, server WebsocketServer) WebsocketClient {
 	})
 
 	return &websocketClient{
-		ID:         generateClientID(),
-		conn:       conn,
-		server:     server,
-		msgCh:      make(chan []byte, messageChannelSize),
-		closeCh:    make(chan bool, 1),
-		pingWriter: nil,
+		ID:              generateClientID(),
+		conn:            conn,
+		server:          server,
+		mikrotikService: mikrotikService,
+		msgCh:           make(chan []byte, messageChannelSize),
+		closeCh:         make(chan bool, 1),
+		pingWriter:      nil,
+		streams:         make(map[string]*poller),
 	}
 }
 
@@ -187,6 +214,7 @@ func (c *websocketClient) onWrite() {
 	ticker := time.NewTicker(pingPeriod)
 	defer func() {
 		c.stopPing()
+		c.stopAllStreams()
 		ticker.Stop()
 		c.conn.Close()
 
@@ -290,10 +318,122 @@ func (c *websocketClient) stopPing() {
 	}
 }
 
+// startStream starts the stream and replaces the client's previous stream
+// with the same name, if any.
+func (c *websocketClient) startStream(p *poller) {
+	c.streamsMutex.Lock()
+	defer c.streamsMutex.Unlock()
+
+	if old, ok := c.streams[p.name]; ok {
+		old.Stop()
+	}
+
+	c.streams[p.name] = p
+	p.Start()
+}
+
+// stopStream stops the client's stream with the specified name.
+func (c *websocketClient) stopStream(name string) {
+	c.streamsMutex.Lock()
+	defer c.streamsMutex.Unlock()
+
+	if p, ok := c.streams[name]; ok {
+		p.Stop()
+		delete(c.streams, name)
+	}
+}
+
+// stopAllStreams stops all of the client's streams.
+func (c *websocketClient) stopAllStreams() {
+	c.streamsMutex.Lock()
+	defer c.streamsMutex.Unlock()
+
+	for name, p := range c.streams {
+		p.Stop()
+		delete(c.streams, name)
+	}
+}
+
+// startTrafficMonitor streams the rx/tx rates of the requested interface.
+func (c *websocketClient) startTrafficMonitor(req *request) {
+	if req.Interface == "" {
+		c.Write([]byte("{ \"error\": \"Invalid interface!\"}"))
+		return
+	}
+
+	name := req.Interface
+	c.startStream(newPoller(c, trafficStream, trafficMonitorPeriod, func(ctx context.Context) (interface{}, error) {
+		return c.mikrotikService.MonitorInterfaceTraffic(ctx, name)
+	}))
+}
+
+// startLogFollow streams the router's new log entries that match the
+// requested topic and text. The last entries are sent first. RouterOS' API
+// follow mode needs a client that reads replies asynchronously, so the log
+// is read periodically and only entries newer than the last one sent are
+// written to the client.
+func (c *websocketClient) startLogFollow(req *request) {
+	filter := mikrotik.LogFilter{
+		Topic: req.Topic,
+		Text:  req.Text,
+	}
+
+	c.startStream(newPoller(c, logStream, logFollowPeriod, func(ctx context.Context) (interface{}, error) {
+		entries, err := c.mikrotikService.RequestLogs(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(entries) == 0 {
+			return nil, nil
+		}
+
+		// Entries older than the last one sent mean that the router
+		// rebooted and started its log over, so the backlog is sent
+		// again.
+		restarted := filter.AfterID != "" &&
+			mikrotik.ParseEntryID(entries[len(entries)-1].ID) < mikrotik.ParseEntryID(filter.AfterID)
+
+		if (filter.AfterID == "" || restarted) && len(entries) > logFollowBacklog {
+			entries = entries[len(entries)-logFollowBacklog:]
+		}
+
+		filter.AfterID = entries[len(entries)-1].ID
+		return entries, nil
+	}))
+}
+
+// startSignalMonitor streams the signal strength, CCQ and rates of the
+// wireless station with the requested MAC address.
+func (c *websocketClient) startSignalMonitor(req *request) {
+	if !req.IsValidMAC() {
+		c.Write([]byte("{ \"error\": \"Invalid MAC address!\"}"))
+		return
+	}
+
+	mac := req.MAC
+	c.startStream(newPoller(c, signalStream, signalMonitorPeriod, func(ctx context.Context) (interface{}, error) {
+		return c.mikrotikService.MonitorWirelessStation(ctx, mac)
+	}))
+}
+
 func (c *websocketClient) processRequest(req *request) {
-	if req.Option == START_PING {
+	switch req.Option {
+	case START_PING:
 		c.startPing(req)
-	} else if req.Option == STOP_PING {
+	case STOP_PING:
 		c.stopPing()
+	case START_TRAFFIC_MONITOR:
+		c.startTrafficMonitor(req)
+	case STOP_TRAFFIC_MONITOR:
+		c.stopStream(trafficStream)
+	case START_LOG_FOLLOW:
+		c.startLogFollow(req)
+	case STOP_LOG_FOLLOW:
+		c.stopStream(logStream)
+	case START_SIGNAL_MONITOR:
+		c.startSignalMonitor(req)
+	case STOP_SIGNAL_MONITOR:
+		c.stopStream(signalStream)
 	}
 }
@@ -7,11 +7,21 @@ type requestOption int
 const (
 	START_PING requestOption = iota
 	STOP_PING
+	START_TRAFFIC_MONITOR
+	STOP_TRAFFIC_MONITOR
+	START_LOG_FOLLOW
+	STOP_LOG_FOLLOW
+	START_SIGNAL_MONITOR
+	STOP_SIGNAL_MONITOR
 )
 
 type request struct {
-	Option requestOption `json:"option"`
-	IP     string        `json:"ip"`
+	Option    requestOption `json:"option"`
+	IP        string        `json:"ip"`
+	Interface string        `json:"interface"`
+	Topic     string        `json:"topic"`
+	Text      string        `json:"text"`
+	MAC       string        `json:"mac"`
 }
 
 func (r *request) IsValidIP() bool {
@@ -19,3 +29,9 @@ func (r *request) IsValidIP() bool {
 
 	return ip != nil
 }
+
+func (r *request) IsValidMAC() bool {
+	_, err := net.ParseMAC(r.MAC)
+
+	return err == nil
+}
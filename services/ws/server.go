@@ -1,9 +1,12 @@
 package ws
 
 import (
-	"log"
 	"net/http"
 
+	"github.com/ab22/stormrage/config"
+	"github.com/ab22/stormrage/handlers/httputils"
+	"github.com/ab22/stormrage/logger"
+	"github.com/ab22/stormrage/services/mikrotik"
 	"github.com/gorilla/websocket"
 )
 
@@ -16,28 +19,34 @@ type WebsocketServer interface {
 
 // Server contains all information to host the websocket server.
 type websocketServer struct {
-	messages       []string
-	clients        map[int]WebsocketClient
-	addClientCh    chan WebsocketClient
-	removeClientCh chan WebsocketClient
-	errorCh        chan error
-	upgrader       websocket.Upgrader
+	messages        []string
+	clients         map[int]WebsocketClient
+	addClientCh     chan WebsocketClient
+	removeClientCh  chan WebsocketClient
+	errorCh         chan error
+	upgrader        websocket.Upgrader
+	log             logger.Logger
+	mikrotikService mikrotik.Service
 }
 
-// NewServer initializes a new Client struct.
-func NewServer() WebsocketServer {
+// NewServer initializes a new Client struct. Websocket connections are only
+// accepted from the server's own origin or from the configured allowed
+// origins.
+func NewServer(cfg *config.Config, log logger.Logger, mikrotikService mikrotik.Service) WebsocketServer {
 	server := &websocketServer{
-		messages:       []string{},
-		clients:        make(map[int]WebsocketClient),
-		addClientCh:    make(chan WebsocketClient),
-		removeClientCh: make(chan WebsocketClient),
-		errorCh:        make(chan error),
+		messages:        []string{},
+		clients:         make(map[int]WebsocketClient),
+		addClientCh:     make(chan WebsocketClient),
+		removeClientCh:  make(chan WebsocketClient),
+		errorCh:         make(chan error),
+		log:             log,
+		mikrotikService: mikrotikService,
 
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
 			CheckOrigin: func(r *http.Request) bool {
-				return true
+				return httputils.IsAllowedOrigin(r, cfg.AllowedOrigins)
 			},
 		},
 	}
@@ -72,10 +81,12 @@ func (s *websocketServer) OnConnect(w http.ResponseWriter, r *http.Request) erro
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 
 	if err != nil {
-		return err
+		// The upgrader already wrote the error response to the client.
+		logger.FromContext(r.Context(), s.log).Warn("websocket upgrade failed", "origin", r.Header.Get("Origin"), "error", err)
+		return nil
 	}
 
-	client := NewClient(conn, s)
+	client := NewClient(conn, s, s.mikrotikService)
 	s.AddClient(client)
 	client.Listen()
 	s.RemoveClient(client)
@@ -95,19 +106,19 @@ func (s *websocketServer) Listen() {
 			s.removeClient(client)
 
 		case err := <-s.errorCh:
-			log.Println("server error channel:", err.Error())
+			s.log.Error("websocket server error", "error", err)
 		}
 	}
 }
 
 func (s *websocketServer) addClient(client WebsocketClient) {
 	s.clients[client.GetID()] = client
-	log.Println("Client has joined the channel!")
+	s.log.Info("websocket client joined", "client_id", client.GetID(), "clients", len(s.clients))
 }
 
 func (s *websocketServer) removeClient(client WebsocketClient) {
 	delete(s.clients, client.GetID())
-	log.Println("Client has left the channel!")
+	s.log.Info("websocket client left", "client_id", client.GetID(), "clients", len(s.clients))
 }
 
 func (s *websocketServer) broadcastMessage(message []byte) {
@@ -0,0 +1,98 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// streamMessage is the message sent to the client for each value produced
+// by a stream. Type identifies the stream that produced the message.
+type streamMessage struct {
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// pollFunc returns the next value of a stream. If there's no new value to
+// send, it must return a nil interface{}.
+type pollFunc func(ctx context.Context) (interface{}, error)
+
+// poller streams values to the client by calling a pollFunc every interval
+// until it's stopped. It's used to follow router data that the RouterOS API
+// client can only read as single replies.
+type poller struct {
+	client   WebsocketClient
+	name     string
+	interval time.Duration
+	poll     pollFunc
+	cancel   context.CancelFunc
+}
+
+func newPoller(client WebsocketClient, name string, interval time.Duration, poll pollFunc) *poller {
+	return &poller{
+		client:   client,
+		name:     name,
+		interval: interval,
+		poll:     poll,
+	}
+}
+
+// Start spawns the goroutine that polls and writes the values to the client.
+func (p *poller) Start() {
+	var ctx context.Context
+
+	ctx, p.cancel = context.WithCancel(context.Background())
+	go p.run(ctx)
+}
+
+// Stop ends the polling goroutine.
+func (p *poller) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+func (p *poller) run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		msg := streamMessage{Type: p.name}
+
+		value, err := p.poll(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			msg.Error = err.Error()
+			p.client.LogError(fmt.Errorf("%s stream: %v", p.name, err))
+		} else {
+			msg.Payload = value
+		}
+
+		if (err != nil || value != nil) && !p.write(msg) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// write encodes the message and sends it to the client. Returns false if the
+// client could not receive it.
+func (p *poller) write(msg streamMessage) bool {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		p.client.LogError(fmt.Errorf("%s stream: error encoding message: %v", p.name, err))
+		return false
+	}
+
+	return p.client.WriteAndWait(data)
+}